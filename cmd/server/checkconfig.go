@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+// Exit codes for check-config, so CI can tell errors from warnings.
+const (
+	checkOK       = 0
+	checkInvalid  = 1
+	checkWarnings = 2
+)
+
+// runCheckConfig implements `ecmwf-dash check-config [-online] [config.yaml]`.
+// It prints every problem found and returns the process exit code.
+func runCheckConfig(args []string) int {
+	fs := flag.NewFlagSet("check-config", flag.ExitOnError)
+	online := fs.Bool("online", false, "also verify that each repository and branch exists on GitHub")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s check-config [-online] [config.yaml]\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Exit status is %d if the config is valid, %d if it is invalid and %d if it only has warnings.\n\n", checkOK, checkInvalid, checkWarnings)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	path := "config.yaml"
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	cfg, warnings, err := config.Check(path, config.Options{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		return checkInvalid
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "%s: warning: %s\n", path, w)
+	}
+
+	if *online {
+		problems, err := verifyOnline(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: online check failed: %v\n", path, err)
+			return checkInvalid
+		}
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "%s: error: %s\n", path, p)
+		}
+		if len(problems) > 0 {
+			return checkInvalid
+		}
+	}
+
+	if len(warnings) > 0 {
+		return checkWarnings
+	}
+
+	fmt.Printf("%s: OK (%d repositories, ~%d API requests/hour)\n", path, len(cfg.GitHub.Repositories), cfg.EstimateHourlyRequests())
+	return checkOK
+}
+
+func verifyOnline(cfg *config.Config) ([]string, error) {
+	gh, err := github.NewClient(cfg.GitHub.Token)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return gh.VerifyRepositories(ctx, cfg.GitHub.Organization, cfg.GitHub.Repositories)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ozaq/ecmwf-dash/internal/auth"
+)
+
+// runKioskToken implements `ecmwf-dash kiosk-token -name NAME [-repos a,b]`.
+// It prints a new token and the config entry holding only its hash, so the
+// secret itself never has to be stored.
+func runKioskToken(args []string) int {
+	fs := flag.NewFlagSet("kiosk-token", flag.ExitOnError)
+	name := fs.String("name", "", "name shown on the admin page, e.g. the screen's location (required)")
+	repos := fs.String("repos", "", "comma-separated repositories the token may see (default: all)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s kiosk-token -name NAME [-repos a,b]\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if *name == "" {
+		fs.Usage()
+		return 1
+	}
+
+	entry := struct {
+		Name        string   `yaml:"name"`
+		TokenSHA256 string   `yaml:"token_sha256"`
+		Repos       []string `yaml:"repos,omitempty,flow"`
+	}{Name: *name}
+	for _, r := range strings.Split(*repos, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			entry.Repos = append(entry.Repos, r)
+		}
+	}
+
+	token := auth.GenerateKioskToken()
+	entry.TokenSHA256 = auth.HashKioskToken(token)
+	snippet, err := yaml.Marshal([]any{entry})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "encoding config entry: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Token (shown once, not stored anywhere):\n\n    %s\n\n", token)
+	fmt.Printf("Open on the screen:\n\n    /builds-dashboard?%s=%s\n\n", auth.KioskParam, token)
+	fmt.Printf("Add to config.yaml under auth.kiosk_tokens:\n\n")
+	for _, line := range strings.Split(strings.TrimRight(string(snippet), "\n"), "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+	return 0
+}
@@ -3,35 +3,70 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"html/template"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
 	"syscall"
 	"time"
 
+	"github.com/ozaq/ecmwf-dash/internal/auth"
 	"github.com/ozaq/ecmwf-dash/internal/config"
 	"github.com/ozaq/ecmwf-dash/internal/fetcher"
 	"github.com/ozaq/ecmwf-dash/internal/github"
 	"github.com/ozaq/ecmwf-dash/internal/handlers"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
 	"github.com/ozaq/ecmwf-dash/internal/storage"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
+	"github.com/ozaq/ecmwf-dash/internal/visibility"
 )
 
 var Version = "dev"
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "check-config":
+			os.Exit(runCheckConfig(os.Args[2:]))
+		case "kiosk-token":
+			os.Exit(runKioskToken(os.Args[2:]))
+		}
+	}
+
+	configPath := flag.String("config", "config.yaml", "path to the config file")
+	listen := flag.String("listen", "", "listen address host:port (overrides server.host/server.port)")
+	logFormat := flag.String("log-format", "", "log output format: text or json (overrides log.format)")
+	flag.Parse()
+
+	opts := config.Options{Listen: *listen, LogFormat: *logFormat}
+
 	// Load config
-	cfg, err := config.Load("config.yaml")
+	cfg, err := config.LoadWithOptions(*configPath, opts)
 	if err != nil {
-		log.Fatal("Failed to load config:", err)
+		fatal("failed to load config", err)
 	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log.Format, cfg.Log.Level))
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, Version)
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("flushing traces failed", "error", err)
+		}
+	}()
 
 	// Create GitHub client
-	gh, err := github.NewClient()
+	gh, err := github.NewClient(cfg.GitHub.Token)
 	if err != nil {
-		log.Fatal("Failed to create GitHub client:", err)
+		fatal("failed to create GitHub client", err)
 	}
 
 	// Create storage
@@ -53,56 +88,184 @@ func main() {
 
 	issuesTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/dashboard.html")
 	if err != nil {
-		log.Fatal("Failed to load dashboard template:", err)
+		fatal("failed to load dashboard template", err)
 	}
 
 	prsTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/pullrequests.html")
 	if err != nil {
-		log.Fatal("Failed to load pull requests template:", err)
+		fatal("failed to load pull requests template", err)
 	}
 
 	buildsTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/builds.html")
 	if err != nil {
-		log.Fatal("Failed to load builds template:", err)
+		fatal("failed to load builds template", err)
 	}
 
 	dashboardTmpl, err := template.New("builds_dashboard.html").Funcs(handlers.TemplateFuncs()).ParseFiles("web/templates/builds_dashboard.html", "web/templates/builds.html")
 	if err != nil {
-		log.Fatal("Failed to load builds dashboard template:", err)
+		fatal("failed to load builds dashboard template", err)
 	}
 
-	// Extract configured repo names and per-repo branch config
-	repoNames := make([]string, len(cfg.GitHub.Repositories))
-	repoConfig := make([]handlers.RepoBranches, len(cfg.GitHub.Repositories))
-	for i, repo := range cfg.GitHub.Repositories {
-		repoNames[i] = repo.Name
-		repoConfig[i] = handlers.RepoBranches{Name: repo.Name, Branches: repo.Branches}
+	kioskTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/kiosk_tokens.html")
+	if err != nil {
+		fatal("failed to load kiosk tokens template", err)
+	}
+
+	trendsTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/trends.html")
+	if err != nil {
+		fatal("failed to load trends template", err)
+	}
+
+	flakyTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/flaky.html")
+	if err != nil {
+		fatal("failed to load flaky checks template", err)
+	}
+
+	checkTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/check.html")
+	if err != nil {
+		fatal("failed to load check detail template", err)
+	}
+
+	graphTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/graph.html")
+	if err != nil {
+		fatal("failed to load dependency graph template", err)
+	}
+
+	branchesTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/branches.html")
+	if err != nil {
+		fatal("failed to load branch divergence template", err)
+	}
+
+	releaseTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/release.html")
+	if err != nil {
+		fatal("failed to load release readiness template", err)
+	}
+
+	repoTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/repo.html")
+	if err != nil {
+		fatal("failed to load repository template", err)
+	}
+
+	planningTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/planning.html")
+	if err != nil {
+		fatal("failed to load planning template", err)
+	}
+
+	queueTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/queue.html")
+	if err != nil {
+		fatal("failed to load merge queue template", err)
+	}
+
+	deploymentsTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/deployments.html")
+	if err != nil {
+		fatal("failed to load deployments template", err)
+	}
+
+	releasesTmpl, err := template.New("base.html").Funcs(handlers.TemplateFuncs()).ParseFiles(basePath, "web/templates/releases.html")
+	if err != nil {
+		fatal("failed to load releases template", err)
+	}
+
+	// Optional OIDC login in front of everything except public endpoints
+	var authn *auth.Authenticator
+	var kiosks handlers.KioskStatusSource
+	if cfg.Auth.Enabled {
+		authn, err = auth.New(ctx, cfg.Auth)
+		if err != nil {
+			fatal("failed to set up authentication", err)
+		}
+		kiosks = authn
+		slog.Info("OIDC authentication enabled", "issuer", cfg.Auth.Issuer, "kiosk_tokens", len(cfg.Auth.KioskTokens))
+	}
+
+	// Per-viewer filtering of private repositories needs viewers' GitHub
+	// tokens; keeping private repositories out of kiosk feeds does not
+	var repoVisibility handlers.RepoVisibility
+	var publicRepos handlers.PublicRepos
+	if authn != nil {
+		checker := visibility.New(gh, func(token string) (visibility.RepoAccessor, error) {
+			return github.NewClient(token)
+		}, cfg.Auth.VisibilityCacheTTL)
+		publicRepos = checker
+		if cfg.Auth.GitHubOAuth.ClientID != "" {
+			repoVisibility = checker
+			slog.Info("per-viewer repository visibility enabled")
+		}
 	}
 
 	// Create handler
+	settings := repoSettings(cfg)
 	handler := handlers.New(handlers.HandlerConfig{
-		Store:         store,
-		IssuesTmpl:    issuesTmpl,
-		PRsTmpl:       prsTmpl,
-		BuildTmpl:     buildsTmpl,
-		DashboardTmpl: dashboardTmpl,
-		Organization:  cfg.GitHub.Organization,
-		Version:       Version,
-		RepoNames:     repoNames,
-		RepoConfig:    repoConfig,
-		FetchIntervals: handlers.FetchIntervals{
-			Issues:       cfg.FetchIntervals.Issues,
-			PullRequests: cfg.FetchIntervals.PullRequests,
-			Actions:      cfg.FetchIntervals.Actions,
-		},
+		Store:           store,
+		IssuesTmpl:      issuesTmpl,
+		PRsTmpl:         prsTmpl,
+		BuildTmpl:       buildsTmpl,
+		DashboardTmpl:   dashboardTmpl,
+		KioskTmpl:       kioskTmpl,
+		TrendsTmpl:      trendsTmpl,
+		FlakyTmpl:       flakyTmpl,
+		CheckTmpl:       checkTmpl,
+		GraphTmpl:       graphTmpl,
+		BranchesTmpl:    branchesTmpl,
+		ReleaseTmpl:     releaseTmpl,
+		RepoTmpl:        repoTmpl,
+		PlanningTmpl:    planningTmpl,
+		QueueTmpl:       queueTmpl,
+		DeploymentsTmpl: deploymentsTmpl,
+		ReleasesTmpl:    releasesTmpl,
+		Kiosks:          kiosks,
+		Visibility:      repoVisibility,
+		PublicRepos:     publicRepos,
+		Organization:    settings.Organization,
+		Version:         Version,
+		RepoNames:       settings.RepoNames,
+		RepoConfig:      settings.RepoConfig,
+		FetchIntervals:  settings.FetchIntervals,
+		Trends:          settings.Trends,
+	})
+
+	// Hot-reload config on SIGHUP or when the file changes on disk
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	current := cfg // only touched by the Watch goroutine
+	go config.Watch(ctx, *configPath, opts, 5*time.Second, hupChan, func(newCfg *config.Config) {
+		if newCfg.Server != current.Server || newCfg.Log != current.Log || newCfg.Tracing != current.Tracing ||
+			newCfg.GitHub.Token != current.GitHub.Token || authChanged(current.Auth, newCfg.Auth) {
+			slog.Warn("config reload: server, log, tracing, auth or token settings changed; restart required for them to take effect")
+		}
+		current = newCfg
+		if authn != nil {
+			authn.UpdateAccess(newCfg.Auth)
+		}
+		f.UpdateConfig(newCfg)
+		newSettings := repoSettings(newCfg)
+		handler.UpdateSettings(newSettings)
+		store.RetainRepos(newSettings.RepoNames)
+		slog.Info("config reloaded", "org", newSettings.Organization, "repos", len(newSettings.RepoNames))
 	})
 
 	// Setup routes
 	mux := http.NewServeMux()
 	mux.HandleFunc("/builds", handler.BuildStatus)
+	mux.HandleFunc("/builds.atom", handler.BuildsFeed)
+	mux.HandleFunc("/builds/trends", handler.BuildTrends)
+	mux.HandleFunc("/builds/check", handler.CheckDetail)
+	mux.HandleFunc("/flaky", handler.FlakyChecks)
+	mux.HandleFunc("/graph", handler.DependencyGraph)
+	mux.HandleFunc("/branches", handler.BranchDivergence)
+	mux.HandleFunc("/repo/{name}", handler.RepoDetail)
+	mux.HandleFunc("/repo/{name}/release", handler.RepoRelease)
+	mux.HandleFunc("/repo/{name}/queue", handler.RepoQueue)
 	mux.HandleFunc("/builds-dashboard", handler.BuildsDashboard)
 	mux.HandleFunc("/pulls", handler.PullRequests)
+	mux.HandleFunc("/pulls.atom", handler.PullRequestsFeed)
 	mux.HandleFunc("/issues", handler.Dashboard)
+	mux.HandleFunc("/issues.atom", handler.IssuesFeed)
+	mux.HandleFunc("/planning", handler.Planning)
+	mux.HandleFunc("/deployments", handler.Deployments)
+	mux.HandleFunc("/releases", handler.Releases)
+	mux.HandleFunc("/releases.atom", handler.ReleasesFeed)
+	mux.HandleFunc(auth.AdminKioskPath, handler.KioskTokens)
 	mux.Handle("/static/", http.StripPrefix("/static/", cacheControl(http.FileServer(http.Dir("web/static")))))
 
 	// Health endpoint
@@ -122,7 +285,7 @@ func main() {
 				"checks": store.RepoFetchTimes(storage.CategoryChecks),
 			},
 		}); err != nil {
-			log.Printf("Error encoding health response: %v", err)
+			logging.FromContext(r.Context()).Error("encoding health response failed", "error", err)
 		}
 	})
 
@@ -136,11 +299,16 @@ func main() {
 		handler.BuildStatus(w, r)
 	})
 
-	wrapped := securityHeaders(logMiddleware(mux))
+	app := tracing.Routes(mux)
+	if authn != nil {
+		app = authn.Wrap(app)
+	}
+
+	wrapped := securityHeaders(tracing.Handler(logging.Middleware(app)))
 
 	// Start server with timeouts (HS1)
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	log.Printf("Starting server on %s", addr)
+	slog.Info("starting server", "addr", addr, "version", Version)
 
 	server := &http.Server{
 		Addr:         addr,
@@ -153,26 +321,70 @@ func main() {
 	// Graceful shutdown; second signal force-quits
 	go func() {
 		<-sigChan
-		log.Println("Shutting down...")
-		go func() { <-sigChan; log.Println("Forced shutdown"); os.Exit(1) }()
+		slog.Info("shutting down")
+		go func() { <-sigChan; slog.Warn("forced shutdown"); os.Exit(1) }()
 		cancel()
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer shutdownCancel()
 		if err := server.Shutdown(shutdownCtx); err != nil {
-			log.Printf("Error during shutdown: %v", err)
+			slog.Error("shutdown failed", "error", err)
 		}
 	}()
 
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatal("Server error:", err)
+		fatal("server error", err)
 	}
 }
 
-func logMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("%s %s %s", r.RemoteAddr, r.Method, r.URL.Path)
-		next.ServeHTTP(w, r)
-	})
+// fatal logs err and exits with status 1.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// repoSettings extracts the reloadable handler settings from cfg.
+func repoSettings(cfg *config.Config) handlers.RepoSettings {
+	repoNames := make([]string, len(cfg.GitHub.Repositories))
+	repoConfig := make([]handlers.RepoBranches, len(cfg.GitHub.Repositories))
+	for i, repo := range cfg.GitHub.Repositories {
+		repoNames[i] = repo.Name
+		repoConfig[i] = handlers.RepoBranches{Name: repo.Name, Branches: repo.Branches, DependsOn: repo.DependsOn, ReleaseBranch: repo.ReleaseBranchName()}
+	}
+	return handlers.RepoSettings{
+		Organization: cfg.GitHub.Organization,
+		RepoNames:    repoNames,
+		RepoConfig:   repoConfig,
+		FetchIntervals: handlers.FetchIntervals{
+			Issues:       cfg.FetchIntervals.Issues,
+			PullRequests: cfg.FetchIntervals.PullRequests,
+			Actions:      cfg.FetchIntervals.Actions,
+			Branches:     fetcher.BranchesInterval(cfg),
+			Releases:     fetcher.ReleasesInterval(cfg),
+			Planning:     fetcher.PlanningInterval(cfg),
+			Deployments:  fetcher.DeploymentsInterval(cfg),
+		},
+		Trends: trendSettings(cfg.Trends),
+	}
+}
+
+// trendSettings applies the trend defaults.
+func trendSettings(t config.TrendsConfig) handlers.TrendSettings {
+	s := handlers.TrendSettings{Runs: t.Runs, RegressionThreshold: t.RegressionThreshold}
+	if s.Runs == 0 {
+		s.Runs = config.DefaultTrendRuns
+	}
+	if s.RegressionThreshold == 0 {
+		s.RegressionThreshold = config.DefaultRegressionThreshold
+	}
+	return s
+}
+
+// authChanged reports whether auth settings that need a restart differ.
+// Kiosk tokens and admins are applied on reload.
+func authChanged(prev, next config.AuthConfig) bool {
+	prev.KioskTokens, next.KioskTokens = nil, nil
+	prev.Admins, next.Admins = nil, nil
+	return !reflect.DeepEqual(prev, next)
 }
 
 func cacheControl(next http.Handler) http.Handler {
@@ -0,0 +1,457 @@
+// Package auth puts the dashboard behind OIDC single sign-on: the
+// authorization code flow with PKCE, HMAC-signed session cookies and an
+// allowlist of orgs, teams and email domains.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+)
+
+// Routes served by the Authenticator itself.
+const (
+	LoginPath    = "/auth/login"
+	CallbackPath = "/auth/callback"
+	LogoutPath   = "/auth/logout"
+
+	// AdminKioskPath lists kiosk tokens; only auth.admins may view it.
+	AdminKioskPath = "/kiosk-tokens"
+)
+
+const (
+	sessionCookie = "ecmwf_dash_session"
+	loginCookie   = "ecmwf_dash_login"
+	kioskCookie   = "ecmwf_dash_kiosk"
+
+	// KioskParam is the query parameter carrying a kiosk token.
+	KioskParam = "kiosk"
+
+	loginStateTTL     = 10 * time.Minute
+	kioskCookieMaxAge = 365 * 24 * time.Hour
+
+	defaultSessionTTL  = 12 * time.Hour
+	defaultGroupsClaim = "groups"
+)
+
+var defaultScopes = []string{"openid", "email", "profile", "groups"}
+
+// User is the signed-in user, available to handlers via UserFromContext.
+type User struct {
+	Subject string
+	Email   string
+	Name    string
+
+	// GitHubToken is the viewer's own GitHub token when auth.github_oauth
+	// is configured and they linked their account; empty otherwise.
+	GitHubToken string
+}
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying u.
+func WithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFromContext returns the user attached by the Authenticator.
+func UserFromContext(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey{}).(User)
+	return u, ok
+}
+
+// Authenticator handles the login flow and guards every other route.
+type Authenticator struct {
+	cfg         config.AuthConfig
+	oauth       oauth2.Config
+	provider    *providerMetadata
+	keys        *keySet
+	cookies     signer
+	client      *http.Client
+	groupsClaim string
+	sessionTTL  time.Duration
+	secure      bool // set the Secure flag on cookies
+	now         func() time.Time
+	access      access
+	github      *oauth2.Config // nil unless auth.github_oauth is configured
+	viewers     viewerTokens
+}
+
+// New discovers the issuer's endpoints and returns an Authenticator for cfg.
+// It fails if the issuer cannot be reached, so a misconfigured issuer is
+// caught at startup rather than on the first login.
+func New(ctx context.Context, cfg config.AuthConfig) (*Authenticator, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	provider, err := discover(ctx, client, cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+	a := &Authenticator{
+		cfg: cfg,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  provider.AuthorizationEndpoint,
+				TokenURL: provider.TokenEndpoint,
+			},
+		},
+		provider:    provider,
+		keys:        &keySet{client: client, uri: provider.JWKSURI},
+		cookies:     signer{key: []byte(cfg.SessionSecret)},
+		client:      client,
+		groupsClaim: cfg.GroupsClaim,
+		sessionTTL:  cfg.SessionTTL,
+		secure:      strings.HasPrefix(cfg.RedirectURL, "https://"),
+		now:         time.Now,
+		github:      newGitHubOAuth(cfg.GitHubOAuth),
+	}
+	if a.groupsClaim == "" {
+		a.groupsClaim = defaultGroupsClaim
+	}
+	if a.sessionTTL == 0 {
+		a.sessionTTL = defaultSessionTTL
+	}
+	a.access.update(cfg)
+	return a, nil
+}
+
+// isPublic reports whether path is served without any credentials.
+func isPublic(path string) bool {
+	return path == "/health" || path == "/metrics" || strings.HasPrefix(path, "/badge/")
+}
+
+// isKioskPath reports whether path may be served to a kiosk token holder:
+// the TV dashboard, its assets and the Atom feeds, which feed readers
+// cannot sign in to.
+func isKioskPath(path string) bool {
+	return path == "/builds-dashboard" || strings.HasPrefix(path, "/static/") || strings.HasSuffix(path, ".atom")
+}
+
+// Wrap serves the /auth/ routes and requires a valid session for
+// everything else, except public paths and kiosk-token access to the TV
+// dashboard and feeds. The kiosk token admin page additionally requires an admin.
+func (a *Authenticator) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LoginPath:
+			a.beginLogin(w, r, safeReturnTo(r.URL.Query().Get("next")))
+			return
+		case CallbackPath:
+			a.callback(w, r)
+			return
+		case LogoutPath:
+			a.logout(w, r)
+			return
+		case GitHubCallbackPath:
+			if a.github != nil {
+				a.githubCallback(w, r)
+				return
+			}
+		}
+
+		if isPublic(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if u, ok := a.sessionUser(r); ok {
+			if r.URL.Path == AdminKioskPath && !a.access.isAdmin(u.Email) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			if a.github != nil && !strings.HasPrefix(r.URL.Path, "/static/") {
+				token, linked := a.viewers.get(u.Subject, a.now())
+				readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead
+				if readOnly && (!linked || r.URL.Path == GitHubLinkPath) {
+					returnTo := r.URL.RequestURI()
+					if r.URL.Path == GitHubLinkPath {
+						returnTo = safeReturnTo(r.URL.Query().Get("next"))
+					}
+					a.beginGitHubLink(w, r, returnTo)
+					return
+				}
+				u.GitHubToken = token
+			}
+			next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), u)))
+			return
+		}
+		if isKioskPath(r.URL.Path) {
+			if k, ok := a.kiosk(w, r); ok {
+				next.ServeHTTP(w, r.WithContext(WithKiosk(r.Context(), k)))
+				return
+			}
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		a.beginLogin(w, r, r.URL.RequestURI())
+	})
+}
+
+// sessionUser returns the user from a valid, unexpired session cookie.
+func (a *Authenticator) sessionUser(r *http.Request) (User, bool) {
+	s, ok := a.session(r)
+	if !ok {
+		return User{}, false
+	}
+	return User{Subject: s.Subject, Email: s.Email, Name: s.Name}, true
+}
+
+// session returns the valid, unexpired session cookie's payload.
+func (a *Authenticator) session(r *http.Request) (session, bool) {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return session{}, false
+	}
+	var s session
+	if err := a.cookies.decode(sessionCookie, c.Value, &s); err != nil {
+		return session{}, false
+	}
+	if a.now().Unix() >= s.Expiry {
+		return session{}, false
+	}
+	return s, true
+}
+
+func tokenEqual(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// beginLogin redirects to the provider's authorization endpoint, stashing
+// state, nonce and PKCE verifier in a signed cookie for the callback.
+func (a *Authenticator) beginLogin(w http.ResponseWriter, r *http.Request, returnTo string) {
+	st, ok := a.startState(w, r, loginCookie, returnTo)
+	if !ok {
+		return
+	}
+	authURL := a.oauth.AuthCodeURL(st.State,
+		oauth2.S256ChallengeOption(st.Verifier),
+		oauth2.SetAuthURLParam("nonce", st.Nonce),
+	)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// startState creates a fresh login state and stores it in the named signed
+// cookie. On failure it writes an error response and returns false.
+func (a *Authenticator) startState(w http.ResponseWriter, r *http.Request, cookie, returnTo string) (loginState, bool) {
+	st := loginState{
+		State:    randomString(),
+		Nonce:    randomString(),
+		Verifier: oauth2.GenerateVerifier(),
+		ReturnTo: returnTo,
+		Expiry:   a.now().Add(loginStateTTL).Unix(),
+	}
+	value, err := a.cookies.encode(cookie, st)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("encoding login state failed", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return st, false
+	}
+	a.setCookie(w, cookie, value, loginStateTTL)
+	return st, true
+}
+
+// callback completes the code flow: it checks state, redeems the code with
+// the PKCE verifier, verifies the ID token, applies the allowlist and
+// issues the session cookie.
+func (a *Authenticator) callback(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	q := r.URL.Query()
+
+	if e := q.Get("error"); e != "" {
+		logger.Warn("login failed at provider", "error", e, "description", q.Get("error_description"))
+		http.Error(w, "login failed: "+e, http.StatusForbidden)
+		return
+	}
+
+	st, err := a.loginState(r, loginCookie)
+	a.clearCookie(w, loginCookie)
+	if err != nil {
+		logger.Warn("login callback rejected", "error", err)
+		http.Error(w, "login expired or invalid, please try again", http.StatusBadRequest)
+		return
+	}
+	if !tokenEqual(q.Get("state"), st.State) {
+		logger.Warn("login callback rejected", "error", "state mismatch")
+		http.Error(w, "login expired or invalid, please try again", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), oauth2.HTTPClient, a.client)
+	tok, err := a.oauth.Exchange(ctx, q.Get("code"), oauth2.VerifierOption(st.Verifier))
+	if err != nil {
+		logger.Error("code exchange failed", "error", err)
+		http.Error(w, "login failed", http.StatusBadGateway)
+		return
+	}
+	rawID, _ := tok.Extra("id_token").(string)
+	if rawID == "" {
+		logger.Error("token response has no id_token")
+		http.Error(w, "login failed", http.StatusBadGateway)
+		return
+	}
+	claims, err := a.verifyIDToken(ctx, rawID, st.Nonce)
+	if err != nil {
+		logger.Warn("ID token rejected", "error", err)
+		http.Error(w, "login failed", http.StatusForbidden)
+		return
+	}
+
+	if !a.allowed(claims) {
+		logger.Warn("login denied by allowlist", "sub", claims.Subject, "email", claims.Email, "groups", claims.Groups)
+		http.Error(w, "You are not authorised to view this dashboard.", http.StatusForbidden)
+		return
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.Username
+	}
+	value, err := a.cookies.encode(sessionCookie, session{
+		Subject: claims.Subject,
+		Email:   claims.Email,
+		Name:    name,
+		Expiry:  a.now().Add(a.sessionTTL).Unix(),
+	})
+	if err != nil {
+		logger.Error("encoding session failed", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(w, sessionCookie, value, a.sessionTTL)
+	logger.Info("user signed in", "sub", claims.Subject, "email", claims.Email)
+
+	http.Redirect(w, r, st.ReturnTo, http.StatusFound)
+}
+
+func (a *Authenticator) loginState(r *http.Request, cookie string) (loginState, error) {
+	var st loginState
+	c, err := r.Cookie(cookie)
+	if err != nil {
+		return st, errors.New("no login in progress")
+	}
+	if err := a.cookies.decode(cookie, c.Value, &st); err != nil {
+		return st, err
+	}
+	if a.now().Unix() >= st.Expiry {
+		return st, errors.New("login state expired")
+	}
+	return st, nil
+}
+
+// allowed applies the org/team/email-domain allowlist. An empty allowlist
+// admits everyone the issuer authenticated.
+func (a *Authenticator) allowed(c *idClaims) bool {
+	if len(a.cfg.AllowedOrgs)+len(a.cfg.AllowedTeams)+len(a.cfg.AllowedEmailDomains) == 0 {
+		return true
+	}
+
+	if at := strings.LastIndex(c.Email, "@"); at >= 0 && (c.EmailVerified == nil || *c.EmailVerified) {
+		domain := c.Email[at+1:]
+		for _, d := range a.cfg.AllowedEmailDomains {
+			if strings.EqualFold(domain, d) {
+				return true
+			}
+		}
+	}
+
+	for _, g := range c.Groups {
+		for _, org := range a.cfg.AllowedOrgs {
+			if strings.EqualFold(g, org) || hasPrefixFold(g, org+":") {
+				return true
+			}
+		}
+		for _, team := range a.cfg.AllowedTeams {
+			if strings.EqualFold(g, team) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// logout clears the session and, if the provider supports it, ends the
+// provider session too.
+func (a *Authenticator) logout(w http.ResponseWriter, r *http.Request) {
+	if u, ok := a.sessionUser(r); ok {
+		a.viewers.forget(u.Subject)
+	}
+	a.clearCookie(w, sessionCookie)
+	if a.provider.EndSessionEndpoint != "" {
+		http.Redirect(w, r, a.provider.EndSessionEndpoint, http.StatusFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "Signed out.")
+}
+
+func (a *Authenticator) setCookie(w http.ResponseWriter, name, value string, maxAge time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(maxAge.Seconds()),
+		Secure:   a.secure,
+		HttpOnly: true,
+		// Lax, not Strict: the callback is a cross-site top-level redirect
+		// from the provider and must still carry the login cookie.
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (a *Authenticator) clearCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   a.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// safeReturnTo limits post-login redirects to local paths, so /auth/login
+// cannot be used as an open redirect.
+func safeReturnTo(next string) string {
+	if next == "" || !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	if u, err := url.Parse(next); err != nil || u.Host != "" || u.Scheme != "" {
+		return "/"
+	}
+	return next
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand failed: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
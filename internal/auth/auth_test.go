@@ -0,0 +1,329 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+// testApp runs the Authenticator in front of a handler that echoes the
+// signed-in user, against a mock provider.
+type testApp struct {
+	provider *mockProvider
+	srv      *httptest.Server
+	authn    *Authenticator
+}
+
+func newTestApp(t *testing.T, modify func(*config.AuthConfig)) *testApp {
+	t.Helper()
+	p := newMockProvider(t)
+
+	var handler http.Handler
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := config.AuthConfig{
+		Enabled:       true,
+		Issuer:        p.srv.URL,
+		ClientID:      p.clientID,
+		ClientSecret:  "secret",
+		RedirectURL:   srv.URL + CallbackPath,
+		SessionSecret: strings.Repeat("s", config.MinSessionSecretLen),
+		KioskTokens: []config.KioskTokenConfig{
+			{Name: "lobby", Token: "wallscreen-token"},
+			{Name: "atlas-room", TokenSHA256: HashKioskToken("atlas-room-token"), Repos: []string{"atlas"}},
+		},
+		Admins: []string{"Alice@ecmwf.int"},
+	}
+	if modify != nil {
+		modify(&cfg)
+	}
+	authn, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if authn.github != nil {
+		authn.github.Endpoint = oauth2.Endpoint{AuthURL: p.srv.URL + "/gh/authorize", TokenURL: p.srv.URL + "/gh/token"}
+	}
+
+	handler = authn.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, ok := UserFromContext(r.Context()); ok {
+			io.WriteString(w, "hello "+u.Email)
+			if u.GitHubToken != "" {
+				io.WriteString(w, " with "+u.GitHubToken)
+			}
+			return
+		}
+		if k, ok := KioskFromContext(r.Context()); ok {
+			io.WriteString(w, "kiosk "+k.Name)
+			return
+		}
+		io.WriteString(w, "anonymous")
+	}))
+	return &testApp{provider: p, srv: srv, authn: authn}
+}
+
+// browser returns a client with a cookie jar that follows redirects.
+func browser(t *testing.T) *http.Client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &http.Client{Jar: jar}
+}
+
+// noRedirect returns a client that reports redirects instead of following them.
+func noRedirect(jar http.CookieJar) *http.Client {
+	return &http.Client{Jar: jar, CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+}
+
+func get(t *testing.T, c *http.Client, url string) (*http.Response, string) {
+	t.Helper()
+	resp, err := c.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestLoginFlow(t *testing.T) {
+	app := newTestApp(t, func(c *config.AuthConfig) { c.AllowedEmailDomains = []string{"ecmwf.int"} })
+	c := browser(t)
+
+	resp, body := get(t, c, app.srv.URL+"/issues?repo=eccodes")
+	if resp.StatusCode != http.StatusOK || body != "hello alice@ecmwf.int" {
+		t.Fatalf("after login got %d %q", resp.StatusCode, body)
+	}
+	if resp.Request.URL.RequestURI() != "/issues?repo=eccodes" {
+		t.Errorf("returned to %q, want original URL", resp.Request.URL.RequestURI())
+	}
+	if len(app.provider.tokenErrors) > 0 {
+		t.Errorf("token endpoint errors: %v", app.provider.tokenErrors)
+	}
+
+	// The session cookie now authenticates without another provider round trip.
+	get(t, c, app.srv.URL+"/builds")
+	if app.provider.authorized != 1 {
+		t.Errorf("provider authorizations = %d, want 1", app.provider.authorized)
+	}
+}
+
+func TestLoginDeniedByAllowlist(t *testing.T) {
+	app := newTestApp(t, func(c *config.AuthConfig) { c.AllowedEmailDomains = []string{"example.org"} })
+
+	resp, _ := get(t, browser(t), app.srv.URL+"/issues")
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	verified, unverified := true, false
+	a := &Authenticator{cfg: config.AuthConfig{
+		AllowedOrgs:         []string{"ecmwf"},
+		AllowedTeams:        []string{"other-org:dash"},
+		AllowedEmailDomains: []string{"ecmwf.int"},
+	}}
+
+	tests := []struct {
+		name   string
+		claims idClaims
+		want   bool
+	}{
+		{"org member", idClaims{Groups: []string{"ecmwf"}}, true},
+		{"team in allowed org", idClaims{Groups: []string{"ECMWF:ops"}}, true},
+		{"allowed team", idClaims{Groups: []string{"other-org:dash"}}, true},
+		{"other team", idClaims{Groups: []string{"other-org:infra"}}, false},
+		{"org prefix only", idClaims{Groups: []string{"ecmwf-labs"}}, false},
+		{"verified email", idClaims{Email: "bob@ECMWF.int", EmailVerified: &verified}, true},
+		{"unverified email", idClaims{Email: "bob@ecmwf.int", EmailVerified: &unverified}, false},
+		{"subdomain", idClaims{Email: "bob@evil.ecmwf.int"}, false},
+		{"nothing", idClaims{}, false},
+	}
+	for _, tt := range tests {
+		if got := a.allowed(&tt.claims); got != tt.want {
+			t.Errorf("%s: allowed() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	open := &Authenticator{}
+	if !open.allowed(&idClaims{}) {
+		t.Error("empty allowlist should admit everyone")
+	}
+}
+
+func TestPublicPathsNeedNoLogin(t *testing.T) {
+	app := newTestApp(t, nil)
+	c := noRedirect(nil)
+
+	for _, path := range []string{"/health", "/metrics", "/badge/eccodes.svg"} {
+		resp, body := get(t, c, app.srv.URL+path)
+		if resp.StatusCode != http.StatusOK || body != "anonymous" {
+			t.Errorf("%s: got %d %q, want public access", path, resp.StatusCode, body)
+		}
+	}
+
+	resp, _ := get(t, c, app.srv.URL+"/builds")
+	if resp.StatusCode != http.StatusFound || !strings.HasPrefix(resp.Header.Get("Location"), app.provider.srv.URL+"/authorize") {
+		t.Errorf("/builds: got %d to %q, want redirect to provider", resp.StatusCode, resp.Header.Get("Location"))
+	}
+}
+
+func TestKioskToken(t *testing.T) {
+	app := newTestApp(t, nil)
+	jar, _ := cookiejar.New(nil)
+	c := noRedirect(jar)
+
+	resp, _ := get(t, c, app.srv.URL+"/builds-dashboard?kiosk=wrong")
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("wrong token: status = %d, want redirect to login", resp.StatusCode)
+	}
+
+	resp, body := get(t, c, app.srv.URL+"/builds-dashboard?kiosk=wallscreen-token")
+	if resp.StatusCode != http.StatusOK || body != "kiosk lobby" {
+		t.Fatalf("kiosk token: got %d %q", resp.StatusCode, body)
+	}
+
+	// The cookie set above covers the page's static assets and reloads.
+	for _, path := range []string{"/static/tv.css", "/builds-dashboard"} {
+		if resp, _ := get(t, c, app.srv.URL+path); resp.StatusCode != http.StatusOK {
+			t.Errorf("%s with kiosk cookie: status = %d, want 200", path, resp.StatusCode)
+		}
+	}
+
+	// Feed readers subscribe with the token in the feed URL.
+	if resp, _ := get(t, noRedirect(nil), app.srv.URL+"/releases.atom?kiosk=wallscreen-token"); resp.StatusCode != http.StatusOK {
+		t.Errorf("/releases.atom with kiosk token: status = %d, want 200", resp.StatusCode)
+	}
+
+	// But not the rest of the dashboard.
+	if resp, _ := get(t, c, app.srv.URL+"/issues"); resp.StatusCode != http.StatusFound {
+		t.Errorf("/issues with kiosk cookie: status = %d, want redirect to login", resp.StatusCode)
+	}
+}
+
+func TestTamperedSessionRejected(t *testing.T) {
+	app := newTestApp(t, nil)
+	value, err := app.authn.cookies.encode(sessionCookie, session{Subject: "u1", Email: "alice@ecmwf.int", Expiry: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, _ := (signer{key: []byte("another-secret")}).encode(sessionCookie, session{Subject: "u1", Expiry: time.Now().Add(time.Hour).Unix()})
+	expired, _ := app.authn.cookies.encode(sessionCookie, session{Subject: "u1", Expiry: time.Now().Add(-time.Minute).Unix()})
+	state, _ := app.authn.cookies.encode(loginCookie, loginState{Expiry: time.Now().Add(time.Hour).Unix()})
+
+	tests := []struct {
+		name   string
+		cookie string
+		wantOK bool
+	}{
+		{"valid", value, true},
+		{"modified payload", "x" + value, false},
+		{"wrong key", forged, false},
+		{"expired", expired, false},
+		{"login cookie replayed as session", state, false},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, app.srv.URL+"/builds", nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookie, Value: tt.cookie})
+		resp, err := noRedirect(nil).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if gotOK := resp.StatusCode == http.StatusOK; gotOK != tt.wantOK {
+			t.Errorf("%s: status = %d, want ok=%v", tt.name, resp.StatusCode, tt.wantOK)
+		}
+	}
+}
+
+func TestCallbackRejectsStateMismatch(t *testing.T) {
+	app := newTestApp(t, nil)
+	jar, _ := cookiejar.New(nil)
+	c := noRedirect(jar)
+
+	// Start a login to get the login cookie, then call back with another state.
+	get(t, c, app.srv.URL+LoginPath)
+	resp, _ := get(t, c, app.srv.URL+CallbackPath+"?code=abc&state=forged")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	app := newTestApp(t, nil)
+	p := app.provider
+	valid := func() map[string]any {
+		return map[string]any{"iss": p.srv.URL, "aud": p.clientID, "exp": time.Now().Add(time.Hour).Unix(), "nonce": "n1", "sub": "u1", "groups": []string{"ecmwf"}}
+	}
+	header := map[string]string{"alg": "RS256", "kid": "k1"}
+
+	claims, err := app.authn.verifyIDToken(context.Background(), p.sign(header, valid()), "n1")
+	if err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	if len(claims.Groups) != 1 || claims.Groups[0] != "ecmwf" {
+		t.Errorf("groups = %v, want [ecmwf]", claims.Groups)
+	}
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		modify func(map[string]any)
+		nonce  string
+	}{
+		{"wrong audience", header, func(c map[string]any) { c["aud"] = []string{"other"} }, "n1"},
+		{"wrong issuer", header, func(c map[string]any) { c["iss"] = "https://evil.example" }, "n1"},
+		{"expired", header, func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, "n1"},
+		{"nonce mismatch", header, func(map[string]any) {}, "n2"},
+		{"alg none", map[string]string{"alg": "none", "kid": "k1"}, func(map[string]any) {}, "n1"},
+		{"unknown key", map[string]string{"alg": "RS256", "kid": "k2"}, func(map[string]any) {}, "n1"},
+	}
+	for _, tt := range tests {
+		c := valid()
+		tt.modify(c)
+		if _, err := app.authn.verifyIDToken(context.Background(), p.sign(tt.header, c), tt.nonce); err == nil {
+			t.Errorf("%s: token accepted", tt.name)
+		}
+	}
+}
+
+func TestSafeReturnTo(t *testing.T) {
+	tests := map[string]string{
+		"":                     "/",
+		"/pulls?repo=eccodes":  "/pulls?repo=eccodes",
+		"//evil.example":       "/",
+		"/\\evil.example":      "/",
+		"https://evil.example": "/",
+		"builds":               "/",
+	}
+	for in, want := range tests {
+		if got := safeReturnTo(in); got != want {
+			t.Errorf("safeReturnTo(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNewFailsOnIssuerMismatch(t *testing.T) {
+	p := newMockProvider(t)
+	_, err := New(context.Background(), config.AuthConfig{Issuer: p.srv.URL + "/other", ClientID: "dash"})
+	if err == nil {
+		t.Fatal("expected error when discovery document is missing or names another issuer")
+	}
+}
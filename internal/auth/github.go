@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	oauth2github "golang.org/x/oauth2/github"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+)
+
+// Routes of the GitHub link step, which obtains the viewer's own GitHub
+// token after OIDC sign-in when auth.github_oauth is configured.
+const (
+	GitHubLinkPath     = "/auth/github/login"
+	GitHubCallbackPath = "/auth/github/callback"
+
+	githubLoginCookie = "ecmwf_dash_github_login"
+)
+
+// githubScopes lets the token read private repositories the viewer can
+// access; GitHub has no narrower scope for that.
+var githubScopes = []string{"repo"}
+
+func newGitHubOAuth(cfg config.GitHubOAuthConfig) *oauth2.Config {
+	if cfg.ClientID == "" {
+		return nil
+	}
+	return &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       githubScopes,
+		Endpoint:     oauth2github.Endpoint,
+	}
+}
+
+// viewerTokens keeps signed-in users' GitHub tokens in memory, keyed by
+// OIDC subject. Tokens never leave the server; after a restart users pass
+// through the (then usually silent) GitHub authorisation again. A token
+// expires with the session it was linked in, so an abandoned session does
+// not leave it in memory.
+type viewerTokens struct {
+	mu     sync.Mutex
+	tokens map[string]viewerToken
+}
+
+type viewerToken struct {
+	token   string // "" means the user declined to link
+	expires time.Time
+}
+
+func (v *viewerTokens) get(subject string, now time.Time) (token string, linked bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	t, ok := v.tokens[subject]
+	if !ok || !now.Before(t.expires) {
+		delete(v.tokens, subject)
+		return "", false
+	}
+	return t.token, true
+}
+
+// set stores token until expires, dropping other users' expired tokens.
+func (v *viewerTokens) set(subject, token string, now, expires time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.tokens == nil {
+		v.tokens = make(map[string]viewerToken)
+	}
+	for s, t := range v.tokens {
+		if !now.Before(t.expires) {
+			delete(v.tokens, s)
+		}
+	}
+	v.tokens[subject] = viewerToken{token: token, expires: expires}
+}
+
+func (v *viewerTokens) forget(subject string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.tokens, subject)
+}
+
+// beginGitHubLink redirects to GitHub's authorization page.
+func (a *Authenticator) beginGitHubLink(w http.ResponseWriter, r *http.Request, returnTo string) {
+	st, ok := a.startState(w, r, githubLoginCookie, returnTo)
+	if !ok {
+		return
+	}
+	http.Redirect(w, r, a.github.AuthCodeURL(st.State, oauth2.S256ChallengeOption(st.Verifier)), http.StatusFound)
+}
+
+// githubCallback stores the viewer's GitHub token until their session
+// expires. A viewer who declines is remembered as unlinked, so they are not
+// asked on every page and simply do not see private repositories.
+func (a *Authenticator) githubCallback(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	s, ok := a.session(r)
+	if !ok {
+		a.beginLogin(w, r, "/")
+		return
+	}
+	expires := time.Unix(s.Expiry, 0)
+
+	st, err := a.loginState(r, githubLoginCookie)
+	a.clearCookie(w, githubLoginCookie)
+	q := r.URL.Query()
+	if err != nil || !tokenEqual(q.Get("state"), st.State) {
+		logger.Warn("GitHub link callback rejected", "error", err)
+		http.Error(w, "GitHub authorisation expired or invalid, please try again", http.StatusBadRequest)
+		return
+	}
+
+	if e := q.Get("error"); e != "" {
+		logger.Info("GitHub link declined", "sub", s.Subject, "error", e)
+		a.viewers.set(s.Subject, "", a.now(), expires)
+		http.Redirect(w, r, st.ReturnTo, http.StatusFound)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), oauth2.HTTPClient, a.client)
+	tok, err := a.github.Exchange(ctx, q.Get("code"), oauth2.VerifierOption(st.Verifier))
+	if err != nil {
+		logger.Error("GitHub code exchange failed", "error", err)
+		http.Error(w, "GitHub authorisation failed", http.StatusBadGateway)
+		return
+	}
+	a.viewers.set(s.Subject, tok.AccessToken, a.now(), expires)
+	logger.Info("GitHub account linked", "sub", s.Subject)
+	http.Redirect(w, r, st.ReturnTo, http.StatusFound)
+}
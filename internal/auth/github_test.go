@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func withGitHubOAuth(c *config.AuthConfig) {
+	c.GitHubOAuth = config.GitHubOAuthConfig{
+		ClientID:     "gh-app",
+		ClientSecret: "gh-secret",
+		RedirectURL:  strings.TrimSuffix(c.RedirectURL, CallbackPath) + GitHubCallbackPath,
+	}
+}
+
+func TestGitHubLinkAfterLogin(t *testing.T) {
+	app := newTestApp(t, withGitHubOAuth)
+	c := browser(t)
+
+	resp, body := get(t, c, app.srv.URL+"/pulls")
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(body, "hello alice@ecmwf.int with gho-") {
+		t.Fatalf("after login and link got %d %q", resp.StatusCode, body)
+	}
+	if resp.Request.URL.Path != "/pulls" {
+		t.Errorf("returned to %q, want /pulls", resp.Request.URL.Path)
+	}
+
+	// The token is remembered for the session.
+	_, again := get(t, c, app.srv.URL+"/issues")
+	if !strings.HasSuffix(again, strings.TrimPrefix(body, "hello alice@ecmwf.int")) || app.provider.githubLinks != 1 {
+		t.Errorf("second request got %q after %d links, want the same token and one link", again, app.provider.githubLinks)
+	}
+
+	// Logging out forgets the token; the next login links again.
+	get(t, noRedirect(c.Jar), app.srv.URL+LogoutPath)
+	get(t, c, app.srv.URL+"/issues")
+	if app.provider.githubLinks != 2 {
+		t.Errorf("links after re-login = %d, want 2", app.provider.githubLinks)
+	}
+}
+
+func TestGitHubLinkDeclined(t *testing.T) {
+	app := newTestApp(t, withGitHubOAuth)
+	app.provider.githubDecline = true
+	c := browser(t)
+
+	resp, body := get(t, c, app.srv.URL+"/builds")
+	if resp.StatusCode != http.StatusOK || body != "hello alice@ecmwf.int" {
+		t.Fatalf("declined link got %d %q, want signed in without token", resp.StatusCode, body)
+	}
+	get(t, c, app.srv.URL+"/issues")
+	if app.provider.githubLinks != 1 {
+		t.Errorf("links = %d, a declined viewer should not be asked again", app.provider.githubLinks)
+	}
+
+	// Visiting the link route explicitly asks again.
+	app.provider.githubDecline = false
+	_, body = get(t, c, app.srv.URL+GitHubLinkPath+"?next=/pulls")
+	if !strings.Contains(body, " with gho-") {
+		t.Errorf("explicit link got %q, want a token", body)
+	}
+}
+
+func TestViewerTokensExpire(t *testing.T) {
+	var v viewerTokens
+	now := time.Now()
+	v.set("u1", "gho-1", now, now.Add(time.Hour))
+	v.set("u2", "gho-2", now, now.Add(2*time.Hour))
+
+	if token, linked := v.get("u1", now.Add(59*time.Minute)); !linked || token != "gho-1" {
+		t.Errorf("before expiry got %q, %v, want gho-1", token, linked)
+	}
+	if token, linked := v.get("u1", now.Add(time.Hour)); linked || token != "" {
+		t.Errorf("at session expiry got %q, %v, want unlinked", token, linked)
+	}
+
+	// An abandoned session's token is dropped when another user links.
+	v.set("u3", "", now.Add(3*time.Hour), now.Add(4*time.Hour))
+	if _, ok := v.tokens["u2"]; ok || len(v.tokens) != 1 {
+		t.Errorf("tokens after expiry = %v, want only u3", v.tokens)
+	}
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+// Kiosk identifies the kiosk token a request was admitted with.
+type Kiosk struct {
+	Name  string
+	Repos []string // empty means all repositories
+}
+
+// Allows reports whether the kiosk may see repo.
+func (k Kiosk) Allows(repo string) bool {
+	return len(k.Repos) == 0 || slices.Contains(k.Repos, repo)
+}
+
+// KioskStatus describes a configured kiosk token for the admin page.
+type KioskStatus struct {
+	Name     string
+	Repos    []string
+	LastUsed time.Time // zero if unused since startup
+}
+
+type kioskKey struct{}
+
+// WithKiosk returns a copy of ctx carrying k.
+func WithKiosk(ctx context.Context, k Kiosk) context.Context {
+	return context.WithValue(ctx, kioskKey{}, k)
+}
+
+// KioskFromContext returns the kiosk a request was admitted with, if any.
+func KioskFromContext(ctx context.Context) (Kiosk, bool) {
+	k, ok := ctx.Value(kioskKey{}).(Kiosk)
+	return k, ok
+}
+
+// GenerateKioskToken returns a new random kiosk token.
+func GenerateKioskToken() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand failed: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// HashKioskToken returns the hex SHA-256 of token, the form stored in
+// auth.kiosk_tokens[].token_sha256.
+func HashKioskToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type kioskEntry struct {
+	Kiosk
+	hash [sha256.Size]byte
+}
+
+// access holds the parts of the auth config that can change on reload:
+// kiosk tokens and admins. Last-used times survive reloads by token name.
+type access struct {
+	mu       sync.RWMutex
+	kiosks   []kioskEntry
+	admins   []string
+	lastUsed map[string]time.Time
+}
+
+func (ac *access) update(cfg config.AuthConfig) {
+	kiosks := make([]kioskEntry, 0, len(cfg.KioskTokens))
+	for _, k := range cfg.KioskTokens {
+		hash := k.TokenSHA256
+		if k.Token != "" {
+			hash = HashKioskToken(k.Token)
+		}
+		e := kioskEntry{Kiosk: Kiosk{Name: k.Name, Repos: k.Repos}}
+		if _, err := hex.Decode(e.hash[:], []byte(strings.ToLower(hash))); err != nil {
+			continue // rejected by config validation
+		}
+		kiosks = append(kiosks, e)
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.kiosks = kiosks
+	ac.admins = cfg.Admins
+	if ac.lastUsed == nil {
+		ac.lastUsed = make(map[string]time.Time)
+	}
+}
+
+// lookup returns the kiosk whose token hashes to token's hash.
+func (ac *access) lookup(token string) (Kiosk, bool) {
+	sum := sha256.Sum256([]byte(token))
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+	for _, e := range ac.kiosks {
+		if subtle.ConstantTimeCompare(sum[:], e.hash[:]) == 1 {
+			return e.Kiosk, true
+		}
+	}
+	return Kiosk{}, false
+}
+
+func (ac *access) touch(name string, at time.Time) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.lastUsed[name] = at
+}
+
+func (ac *access) isAdmin(email string) bool {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+	for _, a := range ac.admins {
+		if email != "" && strings.EqualFold(a, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateAccess applies the reloadable parts of cfg (kiosk tokens and
+// admins). Removing a token from the config revokes it immediately.
+func (a *Authenticator) UpdateAccess(cfg config.AuthConfig) {
+	a.access.update(cfg)
+}
+
+// KioskStatus lists the configured kiosk tokens in config order.
+func (a *Authenticator) KioskStatus() []KioskStatus {
+	a.access.mu.RLock()
+	defer a.access.mu.RUnlock()
+	out := make([]KioskStatus, len(a.access.kiosks))
+	for i, e := range a.access.kiosks {
+		out[i] = KioskStatus{Name: e.Name, Repos: e.Repos, LastUsed: a.access.lastUsed[e.Name]}
+	}
+	return out
+}
+
+// kiosk admits read-only requests carrying a kiosk token in the query
+// parameter (remembered in a cookie, so static assets and reloads work) or
+// in that cookie.
+func (a *Authenticator) kiosk(w http.ResponseWriter, r *http.Request) (Kiosk, bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return Kiosk{}, false
+	}
+	token := r.URL.Query().Get(KioskParam)
+	fromQuery := token != ""
+	if !fromQuery {
+		c, err := r.Cookie(kioskCookie)
+		if err != nil {
+			return Kiosk{}, false
+		}
+		token = c.Value
+	}
+
+	k, ok := a.access.lookup(token)
+	if !ok {
+		return Kiosk{}, false
+	}
+	if fromQuery {
+		a.setCookie(w, kioskCookie, token, kioskCookieMaxAge)
+	}
+	a.access.touch(k.Name, a.now())
+	return k, true
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestKioskScopeAndLastUsed(t *testing.T) {
+	app := newTestApp(t, nil)
+	c := noRedirect(nil)
+
+	before := app.authn.KioskStatus()
+	if len(before) != 2 || !before[0].LastUsed.IsZero() || !before[1].LastUsed.IsZero() {
+		t.Fatalf("unexpected initial status: %+v", before)
+	}
+
+	// Hashed tokens work like plain ones and carry their repo scope.
+	resp, body := get(t, c, app.srv.URL+"/builds-dashboard?kiosk=atlas-room-token")
+	if resp.StatusCode != http.StatusOK || body != "kiosk atlas-room" {
+		t.Fatalf("got %d %q", resp.StatusCode, body)
+	}
+	k, ok := app.authn.access.lookup("atlas-room-token")
+	if !ok || !k.Allows("atlas") || k.Allows("eccodes") {
+		t.Errorf("scope = %+v, want only atlas", k)
+	}
+
+	after := app.authn.KioskStatus()
+	if after[1].Name != "atlas-room" || time.Since(after[1].LastUsed) > time.Minute {
+		t.Errorf("atlas-room last used not recorded: %+v", after[1])
+	}
+	if !after[0].LastUsed.IsZero() {
+		t.Errorf("lobby should be unused: %+v", after[0])
+	}
+}
+
+func TestKioskIsReadOnly(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	resp, err := http.Post(app.srv.URL+"/builds-dashboard?kiosk=wallscreen-token", "text/plain", strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("POST with kiosk token: status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestUpdateAccessRevokesKiosk(t *testing.T) {
+	app := newTestApp(t, nil)
+	jar, _ := cookiejar.New(nil)
+	c := noRedirect(jar)
+
+	if resp, _ := get(t, c, app.srv.URL+"/builds-dashboard?kiosk=wallscreen-token"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	app.authn.UpdateAccess(config.AuthConfig{KioskTokens: []config.KioskTokenConfig{
+		{Name: "atlas-room", TokenSHA256: HashKioskToken("atlas-room-token")},
+	}})
+
+	// The remembered cookie stops working once the token is removed.
+	if resp, _ := get(t, c, app.srv.URL+"/builds-dashboard"); resp.StatusCode != http.StatusFound {
+		t.Errorf("revoked token: status = %d, want redirect to login", resp.StatusCode)
+	}
+}
+
+func TestAdminPageRequiresAdmin(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	// Signed in as alice@ecmwf.int, who is listed (case-insensitively) in admins.
+	c := browser(t)
+	if resp, body := get(t, c, app.srv.URL+AdminKioskPath); resp.StatusCode != http.StatusOK || body != "hello alice@ecmwf.int" {
+		t.Errorf("admin: got %d %q", resp.StatusCode, body)
+	}
+
+	app.authn.UpdateAccess(config.AuthConfig{Admins: []string{"bob@ecmwf.int"}})
+	if resp, _ := get(t, c, app.srv.URL+AdminKioskPath); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("non-admin: status = %d, want 403", resp.StatusCode)
+	}
+
+	// A kiosk token never grants the admin page.
+	resp, _ := get(t, noRedirect(nil), app.srv.URL+AdminKioskPath+"?kiosk=wallscreen-token")
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("kiosk: status = %d, want redirect to login", resp.StatusCode)
+	}
+}
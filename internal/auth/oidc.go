@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// providerMetadata is the subset of the OpenID Provider discovery document
+// the dashboard needs.
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// discover fetches the issuer's /.well-known/openid-configuration.
+func discover(ctx context.Context, client *http.Client, issuer string) (*providerMetadata, error) {
+	var meta providerMetadata
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, fmt.Errorf("OIDC discovery: %w", err)
+	}
+	// The issuer in the document must match the configured one exactly,
+	// otherwise ID token iss checks would be meaningless.
+	if meta.Issuer != issuer {
+		return nil, fmt.Errorf("OIDC discovery: issuer mismatch: configured %q, provider reports %q", issuer, meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery: provider metadata lacks authorization, token or jwks endpoint")
+	}
+	return &meta, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jwksMinRefresh limits how often an unknown key ID triggers a JWKS refetch.
+const jwksMinRefresh = time.Minute
+
+// keySet caches the provider's RSA signing keys by key ID, refetching when
+// a token names a key it has not seen (the provider rotated keys).
+type keySet struct {
+	client *http.Client
+	uri    string
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+func (ks *keySet) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if k := ks.lookup(kid); k != nil {
+		return k, nil
+	}
+	if ks.keys != nil && time.Since(ks.fetched) < jwksMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if err := ks.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if k := ks.lookup(kid); k != nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup returns the key with the given ID. Tokens without a kid are
+// accepted only when the set holds a single key.
+func (ks *keySet) lookup(kid string) *rsa.PublicKey {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, k := range ks.keys {
+			return k
+		}
+	}
+	return ks.keys[kid]
+}
+
+func (ks *keySet) refresh(ctx context.Context) error {
+	var doc struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := getJSON(ctx, ks.client, ks.uri, &doc); err != nil {
+		return fmt.Errorf("fetching JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) > 4 {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	ks.keys = keys
+	ks.fetched = time.Now()
+	return nil
+}
+
+// idClaims are the ID token claims the dashboard reads. The groups claim is
+// decoded separately because its name is configurable.
+type idClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	Expiry        int64    `json:"exp"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified *bool    `json:"email_verified"`
+	Name          string   `json:"name"`
+	Username      string   `json:"preferred_username"`
+
+	Groups []string `json:"-"`
+}
+
+// audience decodes the aud claim, which may be a string or an array.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*a = multi
+	return nil
+}
+
+// clockSkew is the leeway allowed when checking token expiry.
+const clockSkew = time.Minute
+
+// verifyIDToken checks the RS256 signature and the iss, aud, exp and nonce
+// claims of a compact-serialised ID token.
+func (a *Authenticator) verifyIDToken(ctx context.Context, raw, nonce string) (*idClaims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed ID token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("ID token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported ID token algorithm %q", header.Alg)
+	}
+	key, err := a.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("ID token signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return nil, errors.New("ID token signature is invalid")
+	}
+
+	var claims idClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("ID token claims: %w", err)
+	}
+	if claims.Issuer != a.provider.Issuer {
+		return nil, fmt.Errorf("ID token issued by %q, want %q", claims.Issuer, a.provider.Issuer)
+	}
+	if !slices.Contains(claims.Audience, a.oauth.ClientID) {
+		return nil, errors.New("ID token is not intended for this client")
+	}
+	if a.now().After(time.Unix(claims.Expiry, 0).Add(clockSkew)) {
+		return nil, errors.New("ID token has expired")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("ID token nonce mismatch")
+	}
+
+	var extra map[string]json.RawMessage
+	if err := decodeSegment(parts[1], &extra); err == nil {
+		if g, ok := extra[a.groupsClaim]; ok {
+			// A malformed groups claim just means no groups.
+			_ = json.Unmarshal(g, &claims.Groups)
+		}
+	}
+	return &claims, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
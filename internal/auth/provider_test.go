@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockProvider is a minimal OpenID provider: discovery, an authorization
+// endpoint that signs in a fixed user without a login page, a token
+// endpoint that enforces PKCE, and a JWKS endpoint.
+type mockProvider struct {
+	srv      *httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+
+	mu          sync.Mutex
+	claims      map[string]any // claims for the next ID token
+	pending     map[string]pendingCode
+	authorized  int
+	tokenErrors []string
+
+	// GitHub OAuth app endpoints (/gh/...), which issue "gho-<code>" tokens.
+	githubDecline bool
+	githubLinks   int
+}
+
+type pendingCode struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &mockProvider{
+		key:      key,
+		clientID: "dash",
+		claims:   map[string]any{"sub": "u1", "email": "alice@ecmwf.int", "email_verified": true, "name": "Alice"},
+		pending:  make(map[string]pendingCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.srv.URL,
+			"authorization_endpoint": p.srv.URL + "/authorize",
+			"token_endpoint":         p.srv.URL + "/token",
+			"jwks_uri":               p.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	mux.HandleFunc("/gh/authorize", p.githubAuthorize)
+	mux.HandleFunc("/gh/token", p.githubToken)
+	p.srv = httptest.NewServer(mux)
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+func (p *mockProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != p.clientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "bad authorization request", http.StatusBadRequest)
+		return
+	}
+	code := randomString()
+	p.mu.Lock()
+	p.authorized++
+	p.pending[code] = pendingCode{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), redirectURI: q.Get("redirect_uri")}
+	p.mu.Unlock()
+
+	back, _ := url.Parse(q.Get("redirect_uri"))
+	bq := back.Query()
+	bq.Set("code", code)
+	bq.Set("state", q.Get("state"))
+	back.RawQuery = bq.Encode()
+	http.Redirect(w, r, back.String(), http.StatusFound)
+}
+
+func (p *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pc, ok := p.pending[r.Form.Get("code")]
+	delete(p.pending, r.Form.Get("code"))
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	switch {
+	case !ok:
+		p.tokenErrors = append(p.tokenErrors, "unknown code")
+	case base64.RawURLEncoding.EncodeToString(sum[:]) != pc.challenge:
+		p.tokenErrors = append(p.tokenErrors, "PKCE verifier mismatch")
+		ok = false
+	case r.Form.Get("redirect_uri") != pc.redirectURI:
+		p.tokenErrors = append(p.tokenErrors, "redirect_uri mismatch")
+		ok = false
+	}
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := map[string]any{
+		"iss":   p.srv.URL,
+		"aud":   p.clientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": pc.nonce,
+	}
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "at",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     p.sign(map[string]string{"alg": "RS256", "kid": "k1"}, claims),
+	})
+}
+
+// sign produces a compact RS256 JWT.
+func (p *mockProvider) sign(header map[string]string, claims map[string]any) string {
+	h, _ := json.Marshal(header)
+	c, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(input))
+	sig, _ := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (p *mockProvider) githubAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	back, _ := url.Parse(q.Get("redirect_uri"))
+	bq := back.Query()
+	bq.Set("state", q.Get("state"))
+
+	p.mu.Lock()
+	p.githubLinks++
+	if p.githubDecline {
+		bq.Set("error", "access_denied")
+	} else {
+		code := randomString()
+		p.pending[code] = pendingCode{challenge: q.Get("code_challenge"), redirectURI: q.Get("redirect_uri")}
+		bq.Set("code", code)
+	}
+	p.mu.Unlock()
+
+	back.RawQuery = bq.Encode()
+	http.Redirect(w, r, back.String(), http.StatusFound)
+}
+
+func (p *mockProvider) githubToken(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	code := r.Form.Get("code")
+	pc, ok := p.pending[code]
+	delete(p.pending, code)
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != pc.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"bad_verification_code"}`))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"access_token": "gho-" + code[:8], "token_type": "bearer", "scope": "repo"})
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// session is the payload of the session cookie.
+type session struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Expiry  int64  `json:"exp"`
+}
+
+// loginState is the payload of the short-lived cookie that carries the
+// state, nonce and PKCE verifier from /auth/login to /auth/callback.
+type loginState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	ReturnTo string `json:"return_to"`
+	Expiry   int64  `json:"exp"`
+}
+
+var errBadSignature = errors.New("invalid cookie signature")
+
+// signer encodes values as base64url(JSON) "." base64url(HMAC-SHA256). The
+// purpose is mixed into the MAC so a cookie of one kind cannot be replayed
+// as another.
+type signer struct {
+	key []byte
+}
+
+func (s signer) encode(purpose string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.mac(purpose, payload)), nil
+}
+
+func (s signer) decode(purpose, value string, v any) error {
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return errBadSignature
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.mac(purpose, payload)) {
+		return errBadSignature
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (s signer) mac(purpose, payload string) []byte {
+	m := hmac.New(sha256.New, s.key)
+	m.Write([]byte(purpose + "." + payload))
+	return m.Sum(nil)
+}
@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -12,40 +17,248 @@ type Config struct {
 	GitHub         GitHubConfig         `yaml:"github"`
 	FetchIntervals FetchIntervalsConfig `yaml:"fetch_intervals"`
 	Server         ServerConfig         `yaml:"server"`
+	Log            LogConfig            `yaml:"log"`
+	Tracing        TracingConfig        `yaml:"tracing"`
+	Auth           AuthConfig           `yaml:"auth"`
+	Trends         TrendsConfig         `yaml:"trends"`
+	Failures       FailuresConfig       `yaml:"failures"`
 }
 
 type GitHubConfig struct {
 	Organization string             `yaml:"organization"`
+	Token        string             `yaml:"token"` // falls back to GITHUB_TOKEN when empty
 	Repositories []RepositoryConfig `yaml:"repositories"`
+
+	// Projects lists organization Projects (v2) boards shown on /planning.
+	Projects []ProjectConfig `yaml:"projects"`
+}
+
+// ProjectConfig names an organization Projects (v2) board.
+type ProjectConfig struct {
+	Number int `yaml:"number"` // from the board URL, e.g. 12 in /orgs/ecmwf/projects/12
+
+	// StatusField is the single-select field whose options are the
+	// board columns. Empty means "Status".
+	StatusField string `yaml:"status_field"`
+}
+
+// StatusFieldName returns StatusField, or its default when empty.
+func (p ProjectConfig) StatusFieldName() string {
+	if p.StatusField != "" {
+		return p.StatusField
+	}
+	return "Status"
 }
 
 type RepositoryConfig struct {
 	Name     string   `yaml:"name"`
 	Branches []string `yaml:"branches"`
+
+	// Scheduled lists workflow files (e.g. nightly.yml) whose latest runs
+	// are shown whatever commit they ran on, for nightly and other
+	// scheduled workflows.
+	Scheduled []string `yaml:"scheduled"`
+
+	// DependsOn names the configured repositories this one builds
+	// against. A failing upstream branch explains failures on the same
+	// branch downstream.
+	DependsOn []string `yaml:"depends_on"`
+
+	// Compare lists branch pairs whose divergence is shown on /branches,
+	// such as develop against master.
+	Compare []BranchPair `yaml:"compare"`
+
+	// ReleaseBranch is the branch releases are tagged from. Empty means
+	// master or main if tracked, else the first tracked branch.
+	ReleaseBranch string `yaml:"release_branch"`
+}
+
+// ReleaseBranchName returns ReleaseBranch, or its default when empty.
+func (r RepositoryConfig) ReleaseBranchName() string {
+	if r.ReleaseBranch != "" {
+		return r.ReleaseBranch
+	}
+	for _, b := range r.Branches {
+		if b == "master" || b == "main" {
+			return b
+		}
+	}
+	if len(r.Branches) > 0 {
+		return r.Branches[0]
+	}
+	return "master"
+}
+
+// BranchPair names two branches to compare: how far Head is ahead of and
+// behind Base.
+type BranchPair struct {
+	Base string `yaml:"base"`
+	Head string `yaml:"head"`
 }
 
 type FetchIntervalsConfig struct {
 	Issues       time.Duration `yaml:"issues"`
 	PullRequests time.Duration `yaml:"pull_requests"`
 	Actions      time.Duration `yaml:"actions"`
+	Branches     time.Duration `yaml:"branches"`    // branch comparisons; defaults to DefaultBranchesInterval
+	Releases     time.Duration `yaml:"releases"`    // releases, tags and milestones; defaults to DefaultReleasesInterval
+	Planning     time.Duration `yaml:"planning"`    // milestones and project boards; defaults to DefaultPlanningInterval
+	Deployments  time.Duration `yaml:"deployments"` // deployments and their statuses; defaults to DefaultDeploymentsInterval
+}
+
+// Defaults for the optional fetch intervals. Divergence, releases and
+// planning change slowly; deployments follow merges.
+const (
+	DefaultBranchesInterval    = time.Hour
+	DefaultReleasesInterval    = 30 * time.Minute
+	DefaultPlanningInterval    = 30 * time.Minute
+	DefaultDeploymentsInterval = 15 * time.Minute
+)
+
+// TrendsConfig controls the check duration statistics on /builds/trends.
+type TrendsConfig struct {
+	Runs                int     `yaml:"runs"`                 // runs per check considered; defaults to 20
+	RegressionThreshold float64 `yaml:"regression_threshold"` // flag slowdowns above this fraction; defaults to 0.25
 }
 
+// Trend defaults and limits. MaxTrendRuns matches the history the store keeps.
+const (
+	DefaultTrendRuns           = 20
+	DefaultRegressionThreshold = 0.25
+	MaxTrendRuns               = 100
+)
+
+// FailuresConfig bounds the details fetched for failing check runs on
+// tracked branches. Job logs cost an extra download per failing job, so
+// they are off unless LogLines is set.
+type FailuresConfig struct {
+	Annotations int `yaml:"annotations"` // per check run; defaults to 10
+	LogLines    int `yaml:"log_lines"`   // trailing job log lines; 0 disables
+}
+
+// Failure detail defaults and limits.
+const (
+	DefaultFailureAnnotations = 10
+	MaxFailureAnnotations     = 50
+	MaxFailureLogLines        = 200
+)
+
 type ServerConfig struct {
 	Port int    `yaml:"port"`
 	Host string `yaml:"host"`
 }
 
+type LogConfig struct {
+	Format string `yaml:"format"` // text (default) or json
+	Level  string `yaml:"level"`  // debug, info (default), warn or error
+}
+
+// TracingConfig controls OpenTelemetry trace export. Disabled by default.
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/HTTP collector as host:port or URL
+	Insecure    bool    `yaml:"insecure"`     // use plain HTTP to the collector
+	ServiceName string  `yaml:"service_name"` // defaults to ecmwf-dash
+	SampleRatio float64 `yaml:"sample_ratio"` // fraction of traces kept; defaults to 1
+}
+
+// AuthConfig enables OIDC single sign-on. When disabled the dashboard is
+// open to anyone who can reach it.
+type AuthConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"` // absolute URL ending in /auth/callback
+	Scopes       []string `yaml:"scopes"`       // defaults to openid, email, profile, groups
+	GroupsClaim  string   `yaml:"groups_claim"` // defaults to groups
+
+	// SessionSecret signs session cookies; at least 32 bytes. Changing it
+	// signs everybody out.
+	SessionSecret string        `yaml:"session_secret"`
+	SessionTTL    time.Duration `yaml:"session_ttl"` // defaults to 12h
+
+	// Allowlist. A user may sign in if any entry matches; when all three
+	// are empty, everyone the issuer authenticates may sign in. Orgs and
+	// teams are matched against the groups claim as "org" and "org:team",
+	// the format Dex's GitHub connector emits.
+	AllowedOrgs         []string `yaml:"allowed_orgs"`
+	AllowedTeams        []string `yaml:"allowed_teams"`
+	AllowedEmailDomains []string `yaml:"allowed_email_domains"`
+
+	// KioskTokens grant read-only access to /builds-dashboard without a
+	// login, for wall screens. Admins (emails) may view their usage.
+	KioskTokens []KioskTokenConfig `yaml:"kiosk_tokens"`
+	Admins      []string           `yaml:"admins"`
+
+	// GitHubOAuth, when set, has every viewer also authorise a GitHub OAuth
+	// app, so private repositories are only shown to viewers who can read
+	// them on GitHub.
+	GitHubOAuth        GitHubOAuthConfig `yaml:"github_oauth"`
+	VisibilityCacheTTL time.Duration     `yaml:"visibility_cache_ttl"` // defaults to 10m
+}
+
+// GitHubOAuthConfig is a GitHub OAuth app used to obtain viewers' tokens.
+type GitHubOAuthConfig struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	RedirectURL  string `yaml:"redirect_url"` // absolute URL ending in /auth/github/callback
+}
+
+// KioskTokenConfig defines one kiosk token. Exactly one of Token and
+// TokenSHA256 is set; the hash form keeps the secret out of the config and
+// is what the kiosk-token command prints.
+type KioskTokenConfig struct {
+	Name        string   `yaml:"name"`
+	Token       string   `yaml:"token"`
+	TokenSHA256 string   `yaml:"token_sha256"` // hex SHA-256 of the token
+	Repos       []string `yaml:"repos"`        // empty means all repositories
+}
+
+// MinKioskTokenLen is the minimum length of a plain kiosk token.
+const MinKioskTokenLen = 16
+
+// MinSessionSecretLen is the minimum length of auth.session_secret.
+const MinSessionSecretLen = 32
+
+// Options are command-line overrides. They take precedence over both the
+// config file and ECMWF_DASH_* environment variables.
+type Options struct {
+	Listen    string // host:port, replaces server.host and server.port
+	LogFormat string // replaces log.format
+}
+
+// Load reads the config file at path with no command-line overrides.
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	return LoadWithOptions(path, Options{})
+}
+
+// LoadWithOptions reads the config file at path and resolves the final
+// configuration. Precedence, lowest to highest:
+//
+//  1. config file, with ${VAR} references in values expanded from the
+//     environment
+//  2. ECMWF_DASH_* environment variables (see ApplyEnv)
+//  3. command-line options
+func LoadWithOptions(path string, opts Options) (*Config, error) {
+	doc, err := readExpanded(path)
 	if err != nil {
-		return nil, fmt.Errorf("reading config file: %w", err)
+		return nil, err
 	}
 
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := doc.Decode(&cfg); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
+	if err := cfg.ApplyEnv(); err != nil {
+		return nil, fmt.Errorf("applying environment overrides: %w", err)
+	}
+
+	if err := cfg.applyOptions(opts); err != nil {
+		return nil, fmt.Errorf("applying command-line options: %w", err)
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
@@ -53,6 +266,43 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// readExpanded reads and parses the config file at path and expands ${VAR}
+// references in its values.
+func readExpanded(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	if err := expandEnv(&doc); err != nil {
+		return nil, fmt.Errorf("expanding config: %w", err)
+	}
+	return &doc, nil
+}
+
+func (c *Config) applyOptions(opts Options) error {
+	if opts.Listen != "" {
+		host, portStr, err := net.SplitHostPort(opts.Listen)
+		if err != nil {
+			return fmt.Errorf("listen address %q: %w", opts.Listen, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return fmt.Errorf("listen address %q: invalid port", opts.Listen)
+		}
+		c.Server.Host = host
+		c.Server.Port = port
+	}
+	if opts.LogFormat != "" {
+		c.Log.Format = opts.LogFormat
+	}
+	return nil
+}
+
 // Validate checks that all required config fields have sensible values.
 func (c *Config) Validate() error {
 	var errs []string
@@ -70,6 +320,22 @@ func (c *Config) Validate() error {
 		if len(repo.Branches) == 0 {
 			errs = append(errs, fmt.Sprintf("repository[%d] (%s) needs at least one branch", i, repo.Name))
 		}
+		for j, wf := range repo.Scheduled {
+			if wf == "" || strings.Contains(wf, "/") {
+				errs = append(errs, fmt.Sprintf("repository[%d] (%s).scheduled[%d] must be a workflow file name such as nightly.yml, got %q", i, repo.Name, j, wf))
+			}
+		}
+		for j, pair := range repo.Compare {
+			if pair.Base == "" || pair.Head == "" || pair.Base == pair.Head {
+				errs = append(errs, fmt.Sprintf("repository[%d] (%s).compare[%d] needs two different branches as base and head, got %q and %q", i, repo.Name, j, pair.Base, pair.Head))
+			}
+		}
+	}
+	errs = append(errs, c.GitHub.validateDependencies()...)
+	for i, project := range c.GitHub.Projects {
+		if project.Number <= 0 {
+			errs = append(errs, fmt.Sprintf("github.projects[%d].number must be > 0, got %d", i, project.Number))
+		}
 	}
 
 	if c.FetchIntervals.Issues <= 0 {
@@ -81,17 +347,178 @@ func (c *Config) Validate() error {
 	if c.FetchIntervals.Actions <= 0 {
 		errs = append(errs, "fetch_intervals.actions must be > 0")
 	}
+	if c.FetchIntervals.Branches < 0 {
+		errs = append(errs, "fetch_intervals.branches must be >= 0")
+	}
+	if c.FetchIntervals.Releases < 0 {
+		errs = append(errs, "fetch_intervals.releases must be >= 0")
+	}
+	if c.FetchIntervals.Planning < 0 {
+		errs = append(errs, "fetch_intervals.planning must be >= 0")
+	}
+	if c.FetchIntervals.Deployments < 0 {
+		errs = append(errs, "fetch_intervals.deployments must be >= 0")
+	}
 
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		errs = append(errs, fmt.Sprintf("server.port must be 1-65535, got %d", c.Server.Port))
 	}
 
+	switch c.Log.Format {
+	case "", "text", "json":
+	default:
+		errs = append(errs, fmt.Sprintf("log.format must be text or json, got %q", c.Log.Format))
+	}
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		errs = append(errs, "tracing.endpoint is required when tracing is enabled")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Sprintf("tracing.sample_ratio must be between 0 and 1, got %v", c.Tracing.SampleRatio))
+	}
+
+	switch c.Log.Level {
+	case "", "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Sprintf("log.level must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+
+	if c.Trends.Runs < 0 || c.Trends.Runs > MaxTrendRuns {
+		errs = append(errs, fmt.Sprintf("trends.runs must be between 0 and %d, got %d", MaxTrendRuns, c.Trends.Runs))
+	}
+	if c.Trends.RegressionThreshold < 0 {
+		errs = append(errs, fmt.Sprintf("trends.regression_threshold must be >= 0, got %v", c.Trends.RegressionThreshold))
+	}
+	if c.Failures.Annotations < 0 || c.Failures.Annotations > MaxFailureAnnotations {
+		errs = append(errs, fmt.Sprintf("failures.annotations must be between 0 and %d, got %d", MaxFailureAnnotations, c.Failures.Annotations))
+	}
+	if c.Failures.LogLines < 0 || c.Failures.LogLines > MaxFailureLogLines {
+		errs = append(errs, fmt.Sprintf("failures.log_lines must be between 0 and %d, got %d", MaxFailureLogLines, c.Failures.LogLines))
+	}
+
+	if c.Auth.Enabled {
+		errs = append(errs, c.Auth.validate()...)
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("%s", joinErrors(errs))
 	}
 	return nil
 }
 
+// validateDependencies checks that depends_on only names other configured
+// repositories and that the dependencies form no cycle.
+func (g *GitHubConfig) validateDependencies() []string {
+	var errs []string
+	deps := make(map[string][]string, len(g.Repositories))
+	for _, repo := range g.Repositories {
+		deps[repo.Name] = nil
+	}
+	for i, repo := range g.Repositories {
+		for j, dep := range repo.DependsOn {
+			switch _, ok := deps[dep]; {
+			case dep == repo.Name:
+				errs = append(errs, fmt.Sprintf("repository[%d] (%s).depends_on[%d] must not name the repository itself", i, repo.Name, j))
+			case !ok:
+				errs = append(errs, fmt.Sprintf("repository[%d] (%s).depends_on[%d]: repository %q is not configured", i, repo.Name, j, dep))
+			default:
+				deps[repo.Name] = append(deps[repo.Name], dep)
+			}
+		}
+	}
+
+	// Depth-first search; reaching a repository still on the stack closes
+	// a cycle.
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(deps))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return append(append([]string(nil), path[i:]...), name)
+				}
+			}
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+	for _, repo := range g.Repositories {
+		if cycle := visit(repo.Name); cycle != nil {
+			errs = append(errs, fmt.Sprintf("github.repositories depends_on forms a cycle: %s", strings.Join(cycle, " -> ")))
+			break
+		}
+	}
+	return errs
+}
+
+func (a *AuthConfig) validate() []string {
+	var errs []string
+	if a.Issuer == "" {
+		errs = append(errs, "auth.issuer is required when auth is enabled")
+	}
+	if a.ClientID == "" {
+		errs = append(errs, "auth.client_id is required when auth is enabled")
+	}
+	if u, err := url.Parse(a.RedirectURL); err != nil || !u.IsAbs() || !strings.HasSuffix(u.Path, "/auth/callback") {
+		errs = append(errs, fmt.Sprintf("auth.redirect_url must be an absolute URL ending in /auth/callback, got %q", a.RedirectURL))
+	}
+	if len(a.SessionSecret) < MinSessionSecretLen {
+		errs = append(errs, fmt.Sprintf("auth.session_secret must be at least %d bytes", MinSessionSecretLen))
+	}
+	if a.SessionTTL < 0 {
+		errs = append(errs, "auth.session_ttl must not be negative")
+	}
+	if g := a.GitHubOAuth; g.ClientID != "" {
+		if g.ClientSecret == "" {
+			errs = append(errs, "auth.github_oauth.client_secret is required with client_id")
+		}
+		if u, err := url.Parse(g.RedirectURL); err != nil || !u.IsAbs() || !strings.HasSuffix(u.Path, "/auth/github/callback") {
+			errs = append(errs, fmt.Sprintf("auth.github_oauth.redirect_url must be an absolute URL ending in /auth/github/callback, got %q", g.RedirectURL))
+		}
+	}
+	if a.VisibilityCacheTTL < 0 {
+		errs = append(errs, "auth.visibility_cache_ttl must not be negative")
+	}
+
+	seen := make(map[string]bool)
+	for i, k := range a.KioskTokens {
+		switch {
+		case k.Name == "":
+			errs = append(errs, fmt.Sprintf("auth.kiosk_tokens[%d].name is required", i))
+		case seen[k.Name]:
+			errs = append(errs, fmt.Sprintf("auth.kiosk_tokens[%d]: duplicate name %q", i, k.Name))
+		}
+		seen[k.Name] = true
+
+		switch {
+		case (k.Token == "") == (k.TokenSHA256 == ""):
+			errs = append(errs, fmt.Sprintf("auth.kiosk_tokens[%d] (%s) needs exactly one of token and token_sha256", i, k.Name))
+		case k.Token != "" && len(k.Token) < MinKioskTokenLen:
+			errs = append(errs, fmt.Sprintf("auth.kiosk_tokens[%d] (%s): token must be at least %d characters", i, k.Name, MinKioskTokenLen))
+		case k.TokenSHA256 != "" && !sha256HexRe.MatchString(k.TokenSHA256):
+			errs = append(errs, fmt.Sprintf("auth.kiosk_tokens[%d] (%s): token_sha256 must be 64 hex digits", i, k.Name))
+		}
+	}
+	return errs
+}
+
+var sha256HexRe = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
 func joinErrors(errs []string) string {
 	if len(errs) == 0 {
 		return ""
@@ -214,3 +214,477 @@ server:
 		t.Errorf("error should mention organization: %v", err)
 	}
 }
+
+// writeConfig writes content to a temp config.yaml and returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+const precedenceConfig = `github:
+  organization: ecmwf
+  repositories:
+    - name: eccodes
+      branches: [master]
+fetch_intervals:
+  issues: 30m
+  pull_requests: 10m
+  actions: 5m
+server:
+  host: "0.0.0.0"
+  port: 8000
+`
+
+func TestLoad_ExpandsEnvReferences(t *testing.T) {
+	t.Setenv("DASH_TEST_ORG", "ecmwf-lab")
+	t.Setenv("DASH_TEST_TOKEN", "s3cr$t")
+	path := writeConfig(t, `github:
+  organization: ${DASH_TEST_ORG}
+  token: "${DASH_TEST_TOKEN}"
+  repositories:
+    - name: eccodes
+      branches: [master]
+fetch_intervals:
+  issues: 30m
+  pull_requests: 10m
+  actions: 5m
+server:
+  host: "$literal"
+  port: 8000
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GitHub.Organization != "ecmwf-lab" {
+		t.Errorf("Organization = %q, want %q", cfg.GitHub.Organization, "ecmwf-lab")
+	}
+	if cfg.GitHub.Token != "s3cr$t" {
+		t.Errorf("Token = %q, want %q", cfg.GitHub.Token, "s3cr$t")
+	}
+	if cfg.Server.Host != "$literal" {
+		t.Errorf("bare $ should be left alone, Host = %q", cfg.Server.Host)
+	}
+}
+
+func TestLoad_EnvReferencesStayValues(t *testing.T) {
+	t.Setenv("DASH_TEST_TOKEN", "a: b # *c\n&d")
+	t.Setenv("DASH_TEST_PORT", "9000")
+	path := writeConfig(t, `github:
+  organization: ecmwf
+  # Set ${DASH_TEST_DEFINITELY_UNSET} in production.
+  token: ${DASH_TEST_TOKEN}
+  repositories:
+    - name: eccodes
+      branches: [master]
+fetch_intervals:
+  issues: 30m
+  pull_requests: 10m
+  actions: 5m
+server:
+  host: "0.0.0.0"
+  port: ${DASH_TEST_PORT}
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GitHub.Token != "a: b # *c\n&d" {
+		t.Errorf("Token = %q, want the variable's value verbatim", cfg.GitHub.Token)
+	}
+	if cfg.Server.Port != 9000 {
+		t.Errorf("Port = %d, want 9000", cfg.Server.Port)
+	}
+}
+
+func TestLoad_UnsetEnvReference(t *testing.T) {
+	path := writeConfig(t, strings.Replace(precedenceConfig, "ecmwf", "${DASH_TEST_DEFINITELY_UNSET}", 1))
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("expected error for unset environment variable")
+	}
+	if !strings.Contains(err.Error(), "DASH_TEST_DEFINITELY_UNSET") {
+		t.Errorf("error should name the unset variable: %v", err)
+	}
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	t.Setenv("ECMWF_DASH_GITHUB_ORGANIZATION", "other-org")
+	t.Setenv("ECMWF_DASH_GITHUB_REPOSITORIES", "[{name: fdb, branches: [develop]}, {name: odc, branches: [master]}]")
+	t.Setenv("ECMWF_DASH_FETCH_INTERVALS_ACTIONS", "2m")
+	t.Setenv("ECMWF_DASH_SERVER_PORT", "9000")
+	t.Setenv("ECMWF_DASH_LOG_FORMAT", "json")
+	path := writeConfig(t, precedenceConfig)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GitHub.Organization != "other-org" {
+		t.Errorf("Organization = %q, want env value %q", cfg.GitHub.Organization, "other-org")
+	}
+	if len(cfg.GitHub.Repositories) != 2 || cfg.GitHub.Repositories[0].Name != "fdb" || cfg.GitHub.Repositories[1].Branches[0] != "master" {
+		t.Errorf("Repositories = %+v, want env value", cfg.GitHub.Repositories)
+	}
+	if cfg.FetchIntervals.Actions != 2*time.Minute {
+		t.Errorf("Actions = %v, want env value 2m", cfg.FetchIntervals.Actions)
+	}
+	if cfg.FetchIntervals.Issues != 30*time.Minute {
+		t.Errorf("Issues = %v, file value should be kept when no override is set", cfg.FetchIntervals.Issues)
+	}
+	if cfg.Server.Port != 9000 {
+		t.Errorf("Port = %d, want env value 9000", cfg.Server.Port)
+	}
+	if cfg.Log.Format != "json" {
+		t.Errorf("Log.Format = %q, want env value json", cfg.Log.Format)
+	}
+}
+
+func TestLoad_OptionsOverrideEnv(t *testing.T) {
+	t.Setenv("ECMWF_DASH_SERVER_HOST", "10.0.0.1")
+	t.Setenv("ECMWF_DASH_SERVER_PORT", "9000")
+	t.Setenv("ECMWF_DASH_LOG_FORMAT", "json")
+	path := writeConfig(t, precedenceConfig)
+
+	cfg, err := LoadWithOptions(path, Options{Listen: "127.0.0.1:8080", LogFormat: "text"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error: %v", err)
+	}
+	if cfg.Server.Host != "127.0.0.1" || cfg.Server.Port != 8080 {
+		t.Errorf("Server = %+v, want -listen value 127.0.0.1:8080", cfg.Server)
+	}
+	if cfg.Log.Format != "text" {
+		t.Errorf("Log.Format = %q, want -log-format value text", cfg.Log.Format)
+	}
+}
+
+func TestLoad_EnvOverrideInvalid(t *testing.T) {
+	t.Setenv("ECMWF_DASH_SERVER_PORT", "not-a-port")
+	path := writeConfig(t, precedenceConfig)
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("expected error for unparseable override")
+	}
+	if !strings.Contains(err.Error(), "ECMWF_DASH_SERVER_PORT") {
+		t.Errorf("error should name the variable: %v", err)
+	}
+}
+
+func TestLoad_InvalidListen(t *testing.T) {
+	path := writeConfig(t, precedenceConfig)
+
+	for _, listen := range []string{"8080", "host:port"} {
+		if _, err := LoadWithOptions(path, Options{Listen: listen}); err == nil {
+			t.Errorf("expected error for -listen %q", listen)
+		}
+	}
+}
+
+func TestValidateLogFormat(t *testing.T) {
+	cfg := validConfig()
+	cfg.Log.Format = "xml"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for unknown log format")
+	}
+}
+
+func TestValidateLogLevel(t *testing.T) {
+	cfg := validConfig()
+	cfg.Log.Level = "verbose"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for unknown log level")
+	}
+}
+
+func TestValidateTracing(t *testing.T) {
+	cfg := validConfig()
+	cfg.Tracing.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for tracing enabled without endpoint")
+	}
+
+	cfg.Tracing.Endpoint = "localhost:4318"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Tracing.SampleRatio = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for sample_ratio above 1")
+	}
+}
+
+func TestValidateTrends(t *testing.T) {
+	cfg := validConfig()
+	cfg.Trends = TrendsConfig{Runs: 30, RegressionThreshold: 0.5}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, trends := range []TrendsConfig{
+		{Runs: MaxTrendRuns + 1},
+		{Runs: -1},
+		{RegressionThreshold: -0.1},
+	} {
+		cfg.Trends = trends
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for trends %+v", trends)
+		}
+	}
+}
+
+func TestValidateScheduled(t *testing.T) {
+	cfg := validConfig()
+	cfg.GitHub.Repositories[0].Scheduled = []string{"nightly.yml"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, wf := range []string{"", ".github/workflows/nightly.yml"} {
+		cfg.GitHub.Repositories[0].Scheduled = []string{wf}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "scheduled[0]") {
+			t.Errorf("Validate() with scheduled %q = %v, want scheduled[0] error", wf, err)
+		}
+	}
+}
+
+func TestValidateCompare(t *testing.T) {
+	cfg := validConfig()
+	cfg.GitHub.Repositories[0].Compare = []BranchPair{{Base: "master", Head: "develop"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, pair := range []BranchPair{{Base: "master"}, {Head: "develop"}, {Base: "develop", Head: "develop"}} {
+		cfg.GitHub.Repositories[0].Compare = []BranchPair{pair}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "compare[0]") {
+			t.Errorf("Validate() with compare %+v = %v, want compare[0] error", pair, err)
+		}
+	}
+
+	cfg.GitHub.Repositories[0].Compare = nil
+	cfg.FetchIntervals.Branches = -time.Minute
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "fetch_intervals.branches") {
+		t.Errorf("Validate() with negative branches interval = %v, want error", err)
+	}
+}
+
+func TestReleaseBranchName(t *testing.T) {
+	tests := []struct {
+		repo RepositoryConfig
+		want string
+	}{
+		{RepositoryConfig{Branches: []string{"develop", "main"}}, "main"},
+		{RepositoryConfig{Branches: []string{"develop"}}, "develop"},
+		{RepositoryConfig{Branches: []string{"develop", "master"}, ReleaseBranch: "release/1.x"}, "release/1.x"},
+	}
+	for _, tt := range tests {
+		if got := tt.repo.ReleaseBranchName(); got != tt.want {
+			t.Errorf("ReleaseBranchName() of %+v = %q, want %q", tt.repo, got, tt.want)
+		}
+	}
+
+	cfg := validConfig()
+	cfg.FetchIntervals.Releases = -time.Minute
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "fetch_intervals.releases") {
+		t.Errorf("Validate() with negative releases interval = %v, want error", err)
+	}
+}
+
+func TestValidateProjects(t *testing.T) {
+	cfg := validConfig()
+	cfg.GitHub.Projects = []ProjectConfig{{Number: 12}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := cfg.GitHub.Projects[0].StatusFieldName(); got != "Status" {
+		t.Errorf("StatusFieldName() = %q, want Status", got)
+	}
+
+	cfg.GitHub.Projects = []ProjectConfig{{StatusField: "Stage"}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "github.projects[0].number") {
+		t.Errorf("Validate() without project number = %v, want error", err)
+	}
+
+	cfg.GitHub.Projects = nil
+	cfg.FetchIntervals.Planning = -time.Minute
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "fetch_intervals.planning") {
+		t.Errorf("Validate() with negative planning interval = %v, want error", err)
+	}
+}
+
+func TestValidateDeploymentsInterval(t *testing.T) {
+	cfg := validConfig()
+	cfg.FetchIntervals.Deployments = -time.Minute
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "fetch_intervals.deployments") {
+		t.Errorf("Validate() with negative deployments interval = %v, want error", err)
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	stack := func() []RepositoryConfig {
+		return []RepositoryConfig{
+			{Name: "eckit", Branches: []string{"develop"}},
+			{Name: "metkit", Branches: []string{"develop"}, DependsOn: []string{"eckit"}},
+			{Name: "fdb", Branches: []string{"develop"}, DependsOn: []string{"eckit", "metkit"}},
+		}
+	}
+	cfg := validConfig()
+	cfg.GitHub.Repositories = stack()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		deps map[int][]string
+		want string
+	}{
+		{"self", map[int][]string{0: {"eckit"}}, "must not name the repository itself"},
+		{"unknown", map[int][]string{1: {"atlas"}}, `repository "atlas" is not configured`},
+		{"cycle", map[int][]string{0: {"fdb"}}, "cycle: eckit -> fdb -> eckit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.GitHub.Repositories = stack()
+			for i, deps := range tt.deps {
+				cfg.GitHub.Repositories[i].DependsOn = deps
+			}
+			if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFailures(t *testing.T) {
+	cfg := validConfig()
+	cfg.Failures = FailuresConfig{Annotations: 20, LogLines: 50}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, failures := range []FailuresConfig{
+		{Annotations: MaxFailureAnnotations + 1},
+		{Annotations: -1},
+		{LogLines: MaxFailureLogLines + 1},
+		{LogLines: -1},
+	} {
+		cfg.Failures = failures
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for failures %+v", failures)
+		}
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	cfg := validConfig()
+	cfg.Auth.Enabled = true
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected error for auth enabled without settings")
+	}
+	for _, key := range []string{"auth.issuer", "auth.client_id", "auth.redirect_url", "auth.session_secret"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error should mention %s: %v", key, err)
+		}
+	}
+
+	cfg.Auth = AuthConfig{
+		Enabled:       true,
+		Issuer:        "https://dex.example.com",
+		ClientID:      "ecmwf-dash",
+		RedirectURL:   "https://dash.example.com/auth/callback",
+		SessionSecret: strings.Repeat("x", MinSessionSecretLen),
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Auth.RedirectURL = "https://dash.example.com/callback"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for redirect_url not ending in /auth/callback")
+	}
+	cfg.Auth.RedirectURL = "https://dash.example.com/auth/callback"
+
+	for _, tokens := range [][]KioskTokenConfig{
+		{{Name: "lobby"}},
+		{{Name: "lobby", Token: "short"}},
+		{{Name: "lobby", Token: "long-enough-token", TokenSHA256: strings.Repeat("a", 64)}},
+		{{Name: "lobby", TokenSHA256: "not-hex"}},
+		{{Token: "long-enough-token"}},
+		{{Name: "lobby", Token: "long-enough-token"}, {Name: "lobby", Token: "another-long-token"}},
+	} {
+		cfg.Auth.KioskTokens = tokens
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for kiosk tokens %+v", tokens)
+		}
+	}
+
+	cfg.Auth.KioskTokens = []KioskTokenConfig{
+		{Name: "lobby", Token: "long-enough-token"},
+		{Name: "atlas-room", TokenSHA256: strings.Repeat("a", 64), Repos: []string{"atlas"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, gh := range []GitHubOAuthConfig{
+		{ClientID: "gh-app", RedirectURL: "https://dash.example.com/auth/github/callback"},
+		{ClientID: "gh-app", ClientSecret: "s", RedirectURL: "https://dash.example.com/auth/callback"},
+	} {
+		cfg.Auth.GitHubOAuth = gh
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for github_oauth %+v", gh)
+		}
+	}
+	cfg.Auth.GitHubOAuth = GitHubOAuthConfig{ClientID: "gh-app", ClientSecret: "s", RedirectURL: "https://dash.example.com/auth/github/callback"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Auth.VisibilityCacheTTL = -time.Minute
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative visibility_cache_ttl")
+	}
+}
+
+func TestEnvKeysIncludeNestedKeys(t *testing.T) {
+	want := []string{
+		"ECMWF_DASH_GITHUB_ORGANIZATION",
+		"ECMWF_DASH_GITHUB_TOKEN",
+		"ECMWF_DASH_GITHUB_REPOSITORIES",
+		"ECMWF_DASH_FETCH_INTERVALS_ISSUES",
+		"ECMWF_DASH_FETCH_INTERVALS_PULL_REQUESTS",
+		"ECMWF_DASH_FETCH_INTERVALS_ACTIONS",
+		"ECMWF_DASH_SERVER_PORT",
+		"ECMWF_DASH_SERVER_HOST",
+		"ECMWF_DASH_LOG_FORMAT",
+		"ECMWF_DASH_LOG_LEVEL",
+		"ECMWF_DASH_TRACING_ENABLED",
+		"ECMWF_DASH_TRACING_ENDPOINT",
+		"ECMWF_DASH_TRACING_SAMPLE_RATIO",
+		"ECMWF_DASH_AUTH_CLIENT_SECRET",
+		"ECMWF_DASH_AUTH_SESSION_SECRET",
+		"ECMWF_DASH_AUTH_ALLOWED_ORGS",
+		"ECMWF_DASH_AUTH_KIOSK_TOKENS",
+	}
+	got := make(map[string]bool)
+	for _, k := range EnvKeys() {
+		got[k] = true
+	}
+	for _, k := range want {
+		if !got[k] {
+			t.Errorf("EnvKeys() missing %q", k)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is prepended to the upper-cased YAML path of every config key to
+// form its override variable, e.g. server.port → ECMWF_DASH_SERVER_PORT.
+const EnvPrefix = "ECMWF_DASH_"
+
+var envRefRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${VAR} references in the string values of a parsed
+// config document with the values of the corresponding environment
+// variables. Keys and comments are left alone, and since the document is
+// already parsed, a value cannot change its structure. A bare $ is left
+// untouched, and a reference to an unset variable is an error rather than
+// a silent empty string.
+func expandEnv(doc *yaml.Node) error {
+	var missing []string
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range n.Content {
+				walk(child)
+			}
+		case yaml.MappingNode:
+			for i := 1; i < len(n.Content); i += 2 {
+				walk(n.Content[i])
+			}
+		case yaml.ScalarNode:
+			if !envRefRe.MatchString(n.Value) {
+				return
+			}
+			// An unquoted value is typed by what it expands to, so
+			// port: ${PORT} is still a number.
+			if n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				n.Tag = ""
+			}
+			n.Value = envRefRe.ReplaceAllStringFunc(n.Value, func(ref string) string {
+				name := envRefRe.FindStringSubmatch(ref)[1]
+				val, ok := os.LookupEnv(name)
+				if !ok {
+					missing = append(missing, name)
+					return ref
+				}
+				return val
+			})
+		}
+	}
+	walk(doc)
+	if len(missing) > 0 {
+		return fmt.Errorf("unset environment variables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ApplyEnv overrides config values from ECMWF_DASH_* environment variables.
+// Every YAML key has a variable (see EnvKeys). String values are used
+// verbatim; other values are parsed as YAML, so durations ("5m"), numbers and
+// lists ("[{name: eccodes, branches: [master]}]") use the config file syntax.
+func (c *Config) ApplyEnv() error {
+	var errs []string
+	walkKeys(reflect.ValueOf(c).Elem(), EnvPrefix, func(name string, field reflect.Value) {
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if field.Kind() == reflect.String {
+			field.SetString(val)
+			return
+		}
+		if err := yaml.Unmarshal([]byte(val), field.Addr().Interface()); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", joinErrors(errs))
+	}
+	return nil
+}
+
+// EnvKeys returns the names of all supported override variables.
+func EnvKeys() []string {
+	var keys []string
+	walkKeys(reflect.ValueOf(&Config{}).Elem(), EnvPrefix, func(name string, _ reflect.Value) {
+		keys = append(keys, name)
+	})
+	return keys
+}
+
+// walkKeys calls fn for every leaf field of the struct v, naming it from the
+// prefix and the upper-cased yaml tags along its path. Slices are leaves.
+func walkKeys(v reflect.Value, prefix string, fn func(name string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + strings.ToUpper(tag)
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			walkKeys(field, name+"_", fn)
+			continue
+		}
+		fn(name, field)
+	}
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rate budget assumptions used by Warnings. The estimate is deliberately
+// rough: it only needs to catch intervals that are off by an order of magnitude.
+const (
+	rateLimitPerHour      = 5000 // authenticated GitHub REST API limit
+	rateBudgetShare       = 0.8  // warn above this share of the limit
+	assumedOpenPRsPerRepo = 5    // each costs reviews, get, check runs, workflow runs, statuses
+	minPlausibleInterval  = time.Minute
+	requiredChecksTTL     = 30 * time.Minute // branch protection is re-read this often
+)
+
+// Check loads the config at path like LoadWithOptions and additionally
+// returns non-fatal warnings: unknown YAML keys, duplicate repositories or
+// branches, and fetch intervals that are implausibly short. A non-nil error
+// means the config would be rejected at startup.
+func Check(path string, opts Options) (*Config, []string, error) {
+	cfg, err := LoadWithOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Keys are not expanded, so the file as written has the same ones.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading config file: %w", err)
+	}
+	warnings := unknownKeys(data)
+	warnings = append(warnings, cfg.Warnings()...)
+	return cfg, warnings, nil
+}
+
+var unknownFieldRe = regexp.MustCompile(`^line (\d+): field (\S+) not found in type \S+$`)
+
+// unknownKeys strictly decodes data and returns one warning per key that
+// does not map to a Config field (typically a typo).
+func unknownKeys(data []byte) []string {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var cfg Config
+	err := dec.Decode(&cfg)
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return nil
+	}
+	var warnings []string
+	for _, msg := range typeErr.Errors {
+		if m := unknownFieldRe.FindStringSubmatch(msg); m != nil {
+			warnings = append(warnings, fmt.Sprintf("line %s: unknown key %q", m[1], m[2]))
+		}
+	}
+	return warnings
+}
+
+// Warnings returns problems that do not stop the server from starting but
+// almost certainly indicate a mistake.
+func (c *Config) Warnings() []string {
+	var warnings []string
+
+	seenRepos := make(map[string]bool)
+	for i, repo := range c.GitHub.Repositories {
+		if seenRepos[repo.Name] {
+			warnings = append(warnings, fmt.Sprintf("repository[%d]: duplicate repository %q", i, repo.Name))
+		}
+		seenRepos[repo.Name] = true
+
+		seenBranches := make(map[string]bool)
+		for _, branch := range repo.Branches {
+			if seenBranches[branch] {
+				warnings = append(warnings, fmt.Sprintf("repository[%d] (%s): duplicate branch %q", i, repo.Name, branch))
+			}
+			seenBranches[branch] = true
+		}
+
+		seenWorkflows := make(map[string]bool)
+		for _, wf := range repo.Scheduled {
+			if seenWorkflows[wf] {
+				warnings = append(warnings, fmt.Sprintf("repository[%d] (%s): duplicate scheduled workflow %q", i, repo.Name, wf))
+			}
+			seenWorkflows[wf] = true
+		}
+
+		seenDeps := make(map[string]bool)
+		for _, dep := range repo.DependsOn {
+			if seenDeps[dep] {
+				warnings = append(warnings, fmt.Sprintf("repository[%d] (%s): duplicate dependency %q", i, repo.Name, dep))
+			}
+			seenDeps[dep] = true
+		}
+
+		seenPairs := make(map[BranchPair]bool)
+		for _, pair := range repo.Compare {
+			if seenPairs[pair] {
+				warnings = append(warnings, fmt.Sprintf("repository[%d] (%s): duplicate branch comparison %s...%s", i, repo.Name, pair.Base, pair.Head))
+			}
+			seenPairs[pair] = true
+		}
+	}
+
+	seenProjects := make(map[int]bool)
+	for i, project := range c.GitHub.Projects {
+		if seenProjects[project.Number] {
+			warnings = append(warnings, fmt.Sprintf("github.projects[%d]: duplicate project %d", i, project.Number))
+		}
+		seenProjects[project.Number] = true
+	}
+
+	for _, iv := range []struct {
+		key      string
+		interval time.Duration
+	}{
+		{"fetch_intervals.issues", c.FetchIntervals.Issues},
+		{"fetch_intervals.pull_requests", c.FetchIntervals.PullRequests},
+		{"fetch_intervals.actions", c.FetchIntervals.Actions},
+		{"fetch_intervals.branches", c.FetchIntervals.Branches},
+		{"fetch_intervals.releases", c.FetchIntervals.Releases},
+		{"fetch_intervals.planning", c.FetchIntervals.Planning},
+		{"fetch_intervals.deployments", c.FetchIntervals.Deployments},
+	} {
+		if iv.interval > 0 && iv.interval < minPlausibleInterval {
+			warnings = append(warnings, fmt.Sprintf("%s is %v; intervals under %v are rarely useful", iv.key, iv.interval, minPlausibleInterval))
+		}
+	}
+
+	a := c.Auth
+	if a.Enabled && len(a.AllowedOrgs)+len(a.AllowedTeams)+len(a.AllowedEmailDomains) == 0 {
+		warnings = append(warnings, "auth is enabled with an empty allowlist; anyone the issuer authenticates can sign in")
+	}
+	if !a.Enabled && len(a.KioskTokens) > 0 {
+		warnings = append(warnings, "auth.kiosk_tokens have no effect while auth is disabled")
+	}
+	for i, k := range a.KioskTokens {
+		for _, repo := range k.Repos {
+			if !seenRepos[repo] {
+				warnings = append(warnings, fmt.Sprintf("auth.kiosk_tokens[%d] (%s): repository %q is not configured", i, k.Name, repo))
+			}
+		}
+	}
+
+	if est := c.EstimateHourlyRequests(); est > rateLimitPerHour*rateBudgetShare {
+		warnings = append(warnings, fmt.Sprintf("estimated %d GitHub API requests/hour exceeds %d%% of the %d/hour rate limit; lengthen fetch_intervals",
+			est, int(rateBudgetShare*100), rateLimitPerHour))
+	}
+
+	return warnings
+}
+
+// EstimateHourlyRequests approximates the GitHub API requests per hour the
+// fetchers will make with this config, assuming single-page list responses
+// and assumedOpenPRsPerRepo open pull requests per repository.
+func (c *Config) EstimateHourlyRequests() int {
+	repos := len(c.GitHub.Repositories)
+	branches, scheduled, compares := 0, 0, 0
+	for _, repo := range c.GitHub.Repositories {
+		branches += len(repo.Branches)
+		scheduled += len(repo.Scheduled)
+		compares += len(repo.Compare)
+	}
+
+	perHour := func(d time.Duration) float64 {
+		if d <= 0 {
+			return 0
+		}
+		return float64(time.Hour) / float64(d)
+	}
+
+	issues := float64(repos) * perHour(c.FetchIntervals.Issues)
+	prs := float64(repos*(1+5*assumedOpenPRsPerRepo)) * perHour(c.FetchIntervals.PullRequests)
+	checks := float64(branches*4+scheduled) * perHour(c.FetchIntervals.Actions)
+	checks += float64(branches*2) * perHour(requiredChecksTTL)
+	branchesInterval := c.FetchIntervals.Branches
+	if branchesInterval == 0 {
+		branchesInterval = DefaultBranchesInterval
+	}
+	comparisons := float64(compares) * perHour(branchesInterval)
+	releasesInterval := c.FetchIntervals.Releases
+	if releasesInterval == 0 {
+		releasesInterval = DefaultReleasesInterval
+	}
+	// Latest and recent releases, tags, commits since the tag and milestones.
+	releases := float64(repos*5) * perHour(releasesInterval)
+	planningInterval := c.FetchIntervals.Planning
+	if planningInterval == 0 {
+		planningInterval = DefaultPlanningInterval
+	}
+	// Milestones per repository; board, fields and one page of items per project.
+	planning := float64(repos+3*len(c.GitHub.Projects)) * perHour(planningInterval)
+	deploymentsInterval := c.FetchIntervals.Deployments
+	if deploymentsInterval == 0 {
+		deploymentsInterval = DefaultDeploymentsInterval
+	}
+	// Deployment list and the statuses of the latest five deployments,
+	// assuming one environment per repository.
+	deployments := float64(repos*6) * perHour(deploymentsInterval)
+	return int(issues + prs + checks + comparisons + releases + planning + deployments)
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck_UnknownKeys(t *testing.T) {
+	path := writeConfig(t, `github:
+  organization: ecmwf
+  repositories:
+    - name: eccodes
+      branchs: [develop]
+      branches: [master]
+fetch_intervals:
+  issues: 30m
+  pull_requests: 10m
+  actions: 5m
+server:
+  host: "0.0.0.0"
+  port: 8000
+servr:
+  port: 9000
+`)
+
+	_, warnings, err := Check(path, Options{})
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], `line 5: unknown key "branchs"`) {
+		t.Errorf("warnings[0] = %q, want unknown key branchs on line 5", warnings[0])
+	}
+	if !strings.Contains(warnings[1], `unknown key "servr"`) {
+		t.Errorf("warnings[1] = %q, want unknown key servr", warnings[1])
+	}
+}
+
+func TestCheck_InvalidConfig(t *testing.T) {
+	path := writeConfig(t, strings.Replace(precedenceConfig, "port: 8000", "port: 0", 1))
+
+	if _, _, err := Check(path, Options{}); err == nil {
+		t.Fatal("expected error for invalid config")
+	}
+}
+
+func TestCheck_CleanConfig(t *testing.T) {
+	path := writeConfig(t, precedenceConfig)
+
+	_, warnings, err := Check(path, Options{})
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestWarnings_Duplicates(t *testing.T) {
+	cfg := validConfig()
+	cfg.GitHub.Repositories = []RepositoryConfig{
+		{Name: "eccodes", Branches: []string{"master", "develop", "master"}, Compare: []BranchPair{{Base: "master", Head: "develop"}, {Base: "master", Head: "develop"}}},
+		{Name: "atlas", Branches: []string{"main"}, Scheduled: []string{"nightly.yml", "nightly.yml"}, DependsOn: []string{"eccodes", "eccodes"}},
+		{Name: "eccodes", Branches: []string{"develop"}},
+	}
+	cfg.GitHub.Projects = []ProjectConfig{{Number: 4}, {Number: 4, StatusField: "Stage"}}
+
+	warnings := cfg.Warnings()
+	if len(warnings) != 6 {
+		t.Fatalf("expected 6 warnings, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], `duplicate branch "master"`) {
+		t.Errorf("warnings[0] = %q, want duplicate branch", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "duplicate branch comparison master...develop") {
+		t.Errorf("warnings[1] = %q, want duplicate branch comparison", warnings[1])
+	}
+	if !strings.Contains(warnings[2], `duplicate scheduled workflow "nightly.yml"`) {
+		t.Errorf("warnings[2] = %q, want duplicate scheduled workflow", warnings[2])
+	}
+	if !strings.Contains(warnings[3], `duplicate dependency "eccodes"`) {
+		t.Errorf("warnings[3] = %q, want duplicate dependency", warnings[3])
+	}
+	if !strings.Contains(warnings[4], `repository[2]: duplicate repository "eccodes"`) {
+		t.Errorf("warnings[4] = %q, want duplicate repository", warnings[4])
+	}
+	if !strings.Contains(warnings[5], "github.projects[1]: duplicate project 4") {
+		t.Errorf("warnings[5] = %q, want duplicate project", warnings[5])
+	}
+}
+
+func TestWarnings_ShortIntervals(t *testing.T) {
+	cfg := validConfig()
+	cfg.FetchIntervals.Actions = 10 * time.Second
+
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "fetch_intervals.actions") {
+		t.Errorf("expected one warning about fetch_intervals.actions, got %v", warnings)
+	}
+}
+
+func TestWarnings_RateBudget(t *testing.T) {
+	cfg := validConfig()
+	for i := 0; i < 40; i++ {
+		cfg.GitHub.Repositories = append(cfg.GitHub.Repositories, RepositoryConfig{Name: fmt.Sprintf("repo%d", i), Branches: []string{"master", "develop"}})
+	}
+	cfg.FetchIntervals.PullRequests = 2 * time.Minute
+
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "rate limit") {
+		t.Errorf("expected one rate limit warning, got %v", warnings)
+	}
+}
+
+func TestWarnings_OpenAuthAllowlist(t *testing.T) {
+	cfg := validConfig()
+	cfg.Auth.Enabled = true
+
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "allowlist") {
+		t.Errorf("expected one allowlist warning, got %v", warnings)
+	}
+
+	cfg.Auth.AllowedEmailDomains = []string{"ecmwf.int"}
+	if warnings := cfg.Warnings(); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestWarnings_KioskTokenUnknownRepo(t *testing.T) {
+	cfg := validConfig()
+	cfg.Auth.Enabled = true
+	cfg.Auth.AllowedOrgs = []string{"ecmwf"}
+	cfg.Auth.KioskTokens = []KioskTokenConfig{{Name: "lobby", Token: "long-enough-token", Repos: []string{"nosuchrepo"}}}
+
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "nosuchrepo") {
+		t.Errorf("expected one warning about nosuchrepo, got %v", warnings)
+	}
+}
+
+func TestEstimateHourlyRequests(t *testing.T) {
+	cfg := validConfig() // 1 repo, 2 branches; 30m / 10m / 5m
+
+	// issues 1*2 + prs (1+5*5)*6 + checks 2*4*12 + protection 2*2*2 + releases 1*5*2 + milestones 1*2
+	// + deployments 1*6*4
+	want := 2 + 156 + 96 + 8 + 10 + 2 + 24
+	if got := cfg.EstimateHourlyRequests(); got != want {
+		t.Errorf("EstimateHourlyRequests() = %d, want %d", got, want)
+	}
+
+	cfg.GitHub.Projects = []ProjectConfig{{Number: 1}}
+	if got := cfg.EstimateHourlyRequests(); got != want+3*2 {
+		t.Errorf("EstimateHourlyRequests() with a project = %d, want %d", got, want+3*2)
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Watch re-runs LoadWithOptions whenever the file at path changes on disk
+// (polled every pollInterval) or a value arrives on trigger (e.g. SIGHUP).
+// Each new config that passes validation is handed to apply; an invalid config
+// is logged and discarded so the previous one keeps running. Watch blocks
+// until ctx is done.
+func Watch(ctx context.Context, path string, opts Options, pollInterval time.Duration, trigger <-chan os.Signal, apply func(*Config)) {
+	last := fileStamp(path)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-trigger:
+			slog.Info("config reload requested", "path", path)
+			last = fileStamp(path)
+			reload(path, opts, apply)
+		case <-ticker.C:
+			stamp := fileStamp(path)
+			if stamp == last {
+				continue
+			}
+			last = stamp
+			slog.Info("config file changed, reloading", "path", path)
+			reload(path, opts, apply)
+		}
+	}
+}
+
+func reload(path string, opts Options, apply func(*Config)) {
+	cfg, err := LoadWithOptions(path, opts)
+	if err != nil {
+		slog.Error("config reload rejected, keeping previous config", "path", path, "error", err)
+		return
+	}
+	apply(cfg)
+}
+
+// stamp identifies a version of a file on disk. Size is included because
+// some filesystems have coarse mtime granularity.
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// fileStamp returns the zero stamp if the file cannot be stat'ed (e.g. while
+// an editor is replacing it), so its reappearance is detected as a change.
+func fileStamp(path string) stamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return stamp{}
+	}
+	return stamp{modTime: info.ModTime(), size: info.Size()}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const watchTestConfig = `github:
+  organization: ecmwf
+  repositories:
+    - name: %s
+      branches: [master]
+fetch_intervals:
+  issues: 30m
+  pull_requests: 10m
+  actions: 5m
+server:
+  host: "0.0.0.0"
+  port: 8000
+`
+
+func writeWatchConfig(t *testing.T, path, repo string) {
+	t.Helper()
+	content := []byte(fmt.Sprintf(watchTestConfig, repo))
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+// startWatch runs Watch in the background and returns a channel of applied configs.
+func startWatch(t *testing.T, path string, trigger chan os.Signal) <-chan *Config {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	applied := make(chan *Config, 4)
+	go Watch(ctx, path, Options{}, 10*time.Millisecond, trigger, func(c *Config) { applied <- c })
+	return applied
+}
+
+func TestWatch_ReloadsOnFileChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeWatchConfig(t, path, "eccodes")
+
+	applied := startWatch(t, path, nil)
+	// Let Watch record the initial file stamp before changing it.
+	time.Sleep(50 * time.Millisecond)
+
+	// Different size guarantees a new stamp even with coarse mtimes.
+	writeWatchConfig(t, path, "atlas-and-more")
+
+	select {
+	case cfg := <-applied:
+		if got := cfg.GitHub.Repositories[0].Name; got != "atlas-and-more" {
+			t.Errorf("reloaded repo = %q, want %q", got, "atlas-and-more")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("config change was not applied")
+	}
+}
+
+func TestWatch_ReloadsOnTrigger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeWatchConfig(t, path, "eccodes")
+
+	trigger := make(chan os.Signal, 1)
+	applied := startWatch(t, path, trigger)
+	trigger <- os.Interrupt
+
+	select {
+	case cfg := <-applied:
+		if got := cfg.GitHub.Repositories[0].Name; got != "eccodes" {
+			t.Errorf("reloaded repo = %q, want %q", got, "eccodes")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("trigger did not reload config")
+	}
+}
+
+func TestWatch_RejectsInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeWatchConfig(t, path, "eccodes")
+
+	trigger := make(chan os.Signal, 1)
+	applied := startWatch(t, path, trigger)
+
+	// Empty repository name fails validation.
+	writeWatchConfig(t, path, `""`)
+	trigger <- os.Interrupt
+
+	select {
+	case cfg := <-applied:
+		t.Fatalf("invalid config should not be applied, got %+v", cfg.GitHub)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -2,12 +2,16 @@ package fetcher
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/ozaq/ecmwf-dash/internal/config"
 	"github.com/ozaq/ecmwf-dash/internal/github"
 	"github.com/ozaq/ecmwf-dash/internal/storage"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // GitHubFetcher abstracts the GitHub API calls used by the Fetcher.
@@ -15,7 +19,12 @@ import (
 type GitHubFetcher interface {
 	FetchIssues(ctx context.Context, org string, repos []config.RepositoryConfig) github.IssuesFetchResult
 	FetchPullRequests(ctx context.Context, org string, repos []config.RepositoryConfig) github.PRsFetchResult
-	FetchBranchChecks(ctx context.Context, org string, repos []config.RepositoryConfig) github.ChecksFetchResult
+	FetchMergedPullRequests(ctx context.Context, org string, prs []github.PullRequest) ([]github.PullRequest, github.RateInfo)
+	FetchBranchChecks(ctx context.Context, org string, repos []config.RepositoryConfig, failures config.FailuresConfig) github.ChecksFetchResult
+	FetchBranchComparisons(ctx context.Context, org string, repos []config.RepositoryConfig) github.ComparisonsFetchResult
+	FetchReleaseInfo(ctx context.Context, org string, repos []config.RepositoryConfig) github.ReleasesFetchResult
+	FetchPlanning(ctx context.Context, org string, repos []config.RepositoryConfig, projects []config.ProjectConfig) github.PlanningFetchResult
+	FetchDeployments(ctx context.Context, org string, repos []config.RepositoryConfig) github.DeploymentsFetchResult
 	LogRate(r github.RateInfo)
 }
 
@@ -23,16 +32,20 @@ type GitHubFetcher interface {
 var _ GitHubFetcher = (*github.Client)(nil)
 
 type Fetcher struct {
-	cfg     *config.Config
+	mu       sync.RWMutex
+	cfg      *config.Config
+	reloaded chan struct{} // closed (and replaced) by UpdateConfig
+
 	gh      GitHubFetcher
 	storage storage.Store
 }
 
 func New(cfg *config.Config, gh GitHubFetcher, store storage.Store) *Fetcher {
 	return &Fetcher{
-		cfg:     cfg,
-		gh:      gh,
-		storage: store,
+		cfg:      cfg,
+		reloaded: make(chan struct{}),
+		gh:       gh,
+		storage:  store,
 	}
 }
 
@@ -40,100 +53,314 @@ func (f *Fetcher) Start(ctx context.Context) {
 	go f.runIssuesFetcher(ctx)
 	go f.runPRsFetcher(ctx)
 	go f.runBranchChecksFetcher(ctx)
+	go f.runBranchComparisonsFetcher(ctx)
+	go f.runReleaseInfoFetcher(ctx)
+	go f.runPlanningFetcher(ctx)
+	go f.runDeploymentsFetcher(ctx)
 }
 
-func (f *Fetcher) runIssuesFetcher(ctx context.Context) {
-	f.fetchIssues(ctx)
-	ticker := time.NewTicker(f.cfg.FetchIntervals.Issues)
+// UpdateConfig atomically replaces the configuration used by all fetch loops.
+// Each loop fetches immediately with the new repo list and restarts its
+// ticker with the new interval.
+func (f *Fetcher) UpdateConfig(cfg *config.Config) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cfg = cfg
+	close(f.reloaded)
+	f.reloaded = make(chan struct{})
+}
+
+// config returns the current configuration.
+func (f *Fetcher) config() *config.Config {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.cfg
+}
+
+// configAndReload returns the current configuration together with the
+// channel that will be closed on the next UpdateConfig.
+func (f *Fetcher) configAndReload() (*config.Config, <-chan struct{}) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.cfg, f.reloaded
+}
+
+// runLoop calls fetch immediately, then every interval(cfg), and again
+// straight away whenever the config is reloaded.
+func (f *Fetcher) runLoop(ctx context.Context, interval func(*config.Config) time.Duration, fetch func(context.Context)) {
+	cfg, reloaded := f.configAndReload()
+	fetch(ctx)
+	ticker := time.NewTicker(interval(cfg))
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			f.fetchIssues(ctx)
+			fetch(ctx)
+		case <-reloaded:
+			cfg, reloaded = f.configAndReload()
+			ticker.Reset(interval(cfg))
+			fetch(ctx)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// startCycle starts the span covering one fetch cycle of a category.
+func startCycle(ctx context.Context, category string, cfg *config.Config) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "fetch cycle "+category, trace.WithAttributes(
+		attribute.String("category", category),
+		attribute.String("org", cfg.GitHub.Organization),
+		attribute.Int("repos", len(cfg.GitHub.Repositories)),
+	))
+}
+
+// endCycle annotates the cycle span with the per-repo outcome and ends it.
+func endCycle(span trace.Span, failedRepos []string, err error) {
+	span.SetAttributes(attribute.StringSlice("failed_repos", failedRepos))
+	tracing.EndSpan(span, err)
+}
+
+func (f *Fetcher) runIssuesFetcher(ctx context.Context) {
+	f.runLoop(ctx, func(c *config.Config) time.Duration { return c.FetchIntervals.Issues }, f.fetchIssues)
+}
+
 func (f *Fetcher) fetchIssues(ctx context.Context) {
-	log.Printf("Fetching issues for %s", f.cfg.GitHub.Organization)
+	cfg := f.config()
+	ctx, span := startCycle(ctx, storage.CategoryIssues, cfg)
+	logger := slog.With("category", storage.CategoryIssues, "org", cfg.GitHub.Organization)
+	logger.Info("fetch started", "repos", len(cfg.GitHub.Repositories))
 
-	result := f.gh.FetchIssues(ctx, f.cfg.GitHub.Organization, f.cfg.GitHub.Repositories)
+	result := f.gh.FetchIssues(ctx, cfg.GitHub.Organization, cfg.GitHub.Repositories)
+	defer endCycle(span, result.FailedRepos, result.Err)
 	if result.Err != nil {
-		log.Printf("Error fetching issues: %v", result.Err)
+		logger.Error("fetch failed", "error", result.Err)
 		return
 	}
 
 	if len(result.FailedRepos) > 0 {
-		log.Printf("Issues: partial failure for repos: %v", result.FailedRepos)
+		logger.Warn("fetch partially failed", "failed_repos", result.FailedRepos)
 	}
 	f.storage.MergeIssues(result.Issues, result.FailedRepos, result.SucceededRepos)
-	log.Printf("Fetched %d issues", len(result.Issues))
+	logger.Info("fetch completed", "count", len(result.Issues), "succeeded_repos", len(result.SucceededRepos), "rate", result.Rate)
 	f.gh.LogRate(result.Rate)
 }
 
 func (f *Fetcher) runPRsFetcher(ctx context.Context) {
-	f.fetchPullRequests(ctx)
-	ticker := time.NewTicker(f.cfg.FetchIntervals.PullRequests)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			f.fetchPullRequests(ctx)
-		case <-ctx.Done():
-			return
-		}
-	}
+	f.runLoop(ctx, func(c *config.Config) time.Duration { return c.FetchIntervals.PullRequests }, f.fetchPullRequests)
 }
 
 func (f *Fetcher) fetchPullRequests(ctx context.Context) {
-	log.Printf("Fetching pull requests for %s", f.cfg.GitHub.Organization)
+	cfg := f.config()
+	ctx, span := startCycle(ctx, storage.CategoryPRs, cfg)
+	logger := slog.With("category", storage.CategoryPRs, "org", cfg.GitHub.Organization)
+	logger.Info("fetch started", "repos", len(cfg.GitHub.Repositories))
 
-	result := f.gh.FetchPullRequests(ctx, f.cfg.GitHub.Organization, f.cfg.GitHub.Repositories)
+	result := f.gh.FetchPullRequests(ctx, cfg.GitHub.Organization, cfg.GitHub.Repositories)
+	defer endCycle(span, result.FailedRepos, result.Err)
 	if result.Err != nil {
-		log.Printf("Error fetching pull requests: %v", result.Err)
+		logger.Error("fetch failed", "error", result.Err)
 		return
 	}
 
 	if len(result.FailedRepos) > 0 {
-		log.Printf("Pull requests: partial failure for repos: %v", result.FailedRepos)
+		logger.Warn("fetch partially failed", "failed_repos", result.FailedRepos)
 	}
+	before, _ := f.storage.GetPullRequests()
 	f.storage.MergePullRequests(result.PullRequests, result.FailedRepos, result.SucceededRepos)
-	log.Printf("Fetched %d pull requests", len(result.PullRequests))
-	f.gh.LogRate(result.Rate)
+	rate := result.Rate
+	if closed := closedPullRequests(before, result); len(closed) > 0 {
+		merged, mergedRate := f.gh.FetchMergedPullRequests(ctx, cfg.GitHub.Organization, closed)
+		f.storage.RecordMergedPullRequests(merged)
+		if mergedRate.Limit > 0 {
+			rate = mergedRate
+		}
+		logger.Info("closed pull requests checked", "closed", len(closed), "merged", len(merged))
+	}
+	logger.Info("fetch completed", "count", len(result.PullRequests), "succeeded_repos", len(result.SucceededRepos), "rate", rate)
+	f.gh.LogRate(rate)
 }
 
-func (f *Fetcher) runBranchChecksFetcher(ctx context.Context) {
-	f.fetchBranchChecks(ctx)
-	ticker := time.NewTicker(f.cfg.FetchIntervals.Actions)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			f.fetchBranchChecks(ctx)
-		case <-ctx.Done():
-			return
+// closedPullRequests returns the pull requests of before that are no
+// longer open in the successfully fetched repositories of result.
+func closedPullRequests(before []github.PullRequest, result github.PRsFetchResult) []github.PullRequest {
+	succeeded := make(map[string]bool, len(result.SucceededRepos))
+	for _, name := range result.SucceededRepos {
+		succeeded[name] = true
+	}
+	open := make(map[string]map[int]bool)
+	for _, pr := range result.PullRequests {
+		if open[pr.Repository] == nil {
+			open[pr.Repository] = make(map[int]bool)
 		}
+		open[pr.Repository][pr.Number] = true
 	}
+	var closed []github.PullRequest
+	for _, pr := range before {
+		if succeeded[pr.Repository] && !open[pr.Repository][pr.Number] {
+			closed = append(closed, pr)
+		}
+	}
+	return closed
+}
+
+func (f *Fetcher) runBranchChecksFetcher(ctx context.Context) {
+	f.runLoop(ctx, func(c *config.Config) time.Duration { return c.FetchIntervals.Actions }, f.fetchBranchChecks)
 }
 
 func (f *Fetcher) fetchBranchChecks(ctx context.Context) {
-	log.Printf("Fetching branch checks for %s", f.cfg.GitHub.Organization)
+	cfg := f.config()
+	ctx, span := startCycle(ctx, storage.CategoryChecks, cfg)
+	logger := slog.With("category", storage.CategoryChecks, "org", cfg.GitHub.Organization)
+	logger.Info("fetch started", "repos", len(cfg.GitHub.Repositories))
 
-	result := f.gh.FetchBranchChecks(ctx, f.cfg.GitHub.Organization, f.cfg.GitHub.Repositories)
+	result := f.gh.FetchBranchChecks(ctx, cfg.GitHub.Organization, cfg.GitHub.Repositories, cfg.Failures)
+	defer endCycle(span, result.FailedRepos, result.Err)
 	if result.Err != nil {
-		log.Printf("Error fetching branch checks: %v", result.Err)
+		logger.Error("fetch failed", "error", result.Err)
 		return
 	}
 
 	if len(result.FailedRepos) > 0 {
-		log.Printf("Branch checks: partial failure for repos: %v", result.FailedRepos)
+		logger.Warn("fetch partially failed", "failed_repos", result.FailedRepos)
 	}
 	f.storage.MergeBranchChecks(result.BranchChecks, result.FailedRepos, result.SucceededRepos)
-	log.Printf("Fetched %d branch checks", len(result.BranchChecks))
+	f.storage.MergeScheduledWorkflows(result.Scheduled, result.FailedRepos)
+	logger.Info("fetch completed", "count", len(result.BranchChecks), "succeeded_repos", len(result.SucceededRepos), "rate", result.Rate)
+	f.gh.LogRate(result.Rate)
+}
+
+// BranchesInterval returns how often branch pairs are compared.
+func BranchesInterval(c *config.Config) time.Duration {
+	if c.FetchIntervals.Branches > 0 {
+		return c.FetchIntervals.Branches
+	}
+	return config.DefaultBranchesInterval
+}
+
+func (f *Fetcher) runBranchComparisonsFetcher(ctx context.Context) {
+	f.runLoop(ctx, BranchesInterval, f.fetchBranchComparisons)
+}
+
+func (f *Fetcher) fetchBranchComparisons(ctx context.Context) {
+	cfg := f.config()
+	ctx, span := startCycle(ctx, storage.CategoryBranches, cfg)
+	logger := slog.With("category", storage.CategoryBranches, "org", cfg.GitHub.Organization)
+	logger.Info("fetch started", "repos", len(cfg.GitHub.Repositories))
+
+	result := f.gh.FetchBranchComparisons(ctx, cfg.GitHub.Organization, cfg.GitHub.Repositories)
+	defer endCycle(span, result.FailedRepos, result.Err)
+	if result.Err != nil {
+		logger.Error("fetch failed", "error", result.Err)
+		return
+	}
+
+	if len(result.FailedRepos) > 0 {
+		logger.Warn("fetch partially failed", "failed_repos", result.FailedRepos)
+	}
+	f.storage.MergeBranchComparisons(result.Comparisons, result.FailedRepos, result.SucceededRepos)
+	logger.Info("fetch completed", "count", len(result.Comparisons), "succeeded_repos", len(result.SucceededRepos), "rate", result.Rate)
+	f.gh.LogRate(result.Rate)
+}
+
+// ReleasesInterval returns how often releases, tags and milestones are
+// fetched.
+func ReleasesInterval(c *config.Config) time.Duration {
+	if c.FetchIntervals.Releases > 0 {
+		return c.FetchIntervals.Releases
+	}
+	return config.DefaultReleasesInterval
+}
+
+func (f *Fetcher) runReleaseInfoFetcher(ctx context.Context) {
+	f.runLoop(ctx, ReleasesInterval, f.fetchReleaseInfo)
+}
+
+func (f *Fetcher) fetchReleaseInfo(ctx context.Context) {
+	cfg := f.config()
+	ctx, span := startCycle(ctx, storage.CategoryReleases, cfg)
+	logger := slog.With("category", storage.CategoryReleases, "org", cfg.GitHub.Organization)
+	logger.Info("fetch started", "repos", len(cfg.GitHub.Repositories))
+
+	result := f.gh.FetchReleaseInfo(ctx, cfg.GitHub.Organization, cfg.GitHub.Repositories)
+	defer endCycle(span, result.FailedRepos, result.Err)
+	if result.Err != nil {
+		logger.Error("fetch failed", "error", result.Err)
+		return
+	}
+
+	if len(result.FailedRepos) > 0 {
+		logger.Warn("fetch partially failed", "failed_repos", result.FailedRepos)
+	}
+	f.storage.MergeReleaseInfo(result.Releases, result.FailedRepos, result.SucceededRepos)
+	logger.Info("fetch completed", "count", len(result.Releases), "succeeded_repos", len(result.SucceededRepos), "rate", result.Rate)
+	f.gh.LogRate(result.Rate)
+}
+
+// PlanningInterval returns how often milestones and project boards are
+// fetched.
+func PlanningInterval(c *config.Config) time.Duration {
+	if c.FetchIntervals.Planning > 0 {
+		return c.FetchIntervals.Planning
+	}
+	return config.DefaultPlanningInterval
+}
+
+func (f *Fetcher) runPlanningFetcher(ctx context.Context) {
+	f.runLoop(ctx, PlanningInterval, f.fetchPlanning)
+}
+
+func (f *Fetcher) fetchPlanning(ctx context.Context) {
+	cfg := f.config()
+	ctx, span := startCycle(ctx, storage.CategoryPlanning, cfg)
+	logger := slog.With("category", storage.CategoryPlanning, "org", cfg.GitHub.Organization)
+	logger.Info("fetch started", "repos", len(cfg.GitHub.Repositories), "projects", len(cfg.GitHub.Projects))
+
+	result := f.gh.FetchPlanning(ctx, cfg.GitHub.Organization, cfg.GitHub.Repositories, cfg.GitHub.Projects)
+	defer endCycle(span, result.FailedRepos, result.Err)
+	if result.Err != nil {
+		logger.Error("fetch failed", "error", result.Err)
+		return
+	}
+
+	if len(result.FailedRepos) > 0 {
+		logger.Warn("fetch partially failed", "failed_repos", result.FailedRepos)
+	}
+	f.storage.MergePlanning(result.Milestones, result.Boards, result.FailedRepos, result.SucceededRepos)
+	logger.Info("fetch completed", "milestones", len(result.Milestones), "boards", len(result.Boards), "succeeded_repos", len(result.SucceededRepos), "rate", result.Rate)
+	f.gh.LogRate(result.Rate)
+}
+
+// DeploymentsInterval returns how often deployments are fetched.
+func DeploymentsInterval(c *config.Config) time.Duration {
+	if c.FetchIntervals.Deployments > 0 {
+		return c.FetchIntervals.Deployments
+	}
+	return config.DefaultDeploymentsInterval
+}
+
+func (f *Fetcher) runDeploymentsFetcher(ctx context.Context) {
+	f.runLoop(ctx, DeploymentsInterval, f.fetchDeployments)
+}
+
+func (f *Fetcher) fetchDeployments(ctx context.Context) {
+	cfg := f.config()
+	ctx, span := startCycle(ctx, storage.CategoryDeployments, cfg)
+	logger := slog.With("category", storage.CategoryDeployments, "org", cfg.GitHub.Organization)
+	logger.Info("fetch started", "repos", len(cfg.GitHub.Repositories))
+
+	result := f.gh.FetchDeployments(ctx, cfg.GitHub.Organization, cfg.GitHub.Repositories)
+	defer endCycle(span, result.FailedRepos, result.Err)
+	if result.Err != nil {
+		logger.Error("fetch failed", "error", result.Err)
+		return
+	}
+
+	if len(result.FailedRepos) > 0 {
+		logger.Warn("fetch partially failed", "failed_repos", result.FailedRepos)
+	}
+	f.storage.MergeDeployments(result.Environments, result.FailedRepos, result.SucceededRepos)
+	logger.Info("fetch completed", "environments", len(result.Environments), "succeeded_repos", len(result.SucceededRepos), "rate", result.Rate)
 	f.gh.LogRate(result.Rate)
 }
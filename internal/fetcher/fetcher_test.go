@@ -10,6 +10,8 @@ import (
 	"github.com/ozaq/ecmwf-dash/internal/config"
 	"github.com/ozaq/ecmwf-dash/internal/github"
 	"github.com/ozaq/ecmwf-dash/internal/storage"
+	"github.com/ozaq/ecmwf-dash/internal/tracing/tracingtest"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // --- Mock GitHubFetcher ---
@@ -20,10 +22,20 @@ type mockGitHubFetcher struct {
 	issuesResult github.IssuesFetchResult
 	prsResult    github.PRsFetchResult
 	checksResult github.ChecksFetchResult
+	cmpResult    github.ComparisonsFetchResult
+	relResult    github.ReleasesFetchResult
+	planResult   github.PlanningFetchResult
+	depResult    github.DeploymentsFetchResult
+	mergedResult []github.PullRequest
 
 	fetchIssuesCalls int
 	fetchPRsCalls    int
 	fetchChecksCalls int
+	fetchCmpCalls    int
+	fetchRelCalls    int
+	fetchPlanCalls   int
+	fetchDepCalls    int
+	lastClosed       []github.PullRequest
 	logRateCalls     int
 	lastRateLogged   github.RateInfo
 
@@ -50,7 +62,14 @@ func (m *mockGitHubFetcher) FetchPullRequests(_ context.Context, org string, rep
 	return m.prsResult
 }
 
-func (m *mockGitHubFetcher) FetchBranchChecks(_ context.Context, org string, repos []config.RepositoryConfig) github.ChecksFetchResult {
+func (m *mockGitHubFetcher) FetchMergedPullRequests(_ context.Context, org string, prs []github.PullRequest) ([]github.PullRequest, github.RateInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastClosed = prs
+	return m.mergedResult, github.RateInfo{}
+}
+
+func (m *mockGitHubFetcher) FetchBranchChecks(_ context.Context, org string, repos []config.RepositoryConfig, _ config.FailuresConfig) github.ChecksFetchResult {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.fetchChecksCalls++
@@ -59,6 +78,42 @@ func (m *mockGitHubFetcher) FetchBranchChecks(_ context.Context, org string, rep
 	return m.checksResult
 }
 
+func (m *mockGitHubFetcher) FetchBranchComparisons(_ context.Context, org string, repos []config.RepositoryConfig) github.ComparisonsFetchResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetchCmpCalls++
+	m.lastOrg = org
+	m.lastRepos = repos
+	return m.cmpResult
+}
+
+func (m *mockGitHubFetcher) FetchReleaseInfo(_ context.Context, org string, repos []config.RepositoryConfig) github.ReleasesFetchResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetchRelCalls++
+	m.lastOrg = org
+	m.lastRepos = repos
+	return m.relResult
+}
+
+func (m *mockGitHubFetcher) FetchPlanning(_ context.Context, org string, repos []config.RepositoryConfig, _ []config.ProjectConfig) github.PlanningFetchResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetchPlanCalls++
+	m.lastOrg = org
+	m.lastRepos = repos
+	return m.planResult
+}
+
+func (m *mockGitHubFetcher) FetchDeployments(_ context.Context, org string, repos []config.RepositoryConfig) github.DeploymentsFetchResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetchDepCalls++
+	m.lastOrg = org
+	m.lastRepos = repos
+	return m.depResult
+}
+
 func (m *mockGitHubFetcher) LogRate(r github.RateInfo) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -75,9 +130,21 @@ type mockStore struct {
 	mergeIssuesCalls   int
 	mergePRsCalls      int
 	mergeChecksCalls   int
+	mergeCmpCalls      int
+	mergeRelCalls      int
+	mergePlanCalls     int
+	mergeDepCalls      int
 	lastIssues         []github.Issue
 	lastPRs            []github.PullRequest
 	lastChecks         []github.BranchCheck
+	lastScheduled      []github.ScheduledWorkflow
+	lastComparisons    []github.BranchComparison
+	lastReleases       []github.ReleaseInfo
+	lastMilestones     []github.Milestone
+	lastBoards         []github.ProjectBoard
+	lastEnvironments   []github.Environment
+	openPRs            []github.PullRequest // returned by GetPullRequests
+	lastMerged         []github.PullRequest
 	lastFailedRepos    []string
 	lastSucceededRepos []string
 }
@@ -100,6 +167,18 @@ func (m *mockStore) MergePullRequests(prs []github.PullRequest, failedRepos, suc
 	m.lastSucceededRepos = succeededRepos
 }
 
+func (m *mockStore) GetPullRequests() ([]github.PullRequest, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.openPRs, time.Time{}
+}
+
+func (m *mockStore) RecordMergedPullRequests(prs []github.PullRequest) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastMerged = prs
+}
+
 func (m *mockStore) MergeBranchChecks(checks []github.BranchCheck, failedRepos, succeededRepos []string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -109,6 +188,49 @@ func (m *mockStore) MergeBranchChecks(checks []github.BranchCheck, failedRepos,
 	m.lastSucceededRepos = succeededRepos
 }
 
+func (m *mockStore) MergeScheduledWorkflows(workflows []github.ScheduledWorkflow, failedRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastScheduled = workflows
+}
+
+func (m *mockStore) MergeBranchComparisons(comparisons []github.BranchComparison, failedRepos, succeededRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mergeCmpCalls++
+	m.lastComparisons = comparisons
+	m.lastFailedRepos = failedRepos
+	m.lastSucceededRepos = succeededRepos
+}
+
+func (m *mockStore) MergeReleaseInfo(infos []github.ReleaseInfo, failedRepos, succeededRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mergeRelCalls++
+	m.lastReleases = infos
+	m.lastFailedRepos = failedRepos
+	m.lastSucceededRepos = succeededRepos
+}
+
+func (m *mockStore) MergePlanning(milestones []github.Milestone, boards []github.ProjectBoard, failedRepos, succeededRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mergePlanCalls++
+	m.lastMilestones = milestones
+	m.lastBoards = boards
+	m.lastFailedRepos = failedRepos
+	m.lastSucceededRepos = succeededRepos
+}
+
+func (m *mockStore) MergeDeployments(envs []github.Environment, failedRepos, succeededRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mergeDepCalls++
+	m.lastEnvironments = envs
+	m.lastFailedRepos = failedRepos
+	m.lastSucceededRepos = succeededRepos
+}
+
 // --- Helpers ---
 
 func testConfig() *config.Config {
@@ -241,6 +363,38 @@ func TestFetchIssues_PartialFailure(t *testing.T) {
 	}
 }
 
+func TestFetchIssues_RecordsCycleSpan(t *testing.T) {
+	exp := tracingtest.Record(t)
+	gh := &mockGitHubFetcher{
+		issuesResult: github.IssuesFetchResult{
+			SucceededRepos: []string{"repo-a"},
+			FailedRepos:    []string{"repo-b"},
+			Rate:           testRate(),
+		},
+	}
+	f := New(testConfig(), gh, &mockStore{})
+
+	f.fetchIssues(context.Background())
+
+	span := tracingtest.Find(exp, "fetch cycle "+storage.CategoryIssues)
+	if span == nil {
+		t.Fatal("fetch cycle span not recorded")
+	}
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["org"] != "testorg" || attrs["category"] != storage.CategoryIssues {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+	if attrs["failed_repos"] != `["repo-b"]` {
+		t.Errorf("failed_repos = %s, want [\"repo-b\"]", attrs["failed_repos"])
+	}
+	if span.Status.Code == codes.Error {
+		t.Error("partial failure should not mark the cycle span as an error")
+	}
+}
+
 func TestFetchPullRequests_Success(t *testing.T) {
 	gh := &mockGitHubFetcher{
 		prsResult: github.PRsFetchResult{
@@ -265,6 +419,33 @@ func TestFetchPullRequests_Success(t *testing.T) {
 	}
 }
 
+func TestFetchPullRequests_RecordsMerges(t *testing.T) {
+	gh := &mockGitHubFetcher{
+		prsResult: github.PRsFetchResult{
+			PullRequests:   []github.PullRequest{{Repository: "testrepo", Number: 10}},
+			SucceededRepos: []string{"testrepo"},
+			FailedRepos:    []string{"otherrepo"},
+			Rate:           testRate(),
+		},
+		mergedResult: []github.PullRequest{{Repository: "testrepo", Number: 9, State: "merged", MergedAt: time.Now()}},
+	}
+	store := &mockStore{openPRs: []github.PullRequest{
+		{Repository: "testrepo", Number: 9},
+		{Repository: "testrepo", Number: 10},
+		{Repository: "otherrepo", Number: 3}, // its repo failed, so not known to be closed
+	}}
+	f := New(testConfig(), gh, store)
+
+	f.fetchPullRequests(context.Background())
+
+	if len(gh.lastClosed) != 1 || gh.lastClosed[0].Number != 9 {
+		t.Errorf("closed pull requests looked up = %v, want only #9", gh.lastClosed)
+	}
+	if len(store.lastMerged) != 1 || store.lastMerged[0].Number != 9 {
+		t.Errorf("merged pull requests recorded = %v, want #9", store.lastMerged)
+	}
+}
+
 func TestFetchPullRequests_TotalFailure(t *testing.T) {
 	gh := &mockGitHubFetcher{
 		prsResult: github.PRsFetchResult{Err: fmt.Errorf("all repos failed")},
@@ -296,6 +477,7 @@ func TestFetchBranchChecks_Success(t *testing.T) {
 					},
 				},
 			},
+			Scheduled:      []github.ScheduledWorkflow{{Repository: "testrepo", File: "nightly.yml"}},
 			SucceededRepos: []string{"testrepo"},
 			Rate:           testRate(),
 		},
@@ -317,6 +499,9 @@ func TestFetchBranchChecks_Success(t *testing.T) {
 	if len(store.lastChecks[0].Checks) != 2 {
 		t.Errorf("expected 2 checks, got %d", len(store.lastChecks[0].Checks))
 	}
+	if len(store.lastScheduled) != 1 {
+		t.Errorf("expected 1 scheduled workflow stored, got %d", len(store.lastScheduled))
+	}
 }
 
 func TestFetchBranchChecks_TotalFailure(t *testing.T) {
@@ -336,6 +521,163 @@ func TestFetchBranchChecks_TotalFailure(t *testing.T) {
 	}
 }
 
+func TestFetchBranchComparisons(t *testing.T) {
+	gh := &mockGitHubFetcher{
+		cmpResult: github.ComparisonsFetchResult{
+			Comparisons:    []github.BranchComparison{{Repository: "testrepo", Base: "main", Head: "develop", AheadBy: 4, BehindBy: 1}},
+			SucceededRepos: []string{"testrepo"},
+			Rate:           testRate(),
+		},
+	}
+	store := &mockStore{}
+	f := New(testConfig(), gh, store)
+
+	f.fetchBranchComparisons(context.Background())
+
+	store.mu.Lock()
+	if store.mergeCmpCalls != 1 || len(store.lastComparisons) != 1 {
+		t.Errorf("expected 1 MergeBranchComparisons call with 1 comparison, got %d calls, %v", store.mergeCmpCalls, store.lastComparisons)
+	}
+	store.mu.Unlock()
+
+	gh.cmpResult = github.ComparisonsFetchResult{Err: fmt.Errorf("all repos failed")}
+	f.fetchBranchComparisons(context.Background())
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.mergeCmpCalls != 1 {
+		t.Errorf("expected no MergeBranchComparisons call on total failure, got %d calls", store.mergeCmpCalls)
+	}
+}
+
+func TestBranchesInterval(t *testing.T) {
+	cfg := testConfig()
+	if got := BranchesInterval(cfg); got != config.DefaultBranchesInterval {
+		t.Errorf("BranchesInterval() unset = %v, want %v", got, config.DefaultBranchesInterval)
+	}
+	cfg.FetchIntervals.Branches = 10 * time.Minute
+	if got := BranchesInterval(cfg); got != 10*time.Minute {
+		t.Errorf("BranchesInterval() = %v, want 10m", got)
+	}
+}
+
+func TestFetchReleaseInfo(t *testing.T) {
+	gh := &mockGitHubFetcher{
+		relResult: github.ReleasesFetchResult{
+			Releases:       []github.ReleaseInfo{{Repository: "testrepo", Branch: "main", LatestTag: "1.2.0", CommitsSinceTag: 7}},
+			SucceededRepos: []string{"testrepo"},
+			Rate:           testRate(),
+		},
+	}
+	store := &mockStore{}
+	f := New(testConfig(), gh, store)
+
+	f.fetchReleaseInfo(context.Background())
+
+	store.mu.Lock()
+	if store.mergeRelCalls != 1 || len(store.lastReleases) != 1 {
+		t.Errorf("expected 1 MergeReleaseInfo call with 1 entry, got %d calls, %v", store.mergeRelCalls, store.lastReleases)
+	}
+	store.mu.Unlock()
+
+	gh.relResult = github.ReleasesFetchResult{Err: fmt.Errorf("all repos failed")}
+	f.fetchReleaseInfo(context.Background())
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.mergeRelCalls != 1 {
+		t.Errorf("expected no MergeReleaseInfo call on total failure, got %d calls", store.mergeRelCalls)
+	}
+}
+
+func TestReleasesInterval(t *testing.T) {
+	cfg := testConfig()
+	if got := ReleasesInterval(cfg); got != config.DefaultReleasesInterval {
+		t.Errorf("ReleasesInterval() unset = %v, want %v", got, config.DefaultReleasesInterval)
+	}
+	cfg.FetchIntervals.Releases = 2 * time.Hour
+	if got := ReleasesInterval(cfg); got != 2*time.Hour {
+		t.Errorf("ReleasesInterval() = %v, want 2h", got)
+	}
+}
+
+func TestFetchPlanning(t *testing.T) {
+	gh := &mockGitHubFetcher{
+		planResult: github.PlanningFetchResult{
+			Milestones:     []github.Milestone{{Repository: "testrepo", Number: 1, Title: "1.0"}},
+			Boards:         []github.ProjectBoard{{Number: 3, Title: "Roadmap"}},
+			SucceededRepos: []string{"testrepo"},
+			Rate:           testRate(),
+		},
+	}
+	store := &mockStore{}
+	f := New(testConfig(), gh, store)
+
+	f.fetchPlanning(context.Background())
+
+	store.mu.Lock()
+	if store.mergePlanCalls != 1 || len(store.lastMilestones) != 1 || len(store.lastBoards) != 1 {
+		t.Errorf("expected 1 MergePlanning call with 1 milestone and 1 board, got %d calls, %v, %v", store.mergePlanCalls, store.lastMilestones, store.lastBoards)
+	}
+	store.mu.Unlock()
+
+	gh.planResult = github.PlanningFetchResult{Err: fmt.Errorf("all repos failed")}
+	f.fetchPlanning(context.Background())
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.mergePlanCalls != 1 {
+		t.Errorf("expected no MergePlanning call on total failure, got %d calls", store.mergePlanCalls)
+	}
+}
+
+func TestPlanningInterval(t *testing.T) {
+	cfg := testConfig()
+	if got := PlanningInterval(cfg); got != config.DefaultPlanningInterval {
+		t.Errorf("PlanningInterval() unset = %v, want %v", got, config.DefaultPlanningInterval)
+	}
+	cfg.FetchIntervals.Planning = time.Hour
+	if got := PlanningInterval(cfg); got != time.Hour {
+		t.Errorf("PlanningInterval() = %v, want 1h", got)
+	}
+}
+
+func TestFetchDeployments(t *testing.T) {
+	gh := &mockGitHubFetcher{
+		depResult: github.DeploymentsFetchResult{
+			Environments:   []github.Environment{{Repository: "testrepo", Name: "docs"}},
+			SucceededRepos: []string{"testrepo"},
+			Rate:           testRate(),
+		},
+	}
+	store := &mockStore{}
+	f := New(testConfig(), gh, store)
+
+	f.fetchDeployments(context.Background())
+
+	store.mu.Lock()
+	if store.mergeDepCalls != 1 || len(store.lastEnvironments) != 1 {
+		t.Errorf("expected 1 MergeDeployments call with 1 environment, got %d calls, %v", store.mergeDepCalls, store.lastEnvironments)
+	}
+	store.mu.Unlock()
+
+	gh.depResult = github.DeploymentsFetchResult{Err: fmt.Errorf("all repos failed")}
+	f.fetchDeployments(context.Background())
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.mergeDepCalls != 1 {
+		t.Errorf("expected no MergeDeployments call on total failure, got %d calls", store.mergeDepCalls)
+	}
+}
+
+func TestDeploymentsInterval(t *testing.T) {
+	cfg := testConfig()
+	if got := DeploymentsInterval(cfg); got != config.DefaultDeploymentsInterval {
+		t.Errorf("DeploymentsInterval() unset = %v, want %v", got, config.DefaultDeploymentsInterval)
+	}
+	cfg.FetchIntervals.Deployments = time.Hour
+	if got := DeploymentsInterval(cfg); got != time.Hour {
+		t.Errorf("DeploymentsInterval() = %v, want 1h", got)
+	}
+}
+
 func TestRunIssuesFetcher_ContextCancellation(t *testing.T) {
 	gh := &mockGitHubFetcher{}
 	store := &mockStore{}
@@ -441,6 +783,18 @@ func TestStart_LaunchesAllFetchers(t *testing.T) {
 	if gh.fetchChecksCalls < 1 {
 		t.Errorf("expected at least 1 FetchBranchChecks call, got %d", gh.fetchChecksCalls)
 	}
+	if gh.fetchCmpCalls < 1 {
+		t.Errorf("expected at least 1 FetchBranchComparisons call, got %d", gh.fetchCmpCalls)
+	}
+	if gh.fetchRelCalls < 1 {
+		t.Errorf("expected at least 1 FetchReleaseInfo call, got %d", gh.fetchRelCalls)
+	}
+	if gh.fetchPlanCalls < 1 {
+		t.Errorf("expected at least 1 FetchPlanning call, got %d", gh.fetchPlanCalls)
+	}
+	if gh.fetchDepCalls < 1 {
+		t.Errorf("expected at least 1 FetchDeployments call, got %d", gh.fetchDepCalls)
+	}
 }
 
 func TestFetchIssues_UsesConfigOrgAndRepos(t *testing.T) {
@@ -478,3 +832,61 @@ func TestFetchIssues_UsesConfigOrgAndRepos(t *testing.T) {
 		t.Errorf("expected first repo 'repo-a', got %q", gh.lastRepos[0].Name)
 	}
 }
+
+func TestUpdateConfig_RefetchesWithNewRepos(t *testing.T) {
+	cfg := testConfig()
+	cfg.FetchIntervals.Issues = time.Hour // only the reload should trigger a second fetch
+
+	gh := &mockGitHubFetcher{}
+	store := &mockStore{}
+	f := New(cfg, gh, store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.runIssuesFetcher(ctx)
+	time.Sleep(50 * time.Millisecond)
+
+	newCfg := testConfig()
+	newCfg.FetchIntervals.Issues = time.Hour
+	newCfg.GitHub.Repositories = append(newCfg.GitHub.Repositories, config.RepositoryConfig{Name: "newrepo", Branches: []string{"main"}})
+	f.UpdateConfig(newCfg)
+	time.Sleep(50 * time.Millisecond)
+
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+
+	if gh.fetchIssuesCalls != 2 {
+		t.Errorf("expected 2 FetchIssues calls (initial + reload), got %d", gh.fetchIssuesCalls)
+	}
+	if len(gh.lastRepos) != 2 || gh.lastRepos[1].Name != "newrepo" {
+		t.Errorf("expected reload fetch to include newrepo, got %v", gh.lastRepos)
+	}
+	if f.config() != newCfg {
+		t.Error("UpdateConfig() did not replace config")
+	}
+}
+
+func TestUpdateConfig_AppliesNewInterval(t *testing.T) {
+	cfg := testConfig()
+	cfg.FetchIntervals.Actions = time.Hour
+
+	gh := &mockGitHubFetcher{}
+	store := &mockStore{}
+	f := New(cfg, gh, store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.runBranchChecksFetcher(ctx)
+	time.Sleep(20 * time.Millisecond)
+
+	f.UpdateConfig(testConfig()) // 100ms interval
+	time.Sleep(350 * time.Millisecond)
+
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+
+	// initial + reload + at least two ticks at the new interval
+	if gh.fetchChecksCalls < 4 {
+		t.Errorf("expected at least 4 FetchBranchChecks calls after interval change, got %d", gh.fetchChecksCalls)
+	}
+}
@@ -0,0 +1,19 @@
+package github
+
+import (
+	"context"
+)
+
+// RepoAccess reports whether org/repo is private and whether the client's
+// token can read it. A repository the token cannot see at all is reported
+// as unreadable without an error, since GitHub answers 404 either way.
+func (c *Client) RepoAccess(ctx context.Context, org, repo string) (private, readable bool, err error) {
+	r, _, err := c.gh.Repositories.Get(ctx, org, repo)
+	if err != nil {
+		if isNotFound(err) {
+			return true, false, nil
+		}
+		return false, false, err
+	}
+	return r.GetPrivate(), true, nil
+}
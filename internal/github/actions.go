@@ -3,13 +3,16 @@ package github
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	gh "github.com/google/go-github/v83/github"
 	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
 )
 
-func (c *Client) FetchBranchChecks(ctx context.Context, org string, repos []config.RepositoryConfig) ChecksFetchResult {
+// FetchBranchChecks fetches the checks of each branch head. Failed check
+// runs get their output, annotations and log tail as bounded by failures.
+func (c *Client) FetchBranchChecks(ctx context.Context, org string, repos []config.RepositoryConfig, failures config.FailuresConfig) ChecksFetchResult {
 	var result ChecksFetchResult
 	successCount := 0
 
@@ -18,19 +21,22 @@ func (c *Client) FetchBranchChecks(ctx context.Context, org string, repos []conf
 			break
 		}
 
+		repoCtx, span := tracing.StartRepoSpan(ctx, "checks", org, repo.Name)
 		repoFailed := true
+		var lastErr error
 		for _, branch := range repo.Branches {
-			if ctx.Err() != nil {
+			if repoCtx.Err() != nil {
 				break
 			}
 
 			// Get the latest commit for this branch
-			commits, resp, err := c.gh.Repositories.ListCommits(ctx, org, repo.Name, &gh.CommitsListOptions{
+			commits, resp, err := c.gh.Repositories.ListCommits(repoCtx, org, repo.Name, &gh.CommitsListOptions{
 				SHA:         branch,
 				ListOptions: gh.ListOptions{PerPage: 1},
 			})
 			if err != nil {
-				log.Printf("Error fetching commits for %s/%s (branch: %s): %v", org, repo.Name, branch, err)
+				slog.Error("fetching latest commit failed", "category", "checks", "org", org, "repo", repo.Name, "branch", branch, "error", err)
+				lastErr = err
 				continue
 			}
 			if resp != nil {
@@ -51,13 +57,13 @@ func (c *Client) FetchBranchChecks(ctx context.Context, org string, repos []conf
 			}
 
 			for {
-				if ctx.Err() != nil {
+				if repoCtx.Err() != nil {
 					break
 				}
 
-				checkRuns, resp, err := c.gh.Checks.ListCheckRunsForRef(ctx, org, repo.Name, latestCommit.GetSHA(), opts)
+				checkRuns, resp, err := c.gh.Checks.ListCheckRunsForRef(repoCtx, org, repo.Name, latestCommit.GetSHA(), opts)
 				if err != nil {
-					log.Printf("Error fetching check runs for %s/%s (branch: %s, SHA: %s): %v", org, repo.Name, branch, latestCommit.GetSHA(), err)
+					slog.Error("fetching check runs failed", "category", "checks", "org", org, "repo", repo.Name, "branch", branch, "sha", latestCommit.GetSHA(), "error", err)
 					break
 				}
 				if resp != nil {
@@ -70,19 +76,46 @@ func (c *Client) FetchBranchChecks(ctx context.Context, org string, repos []conf
 				opts.Page = resp.NextPage
 			}
 
+			// Without workflow runs the checks are still shown, just
+			// grouped by app rather than by workflow.
+			runs, rate, err := c.workflowRunsForSHA(repoCtx, org, repo.Name, latestCommit.GetSHA())
+			if err != nil {
+				slog.Warn("fetching workflow runs failed", "category", "checks", "org", org, "repo", repo.Name, "branch", branch, "sha", latestCommit.GetSHA(), "error", err)
+			} else if rate.Limit > 0 {
+				result.Rate = rate
+			}
+
 			var checks []Check
+			var skipped []string
 			for _, check := range allCheckRuns {
 				if check.GetConclusion() == "skipped" {
+					skipped = append(skipped, check.GetName())
 					continue
 				}
+				converted := newCheck(check, runs)
+				if failed(check) {
+					details, rate := c.cachedFailureDetails(repoCtx, org, repo.Name, check, failures)
+					converted.Failure = details
+					if rate.Limit > 0 {
+						result.Rate = rate
+					}
+				}
+				checks = append(checks, converted)
+			}
 
-				checks = append(checks, Check{
-					Name:       check.GetName(),
-					Status:     check.GetStatus(),
-					Conclusion: check.GetConclusion(),
-					URL:        check.GetHTMLURL(),
-				})
+			statuses, rate, err := c.commitStatuses(repoCtx, org, repo.Name, latestCommit.GetSHA())
+			if err != nil {
+				slog.Warn("fetching commit statuses failed", "category", "checks", "org", org, "repo", repo.Name, "branch", branch, "sha", latestCommit.GetSHA(), "error", err)
+			} else if rate.Limit > 0 {
+				result.Rate = rate
+			}
+			checks = append(checks, statuses...)
+
+			required, rate := c.cachedRequiredContexts(repoCtx, org, repo.Name, branch)
+			if rate.Limit > 0 {
+				result.Rate = rate
 			}
+			missing := markRequired(checks, required, skipped)
 
 			branchCheck := BranchCheck{
 				Repository: repo.Name,
@@ -91,12 +124,20 @@ func (c *Client) FetchBranchChecks(ctx context.Context, org string, repos []conf
 				CommitURL:  latestCommit.GetHTMLURL(),
 				UpdatedAt:  latestCommit.GetCommit().GetCommitter().GetDate().Time,
 				Checks:     checks,
+
+				RequiredChecks:  required,
+				MissingRequired: missing,
 			}
 
 			result.BranchChecks = append(result.BranchChecks, branchCheck)
 			successCount++
 			repoFailed = false
 		}
+		c.fetchScheduled(repoCtx, org, repo.Name, repo.Scheduled, &result)
+		if !repoFailed {
+			lastErr = nil
+		}
+		tracing.EndSpan(span, lastErr)
 
 		if repoFailed {
 			result.FailedRepos = append(result.FailedRepos, repo.Name)
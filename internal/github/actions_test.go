@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestFetchBranchChecks_WorkflowRunsAndStatuses(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/commits", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"sha": "abc123"}]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_count": 3, "check_runs": [
+			{"name": "build (ubuntu)", "status": "completed", "conclusion": "success", "check_suite": {"id": 10},
+			 "started_at": "2026-05-01T10:00:00Z", "completed_at": "2026-05-01T10:12:30Z"},
+			{"name": "build (macos)", "status": "completed", "conclusion": "failure", "check_suite": {"id": 10}},
+			{"name": "jenkins", "status": "completed", "conclusion": "success", "check_suite": {"id": 99}, "app": {"name": "Jenkins"}}
+		]}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("head_sha") != "abc123" {
+			t.Errorf("head_sha = %q, want abc123", r.URL.Query().Get("head_sha"))
+		}
+		w.Write([]byte(`{"total_count": 1, "workflow_runs": [
+			{"id": 555, "name": "CI", "run_attempt": 2, "event": "push", "check_suite_id": 10}
+		]}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/commits/abc123/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": "failure", "statuses": [
+			{"context": "continuous-integration/jenkins/branch", "state": "pending", "target_url": "https://jenkins.example.com/1"},
+			{"context": "docs/readthedocs.org:eccodes", "state": "error", "target_url": "https://readthedocs.org/b/2"}
+		]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	result := c.FetchBranchChecks(context.Background(), "ecmwf", []config.RepositoryConfig{{Name: "eccodes", Branches: []string{"develop"}}}, config.FailuresConfig{})
+	if result.Err != nil || len(result.BranchChecks) != 1 {
+		t.Fatalf("FetchBranchChecks() = %+v", result)
+	}
+
+	checks := result.BranchChecks[0].Checks
+	if len(checks) != 5 {
+		t.Fatalf("got %d checks, want 5", len(checks))
+	}
+	want := Check{Name: "build (ubuntu)", Status: "completed", Conclusion: "success", Source: SourceCheckRun, Workflow: "CI", RunID: 555, RunAttempt: 2, Event: "push",
+		StartedAt:   time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC),
+		CompletedAt: time.Date(2026, 5, 1, 10, 12, 30, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(checks[0], want) {
+		t.Errorf("checks[0] = %+v, want %+v", checks[0], want)
+	}
+	if d := checks[0].Duration(); d != 12*time.Minute+30*time.Second {
+		t.Errorf("Duration() = %v, want 12m30s", d)
+	}
+	if d := checks[1].Duration(); d != 0 {
+		t.Errorf("Duration() without timing = %v, want 0", d)
+	}
+	if checks[2].Workflow != "Jenkins" || checks[2].RunID != 0 {
+		t.Errorf("external check = %+v, want grouped under app name", checks[2])
+	}
+
+	jenkins, docs := checks[3], checks[4]
+	if jenkins.Source != SourceStatus || ClassifyCheck(jenkins.Status, jenkins.Conclusion) != "running" || jenkins.URL != "https://jenkins.example.com/1" {
+		t.Errorf("pending status = %+v, want a running status check", jenkins)
+	}
+	if docs.Name != "docs/readthedocs.org:eccodes" || ClassifyCheck(docs.Status, docs.Conclusion) != "failure" {
+		t.Errorf("error status = %+v, want a failed status check", docs)
+	}
+}
@@ -1,20 +1,192 @@
 package github
 
-// ClassifyCheck returns "running", "success", or "failure" for a check run.
-// Skipped checks should be pre-filtered by the caller.
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ClassifyCheck returns "running", "success", or "failure" for a check run
+// or a commit status. Skipped checks should be pre-filtered by the caller.
 //
 // Classification:
 //   - in_progress/queued/waiting/pending → "running"
 //   - conclusion == "success"            → "success"
 //   - everything else (failure, timed_out, cancelled, action_required,
 //     neutral, stale, unknown)           → "failure"
+//
+// Commit statuses may be passed either as mapped by the client (status
+// "completed", state as conclusion) or with the raw state as status:
+// pending → "running", success → "success", failure/error → "failure".
 func ClassifyCheck(status, conclusion string) string {
 	switch status {
 	case "in_progress", "queued", "waiting", "pending":
 		return "running"
+	case "success", "failure", "error":
+		if conclusion == "" {
+			conclusion = status
+		}
 	}
 	if conclusion == "success" {
 		return "success"
 	}
 	return "failure"
 }
+
+// Duration returns how long a finished check ran, or 0 if unknown.
+func (c Check) Duration() time.Duration {
+	if c.StartedAt.IsZero() || c.CompletedAt.Before(c.StartedAt) {
+		return 0
+	}
+	return c.CompletedAt.Sub(c.StartedAt)
+}
+
+// CheckCounts tallies checks by ClassifyCheck result.
+type CheckCounts struct {
+	Success int
+	Failure int
+	Running int
+}
+
+func (c *CheckCounts) add(check Check) {
+	switch ClassifyCheck(check.Status, check.Conclusion) {
+	case "running":
+		c.Running++
+	case "success":
+		c.Success++
+	default:
+		c.Failure++
+	}
+}
+
+// Total returns the number of checks counted.
+func (c CheckCounts) Total() int { return c.Success + c.Failure + c.Running }
+
+// Status summarises the counts the way branch statuses do: "running" if
+// anything is still running, else "failure" if anything failed, else
+// "success".
+func (c CheckCounts) Status() string {
+	switch {
+	case c.Running > 0:
+		return "running"
+	case c.Failure > 0:
+		return "failure"
+	default:
+		return "success"
+	}
+}
+
+// CheckGroup holds the checks of one workflow run (or of one external app).
+type CheckGroup struct {
+	Workflow   string
+	RunID      int64
+	RunAttempt int
+	Event      string
+	Jobs       []JobGroup
+	CheckCounts
+}
+
+// JobGroup holds one job. The variants of a matrix job, named like
+// "build (ubuntu, gcc-12)", are collapsed into a single group "build".
+type JobGroup struct {
+	Name   string
+	Checks []Check
+	CheckCounts
+}
+
+// Matrix reports whether the job has more than one variant.
+func (j JobGroup) Matrix() bool { return len(j.Checks) > 1 }
+
+// URL links to the first unsuccessful variant, or to the first variant if
+// all passed.
+func (j JobGroup) URL() string {
+	for _, c := range j.Checks {
+		if ClassifyCheck(c.Status, c.Conclusion) != "success" {
+			return c.URL
+		}
+	}
+	if len(j.Checks) == 0 {
+		return ""
+	}
+	return j.Checks[0].URL
+}
+
+// Flaky reports whether any failing variant is a known flaky check.
+func (j JobGroup) Flaky() bool {
+	for _, c := range j.Checks {
+		if c.Flaky {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupChecks groups checks by workflow run and collapses matrix jobs.
+// Groups are ordered by workflow name, jobs and variants by name.
+func GroupChecks(checks []Check) []CheckGroup {
+	type groupKey struct {
+		workflow string
+		runID    int64
+	}
+	var groups []CheckGroup
+	groupIndex := make(map[groupKey]int)
+	jobIndex := make(map[groupKey]map[string]int)
+
+	for _, check := range checks {
+		key := groupKey{check.Workflow, check.RunID}
+		gi, ok := groupIndex[key]
+		if !ok {
+			gi = len(groups)
+			groupIndex[key] = gi
+			jobIndex[key] = make(map[string]int)
+			groups = append(groups, CheckGroup{
+				Workflow:   check.Workflow,
+				RunID:      check.RunID,
+				RunAttempt: check.RunAttempt,
+				Event:      check.Event,
+			})
+		}
+		g := &groups[gi]
+		g.add(check)
+
+		name := jobName(check.Name)
+		ji, ok := jobIndex[key][name]
+		if !ok {
+			ji = len(g.Jobs)
+			jobIndex[key][name] = ji
+			g.Jobs = append(g.Jobs, JobGroup{Name: name})
+		}
+		g.Jobs[ji].Checks = append(g.Jobs[ji].Checks, check)
+		g.Jobs[ji].add(check)
+	}
+
+	for i := range groups {
+		jobs := groups[i].Jobs
+		sort.SliceStable(jobs, func(a, b int) bool { return jobs[a].Name < jobs[b].Name })
+		for j := range jobs {
+			variants := jobs[j].Checks
+			sort.SliceStable(variants, func(a, b int) bool { return variants[a].Name < variants[b].Name })
+		}
+	}
+	sort.SliceStable(groups, func(a, b int) bool {
+		if groups[a].Workflow != groups[b].Workflow {
+			return groups[a].Workflow < groups[b].Workflow
+		}
+		return groups[a].RunID < groups[b].RunID
+	})
+	return groups
+}
+
+// jobName strips a trailing matrix suffix: "build (ubuntu, gcc-12)" → "build".
+func jobName(name string) string {
+	if !strings.HasSuffix(name, ")") {
+		return name
+	}
+	if i := strings.LastIndex(name, " ("); i > 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// CheckGroups returns the PR's checks grouped by workflow.
+func (pr PullRequest) CheckGroups() []CheckGroup { return GroupChecks(pr.Checks) }
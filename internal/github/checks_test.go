@@ -1,6 +1,9 @@
 package github
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestClassifyCheck(t *testing.T) {
 	tests := []struct {
@@ -27,6 +30,12 @@ func TestClassifyCheck(t *testing.T) {
 		{"completed", "neutral", "failure"},
 		{"completed", "stale", "failure"},
 
+		// Commit status states, raw and as mapped by the client
+		{"success", "", "success"},
+		{"failure", "", "failure"},
+		{"error", "", "failure"},
+		{"completed", "error", "failure"},
+
 		// Unknown conclusion
 		{"completed", "something_unknown", "failure"},
 		{"completed", "", "failure"},
@@ -41,3 +50,57 @@ func TestClassifyCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupChecks(t *testing.T) {
+	checks := []Check{
+		{Name: "build (ubuntu, gcc-12)", Status: "completed", Conclusion: "success", Workflow: "CI", RunID: 1},
+		{Name: "docs", Status: "completed", Conclusion: "success", Workflow: "Docs", RunID: 2},
+		{Name: "build (macos, clang)", Status: "completed", Conclusion: "failure", Workflow: "CI", RunID: 1},
+		{Name: "lint", Status: "in_progress", Workflow: "CI", RunID: 1},
+		{Name: "build (ubuntu, gcc-11)", Status: "completed", Conclusion: "success", Workflow: "CI", RunID: 1},
+		{Name: "continuous-integration/jenkins", Status: "completed", Conclusion: "success", Workflow: "Jenkins"},
+	}
+
+	groups := GroupChecks(checks)
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Workflow)
+	}
+	if got, want := strings.Join(names, ","), "CI,Docs,Jenkins"; got != want {
+		t.Fatalf("groups = %s, want %s", got, want)
+	}
+
+	ci := groups[0]
+	if ci.Status() != "running" || ci.Total() != 4 || ci.Failure != 1 {
+		t.Errorf("CI counts = %+v, status %s", ci.CheckCounts, ci.Status())
+	}
+	if len(ci.Jobs) != 2 || ci.Jobs[0].Name != "build" || ci.Jobs[1].Name != "lint" {
+		t.Fatalf("CI jobs = %+v", ci.Jobs)
+	}
+	build := ci.Jobs[0]
+	if !build.Matrix() || build.Total() != 3 || build.Status() != "failure" {
+		t.Errorf("build job = %+v, status %s", build.CheckCounts, build.Status())
+	}
+	if build.Checks[0].Name != "build (macos, clang)" {
+		t.Errorf("variants not sorted: first is %q", build.Checks[0].Name)
+	}
+	if ci.Jobs[1].Matrix() {
+		t.Error("lint should not be a matrix job")
+	}
+}
+
+func TestJobName(t *testing.T) {
+	tests := map[string]string{
+		"build (ubuntu, gcc-12)":         "build",
+		"ci / test (3.12)":               "ci / test",
+		"build":                          "build",
+		"(weird)":                        "(weird)",
+		"check (optional) step":          "check (optional) step",
+		"continuous-integration/jenkins": "continuous-integration/jenkins",
+	}
+	for name, want := range tests {
+		if got := jobName(name); got != want {
+			t.Errorf("jobName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -3,22 +3,35 @@ package github
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	gh "github.com/google/go-github/v83/github"
 	"golang.org/x/oauth2"
+
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
 )
 
 type Client struct {
-	gh *gh.Client
+	gh   *gh.Client
+	logs *http.Client // for job log downloads, which must not carry the token
+
+	mu       sync.Mutex
+	required map[string]requiredEntry // "org/repo@branch" → required contexts
+	failures map[failureKey]failureEntry
 }
 
-func NewClient() (*Client, error) {
-	token := os.Getenv("GITHUB_TOKEN")
+// NewClient creates an authenticated client. An empty token falls back to
+// the GITHUB_TOKEN environment variable.
+func NewClient(token string) (*Client, error) {
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
 	if token == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN environment variable not set")
+		return nil, fmt.Errorf("no GitHub token: set github.token, ECMWF_DASH_GITHUB_TOKEN or GITHUB_TOKEN")
 	}
 
 	// context.Background() is appropriate here: StaticTokenSource doesn't make
@@ -30,9 +43,11 @@ func NewClient() (*Client, error) {
 	)
 	tc := oauth2.NewClient(ctx, ts)
 	tc.Timeout = 30 * time.Second
+	tc.Transport = tracing.Transport(tc.Transport)
 
 	return &Client{
-		gh: gh.NewClient(tc),
+		gh:   gh.NewClient(tc),
+		logs: &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(http.DefaultTransport)},
 	}, nil
 }
 
@@ -43,13 +58,23 @@ type RateInfo struct {
 	Reset     time.Time
 }
 
+// LogValue renders RateInfo as a group of remaining/limit/reset attributes.
+func (r RateInfo) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("remaining", r.Remaining),
+		slog.Int("limit", r.Limit),
+		slog.Time("reset", r.Reset),
+	)
+}
+
 // LogRate logs the current rate limit status. Call with the RateInfo returned
 // by fetch functions instead of making a separate API call.
 func (c *Client) LogRate(r RateInfo) {
-	log.Printf("GitHub API rate: %d/%d remaining, resets at %s", r.Remaining, r.Limit, r.Reset.Format(time.RFC3339))
 	if r.Remaining < 100 {
-		log.Printf("WARNING: GitHub API rate limit nearly exhausted (%d remaining)", r.Remaining)
+		slog.Warn("github rate limit nearly exhausted", "rate", r)
+		return
 	}
+	slog.Debug("github rate limit", "rate", r)
 }
 
 // rateFromResponse extracts RateInfo from a GitHub API response.
@@ -0,0 +1,131 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	gh "github.com/google/go-github/v83/github"
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
+)
+
+// BranchComparison is how far two branches of a repository have diverged.
+type BranchComparison struct {
+	Repository string
+	Base       string // e.g. master
+	Head       string // e.g. develop
+	URL        string // the comparison on GitHub
+
+	AheadBy  int // commits on Head missing from Base
+	BehindBy int // commits on Base missing from Head
+
+	// MergeBaseSHA is the newest Base commit already in Head; its date
+	// is when Base was last merged back into Head, as far as can be told
+	// without walking Head's history.
+	MergeBaseSHA string
+	MergeBaseAt  time.Time
+
+	// FetchFailed is set when the comparison could not be fetched; the
+	// store then keeps any earlier result.
+	FetchFailed bool
+}
+
+// Divergence is the total number of commits the branches differ by.
+func (c BranchComparison) Divergence() int { return c.AheadBy + c.BehindBy }
+
+// FetchBranchComparisons compares the configured branch pairs of each
+// repository. Repositories without pairs are skipped and count as
+// succeeded; a repository fails when none of its pairs could be compared.
+func (c *Client) FetchBranchComparisons(ctx context.Context, org string, repos []config.RepositoryConfig) ComparisonsFetchResult {
+	var result ComparisonsFetchResult
+	successCount := 0
+
+	for _, repo := range repos {
+		if ctx.Err() != nil {
+			break
+		}
+		if len(repo.Compare) == 0 {
+			result.SucceededRepos = append(result.SucceededRepos, repo.Name)
+			continue
+		}
+
+		repoCtx, span := tracing.StartRepoSpan(ctx, "branches", org, repo.Name)
+		repoFailed := true
+		var lastErr error
+		for _, pair := range repo.Compare {
+			if repoCtx.Err() != nil {
+				break
+			}
+			cmp, rate, err := c.compareBranches(repoCtx, org, repo.Name, pair)
+			if rate.Limit > 0 {
+				result.Rate = rate
+			}
+			if err != nil {
+				slog.Error("comparing branches failed", "category", "branches", "org", org, "repo", repo.Name, "base", pair.Base, "head", pair.Head, "error", err)
+				lastErr = err
+			} else {
+				repoFailed = false
+			}
+			result.Comparisons = append(result.Comparisons, cmp)
+		}
+		if !repoFailed {
+			lastErr = nil
+			successCount++
+		}
+		tracing.EndSpan(span, lastErr)
+
+		if repoFailed {
+			result.FailedRepos = append(result.FailedRepos, repo.Name)
+		} else {
+			result.SucceededRepos = append(result.SucceededRepos, repo.Name)
+		}
+	}
+
+	attempted := len(result.SucceededRepos) + len(result.FailedRepos)
+	for i := attempted; i < len(repos); i++ {
+		result.FailedRepos = append(result.FailedRepos, repos[i].Name)
+	}
+
+	if successCount == 0 && len(result.FailedRepos) > 0 {
+		if ctx.Err() != nil {
+			result.Err = ctx.Err()
+		} else {
+			result.Err = fmt.Errorf("all branch comparisons failed")
+		}
+	}
+
+	return result
+}
+
+// compareBranches fetches the ahead/behind counts of one branch pair. The
+// commit list is not needed, so only one commit is requested.
+func (c *Client) compareBranches(ctx context.Context, org, repo string, pair config.BranchPair) (BranchComparison, RateInfo, error) {
+	cmp := BranchComparison{
+		Repository: repo,
+		Base:       pair.Base,
+		Head:       pair.Head,
+		URL:        fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s", org, repo, pair.Base, pair.Head),
+	}
+	var rate RateInfo
+	res, resp, err := c.gh.Repositories.CompareCommits(ctx, org, repo, pair.Base, pair.Head, &gh.ListOptions{PerPage: 1})
+	if resp != nil {
+		rate = rateFromResponse(resp)
+	}
+	if err != nil {
+		cmp.FetchFailed = true
+		return cmp, rate, err
+	}
+
+	cmp.AheadBy = res.GetAheadBy()
+	cmp.BehindBy = res.GetBehindBy()
+	if res.GetHTMLURL() != "" {
+		cmp.URL = res.GetHTMLURL()
+	}
+	if mb := res.GetMergeBaseCommit(); mb != nil {
+		cmp.MergeBaseSHA = mb.GetSHA()
+		cmp.MergeBaseAt = mb.GetCommit().GetCommitter().GetDate().Time
+	}
+	return cmp, rate, nil
+}
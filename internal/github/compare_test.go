@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestFetchBranchComparisons(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eckit/compare/master...develop", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "1" {
+			t.Errorf("per_page = %q, want 1: the commit list is not used", r.URL.Query().Get("per_page"))
+		}
+		w.Write([]byte(`{"status": "diverged", "ahead_by": 42, "behind_by": 3,
+			"html_url": "https://github.com/ecmwf/eckit/compare/master...develop",
+			"merge_base_commit": {"sha": "base123", "commit": {"committer": {"date": "2026-04-01T10:00:00Z"}}}}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eckit/compare/master...gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/ecmwf/fdb/compare/master...develop", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	repos := []config.RepositoryConfig{
+		{Name: "eckit", Compare: []config.BranchPair{{Base: "master", Head: "develop"}, {Base: "master", Head: "gone"}}},
+		{Name: "metkit"},
+		{Name: "fdb", Compare: []config.BranchPair{{Base: "master", Head: "develop"}}},
+	}
+	result := c.FetchBranchComparisons(context.Background(), "ecmwf", repos)
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.SucceededRepos) != 2 || len(result.FailedRepos) != 1 || result.FailedRepos[0] != "fdb" {
+		t.Errorf("succeeded %v, failed %v; want eckit and metkit (no pairs) to succeed, fdb to fail", result.SucceededRepos, result.FailedRepos)
+	}
+	if len(result.Comparisons) != 3 {
+		t.Fatalf("got %d comparisons, want 3", len(result.Comparisons))
+	}
+
+	got := result.Comparisons[0]
+	if got.AheadBy != 42 || got.BehindBy != 3 || got.Divergence() != 45 || got.MergeBaseSHA != "base123" {
+		t.Errorf("comparison = %+v", got)
+	}
+	if want := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC); !got.MergeBaseAt.Equal(want) {
+		t.Errorf("MergeBaseAt = %v, want %v", got.MergeBaseAt, want)
+	}
+	if gone := result.Comparisons[1]; !gone.FetchFailed || gone.URL != "https://github.com/ecmwf/eckit/compare/master...gone" {
+		t.Errorf("missing branch = %+v, want a failed comparison", gone)
+	}
+}
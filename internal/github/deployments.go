@@ -0,0 +1,167 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	gh "github.com/google/go-github/v83/github"
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
+)
+
+// maxDeploymentHistory is how many deployments are kept per environment,
+// each costing a request for its latest status.
+const maxDeploymentHistory = 5
+
+// Environment is a deployment environment of a repository, such as
+// "docs" or "pypi", with its latest deployments.
+type Environment struct {
+	Repository  string
+	Name        string
+	Deployments []Deployment // newest first, up to maxDeploymentHistory
+}
+
+// Latest returns the newest deployment, or nil when there is none.
+func (e Environment) Latest() *Deployment {
+	if len(e.Deployments) == 0 {
+		return nil
+	}
+	return &e.Deployments[0]
+}
+
+// Deployment is one deployment to an environment and its latest status.
+type Deployment struct {
+	ID        int64
+	Ref       string // branch or tag deployed
+	SHA       string
+	Creator   string
+	CreatedAt time.Time
+
+	// State is that of the latest status: success, failure, error,
+	// inactive, in_progress, queued or pending; "" when none was reported.
+	State       string
+	Description string
+	UpdatedAt   time.Time // of the latest status, else CreatedAt
+	URL         string    // the deployed site or the deployment's logs, if reported
+}
+
+// StatusClass maps State to the status classes used for checks.
+func (d Deployment) StatusClass() string {
+	switch d.State {
+	case "success":
+		return "status-success"
+	case "failure", "error":
+		return "status-failure"
+	case "in_progress", "queued", "pending":
+		return "status-running"
+	default:
+		return "status-neutral"
+	}
+}
+
+// FetchDeployments fetches the latest deployments of each repository,
+// grouped by environment, with their latest statuses. Only the newest 100
+// deployments of a repository are looked at, so an environment not
+// deployed to since is missing.
+func (c *Client) FetchDeployments(ctx context.Context, org string, repos []config.RepositoryConfig) DeploymentsFetchResult {
+	var result DeploymentsFetchResult
+	successCount := 0
+
+	for _, repo := range repos {
+		if ctx.Err() != nil {
+			break
+		}
+
+		repoCtx, span := tracing.StartRepoSpan(ctx, "deployments", org, repo.Name)
+		envs, rate, err := c.environments(repoCtx, org, repo.Name)
+		if rate.Limit > 0 {
+			result.Rate = rate
+		}
+		tracing.EndSpan(span, err)
+
+		if err != nil {
+			slog.Error("fetching deployments failed", "category", "deployments", "org", org, "repo", repo.Name, "error", err)
+			result.FailedRepos = append(result.FailedRepos, repo.Name)
+			continue
+		}
+		result.Environments = append(result.Environments, envs...)
+		result.SucceededRepos = append(result.SucceededRepos, repo.Name)
+		successCount++
+	}
+
+	attempted := len(result.SucceededRepos) + len(result.FailedRepos)
+	for i := attempted; i < len(repos); i++ {
+		result.FailedRepos = append(result.FailedRepos, repos[i].Name)
+	}
+
+	if successCount == 0 && len(repos) > 0 {
+		if ctx.Err() != nil {
+			result.Err = ctx.Err()
+		} else {
+			result.Err = fmt.Errorf("all %d repos failed", len(repos))
+		}
+	}
+
+	return result
+}
+
+// environments lists repo's deployments, newest first, and reads the
+// latest status of the newest maxDeploymentHistory per environment.
+// Environments are in the order they were last deployed to.
+func (c *Client) environments(ctx context.Context, org, repo string) ([]Environment, RateInfo, error) {
+	var rate RateInfo
+	deployments, resp, err := c.gh.Repositories.ListDeployments(ctx, org, repo, &gh.DeploymentsListOptions{
+		ListOptions: gh.ListOptions{PerPage: 100},
+	})
+	if resp != nil {
+		rate = rateFromResponse(resp)
+	}
+	if err != nil {
+		return nil, rate, err
+	}
+
+	var envs []Environment
+	index := make(map[string]int)
+	for _, d := range deployments {
+		name := d.GetEnvironment()
+		i, ok := index[name]
+		if !ok {
+			i = len(envs)
+			index[name] = i
+			envs = append(envs, Environment{Repository: repo, Name: name})
+		}
+		if len(envs[i].Deployments) == maxDeploymentHistory {
+			continue
+		}
+
+		dep := Deployment{
+			ID:        d.GetID(),
+			Ref:       d.GetRef(),
+			SHA:       d.GetSHA(),
+			Creator:   d.GetCreator().GetLogin(),
+			CreatedAt: d.GetCreatedAt().Time,
+			UpdatedAt: d.GetCreatedAt().Time,
+		}
+		statuses, resp, err := c.gh.Repositories.ListDeploymentStatuses(ctx, org, repo, dep.ID, &gh.ListOptions{PerPage: 1})
+		if resp != nil {
+			rate = rateFromResponse(resp)
+		}
+		if err != nil {
+			return nil, rate, fmt.Errorf("statuses of deployment %d: %w", dep.ID, err)
+		}
+		if len(statuses) > 0 {
+			s := statuses[0]
+			dep.State = s.GetState()
+			dep.Description = s.GetDescription()
+			dep.UpdatedAt = s.GetCreatedAt().Time
+			dep.URL = s.GetEnvironmentURL()
+			if dep.URL == "" {
+				dep.URL = s.GetLogURL()
+			}
+		}
+		envs[i].Deployments = append(envs[i].Deployments, dep)
+	}
+	return envs, rate, nil
+}
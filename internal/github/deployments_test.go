@@ -0,0 +1,76 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestFetchDeployments(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/deployments", func(w http.ResponseWriter, r *http.Request) {
+		// Newest first: seven docs deployments around one to pypi.
+		var deployments []string
+		for id := 10; id >= 4; id-- {
+			deployments = append(deployments, fmt.Sprintf(`{"id": %d, "ref": "develop", "sha": "abc%d", "environment": "docs", "creator": {"login": "bot"}, "created_at": "2026-10-%02dT12:00:00Z"}`, id, id, id))
+			if id == 9 {
+				deployments = append(deployments, `{"id": 99, "ref": "2.40.0", "environment": "pypi", "created_at": "2026-10-09T13:00:00Z"}`)
+			}
+		}
+		w.Write([]byte("[" + strings.Join(deployments, ",") + "]"))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/deployments/{id}/statuses", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "1" {
+			t.Errorf("per_page = %q, want only the latest status", r.URL.Query().Get("per_page"))
+		}
+		switch r.PathValue("id") {
+		case "10":
+			w.Write([]byte(`[{"state": "in_progress", "created_at": "2026-10-10T12:05:00Z", "log_url": "https://ci/10"}]`))
+		case "99":
+			w.Write([]byte(`[{"state": "failure", "description": "twine upload failed", "created_at": "2026-10-09T13:10:00Z"}]`))
+		case "9":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`[{"state": "success", "environment_url": "https://docs.example/eccodes", "created_at": "2026-10-01T00:00:00Z"}]`))
+		}
+	})
+	mux.HandleFunc("/repos/ecmwf/fdb/deployments", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	result := c.FetchDeployments(context.Background(), "ecmwf", []config.RepositoryConfig{{Name: "eccodes"}, {Name: "fdb"}})
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.FailedRepos) != 1 || result.FailedRepos[0] != "fdb" {
+		t.Errorf("failed repos = %v, want fdb", result.FailedRepos)
+	}
+	if len(result.Environments) != 2 {
+		t.Fatalf("got %d environments, want docs and pypi", len(result.Environments))
+	}
+
+	docs, pypi := result.Environments[0], result.Environments[1]
+	if docs.Name != "docs" || len(docs.Deployments) != maxDeploymentHistory {
+		t.Fatalf("docs = %+v, want the latest %d deployments", docs, maxDeploymentHistory)
+	}
+	if d := docs.Deployments[0]; d.ID != 10 || d.State != "in_progress" || d.URL != "https://ci/10" || d.StatusClass() != "status-running" {
+		t.Errorf("latest docs deployment = %+v", d)
+	}
+	if d := docs.Deployments[1]; d.State != "" || !d.UpdatedAt.Equal(d.CreatedAt) || d.StatusClass() != "status-neutral" {
+		t.Errorf("deployment without status = %+v", d)
+	}
+	if d := docs.Deployments[2]; d.URL != "https://docs.example/eccodes" || d.Creator != "bot" {
+		t.Errorf("docs deployment = %+v", d)
+	}
+	if d := pypi.Deployments[0]; pypi.Name != "pypi" || d.Ref != "2.40.0" || d.Description != "twine upload failed" || d.StatusClass() != "status-failure" {
+		t.Errorf("pypi = %+v", pypi)
+	}
+}
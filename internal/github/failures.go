@@ -0,0 +1,263 @@
+package github
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	gh "github.com/google/go-github/v83/github"
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+// FailureDetails explains why a check run failed. It is shared between
+// copies of a Check and never modified after the fetch.
+type FailureDetails struct {
+	Title           string
+	Summary         string // markdown as posted by the check, truncated
+	Annotations     []Annotation
+	AnnotationCount int      // total reported by GitHub; may exceed len(Annotations)
+	Log             []string // last lines of the job log, oldest first
+}
+
+// Annotation is a message a check run attached to a file and line.
+type Annotation struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	Level     string // failure, warning or notice
+	Title     string
+	Message   string
+}
+
+// Location renders the annotation's position as path:line or path:start-end.
+func (a Annotation) Location() string {
+	switch {
+	case a.StartLine == 0:
+		return a.Path
+	case a.EndLine > a.StartLine:
+		return fmt.Sprintf("%s:%d-%d", a.Path, a.StartLine, a.EndLine)
+	default:
+		return fmt.Sprintf("%s:%d", a.Path, a.StartLine)
+	}
+}
+
+// Size bounds on what is kept per failing check run, so a noisy job cannot
+// bloat the store.
+const (
+	maxSummaryBytes   = 4 << 10
+	maxMessageBytes   = 1 << 10
+	maxLogLineBytes   = 500
+	maxLogBytes       = 32 << 20 // read at most this much of a job log
+	failureDetailsTTL = 24 * time.Hour
+	githubActionsSlug = "github-actions"
+)
+
+type failureKey struct {
+	runID    int64
+	settings config.FailuresConfig
+}
+
+type failureEntry struct {
+	details *FailureDetails
+	expires time.Time
+}
+
+// failed reports whether a check run's conclusion means its own output
+// explains a failure. Cancelled and action_required runs have none.
+func failed(run *gh.CheckRun) bool {
+	return run.GetStatus() == "completed" && (run.GetConclusion() == "failure" || run.GetConclusion() == "timed_out")
+}
+
+// cachedFailureDetails is failureDetails with a cache: a completed check
+// run never changes, and it stays the branch head's until the next push.
+// Details are only cached when every request succeeded.
+func (c *Client) cachedFailureDetails(ctx context.Context, org, repo string, run *gh.CheckRun, settings config.FailuresConfig) (*FailureDetails, RateInfo) {
+	key := failureKey{run.GetID(), settings}
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.failures[key]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.details, RateInfo{}
+	}
+
+	details, rate, complete := c.failureDetails(ctx, org, repo, run, settings)
+	if !complete {
+		return details, rate
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures == nil {
+		c.failures = make(map[failureKey]failureEntry)
+	}
+	for k, e := range c.failures {
+		if now.After(e.expires) {
+			delete(c.failures, k)
+		}
+	}
+	c.failures[key] = failureEntry{details: details, expires: now.Add(failureDetailsTTL)}
+	return details, rate
+}
+
+// failureDetails collects the output summary, annotations and optionally
+// the log tail of a failed check run. It returns nil if the run explains
+// nothing, and complete=false if a request failed.
+func (c *Client) failureDetails(ctx context.Context, org, repo string, run *gh.CheckRun, settings config.FailuresConfig) (details *FailureDetails, rate RateInfo, complete bool) {
+	out := run.GetOutput()
+	d := &FailureDetails{
+		Title:           truncate(out.GetTitle(), maxMessageBytes),
+		Summary:         truncate(out.GetSummary(), maxSummaryBytes),
+		AnnotationCount: out.GetAnnotationsCount(),
+	}
+	complete = true
+
+	if d.AnnotationCount > 0 {
+		limit := settings.Annotations
+		if limit == 0 {
+			limit = config.DefaultFailureAnnotations
+		}
+		annotations, resp, err := c.gh.Checks.ListCheckRunAnnotations(ctx, org, repo, run.GetID(), &gh.ListOptions{PerPage: limit})
+		if resp != nil {
+			rate = rateFromResponse(resp)
+		}
+		if err != nil {
+			slog.Warn("fetching check run annotations failed", "category", "checks", "org", org, "repo", repo, "check_run", run.GetID(), "error", err)
+			complete = false
+		}
+		for _, a := range annotations {
+			d.Annotations = append(d.Annotations, Annotation{
+				Path:      a.GetPath(),
+				StartLine: a.GetStartLine(),
+				EndLine:   a.GetEndLine(),
+				Level:     a.GetAnnotationLevel(),
+				Title:     truncate(a.GetTitle(), maxMessageBytes),
+				Message:   truncate(a.GetMessage(), maxMessageBytes),
+			})
+		}
+		// Failures first; GitHub lists annotations in the order posted.
+		sort.SliceStable(d.Annotations, func(i, j int) bool {
+			return d.Annotations[i].Level == "failure" && d.Annotations[j].Level != "failure"
+		})
+	}
+
+	// Check runs of Actions jobs share their ID with the job.
+	if settings.LogLines > 0 && run.GetApp().GetSlug() == githubActionsSlug {
+		lines, jobRate, err := c.jobLogTail(ctx, org, repo, run.GetID(), settings.LogLines)
+		if jobRate.Limit > 0 {
+			rate = jobRate
+		}
+		if err != nil {
+			slog.Warn("fetching job log failed", "category", "checks", "org", org, "repo", repo, "job", run.GetID(), "error", err)
+			complete = false
+		}
+		d.Log = lines
+	}
+
+	if d.Title == "" && d.Summary == "" && len(d.Annotations) == 0 && len(d.Log) == 0 {
+		return nil, rate, complete
+	}
+	return d, rate, complete
+}
+
+var (
+	logTimestamp = regexp.MustCompile(`^\d{4}-\d\d-\d\dT[\d:.]+Z `)
+	ansiEscape   = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+)
+
+// jobLogTail returns the last n lines of an Actions job log, without the
+// per-line timestamps and terminal colour codes.
+func (c *Client) jobLogTail(ctx context.Context, org, repo string, jobID int64, n int) ([]string, RateInfo, error) {
+	var rate RateInfo
+	logURL, resp, err := c.gh.Actions.GetWorkflowJobLogs(ctx, org, repo, jobID, 2)
+	if resp != nil {
+		rate = rateFromResponse(resp)
+	}
+	if err != nil {
+		return nil, rate, err
+	}
+
+	// The log lives on a pre-signed storage URL that must not receive the
+	// GitHub token.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL.String(), nil)
+	if err != nil {
+		return nil, rate, err
+	}
+	logResp, err := c.logHTTPClient().Do(req)
+	if err != nil {
+		return nil, rate, err
+	}
+	defer logResp.Body.Close()
+	if logResp.StatusCode != http.StatusOK {
+		return nil, rate, fmt.Errorf("downloading job log: %s", logResp.Status)
+	}
+
+	lines, err := tailLines(io.LimitReader(logResp.Body, maxLogBytes), n)
+	return lines, rate, err
+}
+
+func (c *Client) logHTTPClient() *http.Client {
+	if c.logs != nil {
+		return c.logs
+	}
+	return http.DefaultClient
+}
+
+// logReadBuffer is how much of a log line tailLines looks at; the rest of
+// longer lines, such as minified output, is skipped.
+const logReadBuffer = 64 << 10
+
+// tailLines keeps the last n non-empty lines of r, cleaned up and truncated.
+func tailLines(r io.Reader, n int) ([]string, error) {
+	ring := make([]string, n)
+	count := 0
+	reader := bufio.NewReaderSize(r, logReadBuffer)
+	var err error
+	for {
+		var chunk []byte
+		var more bool
+		chunk, more, err = reader.ReadLine()
+		if err != nil {
+			break
+		}
+		line := string(chunk)
+		for more && err == nil {
+			_, more, err = reader.ReadLine()
+		}
+		line = logTimestamp.ReplaceAllString(line, "")
+		line = strings.TrimRight(ansiEscape.ReplaceAllString(line, ""), " \r")
+		if line == "" {
+			continue
+		}
+		ring[count%n] = truncate(line, maxLogLineBytes)
+		count++
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	if count <= n {
+		return ring[:count], err
+	}
+	start := count % n
+	return append(ring[start:], ring[:start]...), err
+}
+
+// truncate shortens s to at most max bytes on a rune boundary, marking the
+// cut with an ellipsis.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - len("…")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
@@ -0,0 +1,120 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestFetchBranchChecks_FailureDetails(t *testing.T) {
+	var annotationCalls, logCalls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/commits", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"sha": "abc123"}]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_count": 2, "check_runs": [
+			{"id": 77, "name": "build", "status": "completed", "conclusion": "failure", "app": {"slug": "github-actions"},
+			 "output": {"title": "2 errors", "summary": "Build failed", "annotations_count": 2}},
+			{"id": 78, "name": "lint", "status": "completed", "conclusion": "success", "app": {"slug": "github-actions"},
+			 "output": {"title": "ok", "annotations_count": 1}}
+		]}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/check-runs/77/annotations", func(w http.ResponseWriter, r *http.Request) {
+		annotationCalls++
+		if got := r.URL.Query().Get("per_page"); got != "5" {
+			t.Errorf("per_page = %q, want the configured 5", got)
+		}
+		w.Write([]byte(`[
+			{"path": "README.md", "start_line": 1, "end_line": 1, "annotation_level": "warning", "message": "style"},
+			{"path": "src/grib.c", "start_line": 42, "end_line": 44, "annotation_level": "failure", "title": "error", "message": "undefined symbol"}
+		]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/actions/jobs/77/logs", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://"+r.Host+"/blob/77.txt", http.StatusFound)
+	})
+	mux.HandleFunc("/blob/77.txt", func(w http.ResponseWriter, r *http.Request) {
+		logCalls++
+		if r.Header.Get("Authorization") != "" {
+			t.Error("log download carries credentials")
+		}
+		for i := 1; i <= 10; i++ {
+			fmt.Fprintf(w, "2026-05-01T10:00:%02d.1234567Z \x1b[31mline %d\x1b[0m\n", i, i)
+		}
+	})
+	for _, path := range []string{"/repos/ecmwf/eccodes/actions/runs", "/repos/ecmwf/eccodes/commits/abc123/status"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{}`)) })
+	}
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	repos := []config.RepositoryConfig{{Name: "eccodes", Branches: []string{"develop"}}}
+	settings := config.FailuresConfig{Annotations: 5, LogLines: 3}
+	result := c.FetchBranchChecks(context.Background(), "ecmwf", repos, settings)
+	if result.Err != nil || len(result.BranchChecks) != 1 {
+		t.Fatalf("FetchBranchChecks() = %+v", result)
+	}
+
+	build, lint := result.BranchChecks[0].Checks[0], result.BranchChecks[0].Checks[1]
+	if lint.Failure != nil {
+		t.Errorf("passing check has failure details %+v", lint.Failure)
+	}
+	want := &FailureDetails{
+		Title:           "2 errors",
+		Summary:         "Build failed",
+		AnnotationCount: 2,
+		Annotations: []Annotation{
+			{Path: "src/grib.c", StartLine: 42, EndLine: 44, Level: "failure", Title: "error", Message: "undefined symbol"},
+			{Path: "README.md", StartLine: 1, EndLine: 1, Level: "warning", Message: "style"},
+		},
+		Log: []string{"line 8", "line 9", "line 10"},
+	}
+	if build.ID != 77 || !reflect.DeepEqual(build.Failure, want) {
+		t.Errorf("build = %d %+v, want %+v", build.ID, build.Failure, want)
+	}
+	if loc := build.Failure.Annotations[0].Location(); loc != "src/grib.c:42-44" {
+		t.Errorf("Location() = %q", loc)
+	}
+
+	// The completed run's details are cached.
+	c.FetchBranchChecks(context.Background(), "ecmwf", repos, settings)
+	if annotationCalls != 1 || logCalls != 1 {
+		t.Errorf("annotation calls = %d, log calls = %d, want 1 each", annotationCalls, logCalls)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	got, err := tailLines(strings.NewReader("a\n\nb\r\nc\n"), 5)
+	if err != nil || !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("tailLines() = %q, %v", got, err)
+	}
+	long := strings.Repeat("x", maxLogLineBytes+10)
+	got, _ = tailLines(strings.NewReader("1\n2\n3\n"+long+"\n"), 2)
+	if len(got) != 2 || got[0] != "3" || len(got[1]) > maxLogLineBytes {
+		t.Errorf("tailLines() = %d lines starting %q, want the last 2 truncated", len(got), got[0])
+	}
+
+	// Lines longer than the read buffer are truncated, not an error.
+	huge := strings.Repeat("y", 2<<20)
+	got, err = tailLines(strings.NewReader("1\n"+huge+"\nlast"), 3)
+	if err != nil || len(got) != 3 || got[0] != "1" || !strings.HasPrefix(got[1], "yyy") || len(got[1]) > maxLogLineBytes || got[2] != "last" {
+		t.Errorf("tailLines() = %d lines, %v; want 1, the truncated line and last", len(got), err)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate(short) = %q", got)
+	}
+	got := truncate("ääääää", 8) // 2 bytes per rune
+	if got != "ää…" {
+		t.Errorf("truncate() = %q, want a cut on a rune boundary", got)
+	}
+}
@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MergeQueueEntry is a pull request's place in the merge queue of its base
+// branch.
+type MergeQueueEntry struct {
+	Position   int    // 1 is merged next
+	State      string // QUEUED, AWAITING_CHECKS, MERGEABLE, UNMERGEABLE or LOCKED
+	EnqueuedAt time.Time
+}
+
+// StateLabel returns State in words, e.g. "awaiting checks".
+func (e MergeQueueEntry) StateLabel() string {
+	return strings.ToLower(strings.ReplaceAll(e.State, "_", " "))
+}
+
+// AutoMerge is set on a pull request GitHub merges once its requirements
+// are met.
+type AutoMerge struct {
+	EnabledBy   string
+	MergeMethod string // merge, squash or rebase
+}
+
+// prGraphQL is what the GraphQL API adds to the REST view of a pull request.
+type prGraphQL struct {
+	ClosingIssues []IssueRef
+	MergeQueue    *MergeQueueEntry
+}
+
+// openPRsQuery reads the linked issues (up to 25) and merge queue entry of
+// each open pull request.
+const openPRsQuery = `query($owner: String!, $name: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(states: OPEN, first: 100, after: $after) {
+      nodes {
+        number
+        closingIssuesReferences(first: 25) {
+          nodes { number url repository { nameWithOwner } }
+        }
+        mergeQueueEntry { position state enqueuedAt }
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+type openPRsResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequests struct {
+				Nodes []struct {
+					Number                  int
+					ClosingIssuesReferences struct {
+						Nodes []struct {
+							Number     int
+							URL        string
+							Repository struct{ NameWithOwner string }
+						}
+					}
+					MergeQueueEntry *struct {
+						Position   int
+						State      string
+						EnqueuedAt time.Time
+					}
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			}
+		}
+	}
+	Errors []struct{ Message string }
+}
+
+// openPRsGraphQL asks the GraphQL API for what the REST API does not have:
+// the issues linked to each open pull request of repo, including links
+// made in the sidebar that the description does not mention, and its merge
+// queue entry. GraphQL requests count against their own rate limit, so
+// none is returned.
+func (c *Client) openPRsGraphQL(ctx context.Context, org, repo string) (map[int]prGraphQL, error) {
+	prs := make(map[int]prGraphQL)
+	vars := map[string]any{"owner": org, "name": repo}
+	for {
+		req, err := c.gh.NewRequest("POST", "graphql", map[string]any{"query": openPRsQuery, "variables": vars})
+		if err != nil {
+			return nil, err
+		}
+		var out openPRsResponse
+		if _, err := c.gh.Do(ctx, req, &out); err != nil {
+			return nil, err
+		}
+		if len(out.Errors) > 0 {
+			return nil, fmt.Errorf("graphql: %s", out.Errors[0].Message)
+		}
+
+		page := out.Data.Repository.PullRequests
+		for _, node := range page.Nodes {
+			var pr prGraphQL
+			for _, issue := range node.ClosingIssuesReferences.Nodes {
+				pr.ClosingIssues = addIssueRefs(pr.ClosingIssues, newIssueRef(org, issue.Repository.NameWithOwner, issue.Number, issue.URL))
+			}
+			if e := node.MergeQueueEntry; e != nil {
+				pr.MergeQueue = &MergeQueueEntry{Position: e.Position, State: e.State, EnqueuedAt: e.EnqueuedAt}
+			}
+			prs[node.Number] = pr
+		}
+		if !page.PageInfo.HasNextPage {
+			return prs, nil
+		}
+		vars["after"] = page.PageInfo.EndCursor
+	}
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestFetchPullRequests_MergeQueueAndAutoMerge(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"number": 5, "title": "Queued", "state": "open"},
+			{"number": 6, "title": "Auto", "state": "open", "auto_merge": {"enabled_by": {"login": "alice"}, "merge_method": "squash"}},
+			{"number": 7, "title": "Plain", "state": "open"}
+		]`))
+	})
+	calls := 0
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Write([]byte(`{"data": {"repository": {"pullRequests": {
+				"nodes": [{"number": 5, "closingIssuesReferences": {"nodes": []},
+					"mergeQueueEntry": {"position": 2, "state": "AWAITING_CHECKS", "enqueuedAt": "2026-10-19T08:00:00Z"}}],
+				"pageInfo": {"hasNextPage": true, "endCursor": "c1"}
+			}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequests": {
+			"nodes": [{"number": 6, "closingIssuesReferences": {"nodes": []}, "mergeQueueEntry": null},
+				{"number": 7, "closingIssuesReferences": {"nodes": []}, "mergeQueueEntry": null}],
+			"pageInfo": {"hasNextPage": false}
+		}}}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	result := c.FetchPullRequests(context.Background(), "ecmwf", []config.RepositoryConfig{{Name: "eccodes"}})
+	if calls != 2 {
+		t.Errorf("made %d GraphQL requests, want 2 pages", calls)
+	}
+	if len(result.PullRequests) != 3 {
+		t.Fatalf("got %d pull requests, want 3", len(result.PullRequests))
+	}
+
+	queued, auto, plain := result.PullRequests[0], result.PullRequests[1], result.PullRequests[2]
+	want := MergeQueueEntry{Position: 2, State: "AWAITING_CHECKS", EnqueuedAt: time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)}
+	if queued.MergeQueue == nil || *queued.MergeQueue != want {
+		t.Errorf("MergeQueue = %+v, want %+v", queued.MergeQueue, want)
+	}
+	if auto.AutoMerge == nil || *auto.AutoMerge != (AutoMerge{EnabledBy: "alice", MergeMethod: "squash"}) {
+		t.Errorf("AutoMerge = %+v", auto.AutoMerge)
+	}
+	if queued.AutoMerge != nil || auto.MergeQueue != nil || plain.AutoMerge != nil || plain.MergeQueue != nil {
+		t.Error("only the queued PR should be queued and only the auto PR have auto-merge")
+	}
+}
@@ -3,10 +3,11 @@ package github
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	gh "github.com/google/go-github/v83/github"
 	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
 )
 
 func (c *Client) FetchIssues(ctx context.Context, org string, repos []config.RepositoryConfig) IssuesFetchResult {
@@ -25,16 +26,17 @@ func (c *Client) FetchIssues(ctx context.Context, org string, repos []config.Rep
 			},
 		}
 
-		repoFailed := false
+		repoCtx, span := tracing.StartRepoSpan(ctx, "issues", org, repo.Name)
+		var repoErr error
 		for {
-			if ctx.Err() != nil {
+			if repoCtx.Err() != nil {
 				break
 			}
 
-			issues, resp, err := c.gh.Issues.ListByRepo(ctx, org, repo.Name, opts)
+			issues, resp, err := c.gh.Issues.ListByRepo(repoCtx, org, repo.Name, opts)
 			if err != nil {
-				log.Printf("Error fetching issues for %s/%s: %v", org, repo.Name, err)
-				repoFailed = true
+				slog.Error("fetching issues failed", "category", "issues", "org", org, "repo", repo.Name, "error", err)
+				repoErr = err
 				break
 			}
 			if resp != nil {
@@ -56,6 +58,9 @@ func (c *Client) FetchIssues(ctx context.Context, org string, repos []config.Rep
 					AuthorAvatar: ghIssue.GetUser().GetAvatarURL(),
 					CreatedAt:    ghIssue.GetCreatedAt().Time,
 					UpdatedAt:    ghIssue.GetUpdatedAt().Time,
+
+					Milestone:       ghIssue.GetMilestone().GetTitle(),
+					MilestoneNumber: ghIssue.GetMilestone().GetNumber(),
 				}
 
 				// Set author association and external flag
@@ -82,8 +87,9 @@ func (c *Client) FetchIssues(ctx context.Context, org string, repos []config.Rep
 			}
 			opts.ListOptions.Page = resp.NextPage
 		}
+		tracing.EndSpan(span, repoErr)
 
-		if repoFailed {
+		if repoErr != nil {
 			result.FailedRepos = append(result.FailedRepos, repo.Name)
 		} else {
 			result.SucceededRepos = append(result.SucceededRepos, repo.Name)
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	gh "github.com/google/go-github/v83/github"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
+	"github.com/ozaq/ecmwf-dash/internal/tracing/tracingtest"
+)
+
+// newTestClient returns a Client talking to srv through the tracing
+// transport, as NewClient would.
+func newTestClient(t *testing.T, srv *httptest.Server) *Client {
+	t.Helper()
+	client := gh.NewClient(&http.Client{Transport: tracing.Transport(http.DefaultTransport)})
+	base, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = base
+	return &Client{gh: client}
+}
+
+func TestFetchIssues_RecordsSpans(t *testing.T) {
+	exp := tracingtest.Record(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"number": 1, "title": "bug", "state": "open", "milestone": {"number": 3, "title": "2.40.0"}}]`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	result := c.FetchIssues(context.Background(), "ecmwf", []config.RepositoryConfig{{Name: "eccodes"}})
+	if result.Err != nil || len(result.Issues) != 1 {
+		t.Fatalf("FetchIssues() = %d issues, err %v", len(result.Issues), result.Err)
+	}
+	if issue := result.Issues[0]; issue.Milestone != "2.40.0" || issue.MilestoneNumber != 3 {
+		t.Errorf("milestone = %q #%d, want 2.40.0 #3", issue.Milestone, issue.MilestoneNumber)
+	}
+
+	repoSpan := tracingtest.Find(exp, "fetch issues eccodes")
+	if repoSpan == nil {
+		t.Fatal("repo span not recorded")
+	}
+	apiSpan := tracingtest.Find(exp, "GitHub GET")
+	if apiSpan == nil {
+		var names []string
+		for _, s := range exp.GetSpans() {
+			names = append(names, s.Name)
+		}
+		t.Fatalf("API call span not recorded; got %v", names)
+	}
+	if apiSpan.Parent.SpanID() != repoSpan.SpanContext.SpanID() {
+		t.Error("API call span should be a child of the repo span")
+	}
+	attrs := make(map[string]string)
+	for _, kv := range apiSpan.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["org"] != "ecmwf" || attrs["repo"] != "eccodes" {
+		t.Errorf("API call span attributes = %v, want org and repo", attrs)
+	}
+}
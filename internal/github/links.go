@@ -0,0 +1,70 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IssueRef is an issue a pull request closes, either by a keyword in its
+// description ("Fixes #123") or by a link set on GitHub.
+type IssueRef struct {
+	Repository string // repository name; "owner/name" outside the organization
+	Number     int
+	URL        string
+}
+
+// closingRefPattern matches GitHub's closing keywords followed by "#123",
+// "owner/repo#123" or an issue URL.
+var closingRefPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+(?:#|([\w.-]+/[\w.-]+)#|https://github\.com/([\w.-]+/[\w.-]+)/issues/)(\d+)\b`)
+
+// closingRefs extracts the issues a pull request description says it
+// closes. Plain "#123" refers to repo.
+func closingRefs(org, repo, body string) []IssueRef {
+	var refs []IssueRef
+	for _, m := range closingRefPattern.FindAllStringSubmatch(body, -1) {
+		number, err := strconv.Atoi(m[3])
+		if err != nil || number <= 0 {
+			continue
+		}
+		full := org + "/" + repo
+		if m[1] != "" {
+			full = m[1]
+		} else if m[2] != "" {
+			full = m[2]
+		}
+		refs = addIssueRefs(refs, newIssueRef(org, full, number, ""))
+	}
+	return refs
+}
+
+// newIssueRef builds a ref to issue number of the repository fullName
+// ("owner/name"), defaulting its URL.
+func newIssueRef(org, fullName string, number int, url string) IssueRef {
+	if url == "" {
+		url = fmt.Sprintf("https://github.com/%s/issues/%d", fullName, number)
+	}
+	owner, name, _ := strings.Cut(fullName, "/")
+	if strings.EqualFold(owner, org) {
+		fullName = name
+	}
+	return IssueRef{Repository: fullName, Number: number, URL: url}
+}
+
+// addIssueRefs appends the refs not already in refs.
+func addIssueRefs(refs []IssueRef, more ...IssueRef) []IssueRef {
+	for _, ref := range more {
+		dup := false
+		for _, r := range refs {
+			if strings.EqualFold(r.Repository, ref.Repository) && r.Number == ref.Number {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestClosingRefs(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []IssueRef
+	}{
+		{"none", "Refactors the decoder. See #12.", nil},
+		{"same repo", "Fixes #12", []IssueRef{{Repository: "eccodes", Number: 12, URL: "https://github.com/ecmwf/eccodes/issues/12"}}},
+		{"keyword with colon", "closes: #7", []IssueRef{{Repository: "eccodes", Number: 7, URL: "https://github.com/ecmwf/eccodes/issues/7"}}},
+		{"other repo in org", "Resolves ecmwf/eckit#45", []IssueRef{{Repository: "eckit", Number: 45, URL: "https://github.com/ecmwf/eckit/issues/45"}}},
+		{"outside org", "Fixed other/lib#3", []IssueRef{{Repository: "other/lib", Number: 3, URL: "https://github.com/other/lib/issues/3"}}},
+		{"url", "This closes https://github.com/ecmwf/fdb/issues/9 at last", []IssueRef{{Repository: "fdb", Number: 9, URL: "https://github.com/ecmwf/fdb/issues/9"}}},
+		{"several and duplicates", "Fixes #1, fixes #2\nFIXES #1", []IssueRef{
+			{Repository: "eccodes", Number: 1, URL: "https://github.com/ecmwf/eccodes/issues/1"},
+			{Repository: "eccodes", Number: 2, URL: "https://github.com/ecmwf/eccodes/issues/2"},
+		}},
+		{"not a keyword", "Prefixes #3 and suffixes #4", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closingRefs("ecmwf", "eccodes", tt.body); !slices.Equal(got, tt.want) {
+				t.Errorf("closingRefs(%q) = %+v, want %+v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchPullRequests_ClosingIssues(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"number": 5, "title": "Fix decoder", "state": "open", "body": "Fixes #12"}]`))
+	})
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]any
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode graphql request: %v", err)
+		}
+		if req.Variables["owner"] != "ecmwf" || req.Variables["name"] != "eccodes" {
+			t.Errorf("variables = %v", req.Variables)
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequests": {
+			"nodes": [{"number": 5, "closingIssuesReferences": {"nodes": [
+				{"number": 12, "url": "https://github.com/ecmwf/eccodes/issues/12", "repository": {"nameWithOwner": "ecmwf/eccodes"}},
+				{"number": 45, "url": "https://github.com/ecmwf/eckit/issues/45", "repository": {"nameWithOwner": "ecmwf/eckit"}}
+			]}}],
+			"pageInfo": {"hasNextPage": false}
+		}}}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	result := c.FetchPullRequests(context.Background(), "ecmwf", []config.RepositoryConfig{{Name: "eccodes"}})
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.PullRequests) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(result.PullRequests))
+	}
+	want := []IssueRef{
+		{Repository: "eccodes", Number: 12, URL: "https://github.com/ecmwf/eccodes/issues/12"},
+		{Repository: "eckit", Number: 45, URL: "https://github.com/ecmwf/eckit/issues/45"},
+	}
+	if got := result.PullRequests[0].ClosingIssues; !slices.Equal(got, want) {
+		t.Errorf("ClosingIssues = %+v, want %+v", got, want)
+	}
+}
+
+func TestFetchPullRequests_LinkedIssuesFailure(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"number": 5, "title": "Fix decoder", "state": "open", "body": "Closes #12"}]`))
+	})
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors": [{"message": "Resource not accessible by integration"}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	result := c.FetchPullRequests(context.Background(), "ecmwf", []config.RepositoryConfig{{Name: "eccodes"}})
+	if len(result.FailedRepos) != 0 {
+		t.Errorf("failed repos = %v; a GraphQL error must not fail the repo", result.FailedRepos)
+	}
+	if len(result.PullRequests) != 1 || len(result.PullRequests[0].ClosingIssues) != 1 || result.PullRequests[0].ClosingIssues[0].Number != 12 {
+		t.Errorf("pull requests = %+v, want the reference from the description", result.PullRequests)
+	}
+}
@@ -0,0 +1,274 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	gh "github.com/google/go-github/v83/github"
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
+)
+
+// maxProjectItems caps the items read from one project board, at 100 per
+// request.
+const maxProjectItems = 500
+
+// ProjectBoard is an organization Projects (v2) board with its items
+// grouped into columns by the status field.
+type ProjectBoard struct {
+	Number      int
+	Title       string
+	URL         string
+	StatusField string
+	Columns     []string // status options in board order
+	Items       []ProjectItem
+	Truncated   bool // more than maxProjectItems items
+
+	// FetchFailed is set when the board could not be read; the store then
+	// keeps the earlier board.
+	FetchFailed bool
+}
+
+// ProjectItem is an issue, pull request or draft issue on a board.
+type ProjectItem struct {
+	Repository string // "" for draft issues; "owner/name" outside the organization
+	Kind       string // "issue", "pull" or "draft"
+	Number     int
+	Title      string
+	URL        string
+	State      string // open or closed; "" for draft issues
+	Status     string // "" when the status field is unset
+}
+
+// FetchPlanning fetches the open milestones of each repository and the
+// configured project boards. Boards belong to the organization, so a
+// failed board does not fail any repository; it is returned with
+// FetchFailed set.
+func (c *Client) FetchPlanning(ctx context.Context, org string, repos []config.RepositoryConfig, projects []config.ProjectConfig) PlanningFetchResult {
+	var result PlanningFetchResult
+	successCount := 0
+
+	for _, repo := range repos {
+		if ctx.Err() != nil {
+			break
+		}
+
+		repoCtx, span := tracing.StartRepoSpan(ctx, "planning", org, repo.Name)
+		milestones, rate, err := c.openMilestones(repoCtx, org, repo.Name)
+		if rate.Limit > 0 {
+			result.Rate = rate
+		}
+		tracing.EndSpan(span, err)
+
+		if err != nil {
+			slog.Error("fetching milestones failed", "category", "planning", "org", org, "repo", repo.Name, "error", err)
+			result.FailedRepos = append(result.FailedRepos, repo.Name)
+			continue
+		}
+		result.Milestones = append(result.Milestones, milestones...)
+		result.SucceededRepos = append(result.SucceededRepos, repo.Name)
+		successCount++
+	}
+
+	attempted := len(result.SucceededRepos) + len(result.FailedRepos)
+	for i := attempted; i < len(repos); i++ {
+		result.FailedRepos = append(result.FailedRepos, repos[i].Name)
+	}
+
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			result.Boards = append(result.Boards, ProjectBoard{Number: project.Number, FetchFailed: true})
+			continue
+		}
+		board, rate, err := c.projectBoard(ctx, org, project)
+		if rate.Limit > 0 {
+			result.Rate = rate
+		}
+		if err != nil {
+			slog.Error("fetching project board failed", "category", "planning", "org", org, "project", project.Number, "error", err)
+		} else {
+			successCount++
+		}
+		result.Boards = append(result.Boards, board)
+	}
+
+	if successCount == 0 && len(repos)+len(projects) > 0 {
+		if ctx.Err() != nil {
+			result.Err = ctx.Err()
+		} else {
+			result.Err = fmt.Errorf("all %d repos and %d projects failed", len(repos), len(projects))
+		}
+	}
+
+	return result
+}
+
+// openMilestones lists the open milestones of repo, soonest due first.
+func (c *Client) openMilestones(ctx context.Context, org, repo string) ([]Milestone, RateInfo, error) {
+	var milestones []Milestone
+	var rate RateInfo
+	opts := &gh.MilestoneListOptions{
+		State:       "open",
+		Sort:        "due_on",
+		Direction:   "asc",
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := c.gh.Issues.ListMilestones(ctx, org, repo, opts)
+		if resp != nil {
+			rate = rateFromResponse(resp)
+		}
+		if err != nil {
+			return nil, rate, err
+		}
+		for _, m := range page {
+			milestones = append(milestones, newMilestone(repo, m))
+		}
+		if resp.NextPage == 0 {
+			return milestones, rate, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// projectBoard reads a board's title, the options of its status field and
+// up to maxProjectItems non-archived items.
+func (c *Client) projectBoard(ctx context.Context, org string, project config.ProjectConfig) (ProjectBoard, RateInfo, error) {
+	board := ProjectBoard{
+		Number:      project.Number,
+		URL:         fmt.Sprintf("https://github.com/orgs/%s/projects/%d", org, project.Number),
+		StatusField: project.StatusFieldName(),
+	}
+	var rate RateInfo
+	track := func(resp *gh.Response) {
+		if resp != nil {
+			rate = rateFromResponse(resp)
+		}
+	}
+	fail := func(err error) (ProjectBoard, RateInfo, error) {
+		return ProjectBoard{Number: board.Number, URL: board.URL, StatusField: board.StatusField, FetchFailed: true}, rate, err
+	}
+
+	p, resp, err := c.gh.Projects.GetOrganizationProject(ctx, org, project.Number)
+	track(resp)
+	if err != nil {
+		return fail(fmt.Errorf("project: %w", err))
+	}
+	board.Title = p.GetTitle()
+
+	fields, resp, err := c.gh.Projects.ListOrganizationProjectFields(ctx, org, project.Number, &gh.ListProjectsOptions{
+		ListProjectsPaginationOptions: gh.ListProjectsPaginationOptions{PerPage: 100},
+	})
+	track(resp)
+	if err != nil {
+		return fail(fmt.Errorf("fields: %w", err))
+	}
+	var statusID int64
+	for _, f := range fields {
+		if strings.EqualFold(f.GetName(), board.StatusField) {
+			statusID = f.GetID()
+			for _, o := range f.Options {
+				board.Columns = append(board.Columns, o.GetName().GetRaw())
+			}
+			break
+		}
+	}
+	if statusID == 0 {
+		return fail(fmt.Errorf("project has no field %q", board.StatusField))
+	}
+
+	opts := &gh.ListProjectItemsOptions{
+		ListProjectsOptions: gh.ListProjectsOptions{
+			ListProjectsPaginationOptions: gh.ListProjectsPaginationOptions{PerPage: 100},
+		},
+		Fields: []int64{statusID},
+	}
+	for {
+		items, resp, err := c.gh.Projects.ListOrganizationProjectItems(ctx, org, project.Number, opts)
+		track(resp)
+		if err != nil {
+			return fail(fmt.Errorf("items: %w", err))
+		}
+		for _, item := range items {
+			if item.ArchivedAt != nil {
+				continue
+			}
+			if len(board.Items) == maxProjectItems {
+				board.Truncated = true
+				return board, rate, nil
+			}
+			board.Items = append(board.Items, newProjectItem(org, item, statusID))
+		}
+		if resp.After == "" {
+			return board, rate, nil
+		}
+		opts.After = resp.After
+	}
+}
+
+func newProjectItem(org string, item *gh.ProjectV2Item, statusID int64) ProjectItem {
+	var pi ProjectItem
+	content := item.GetContent()
+	switch {
+	case content != nil && content.Issue != nil:
+		pi.Kind = "issue"
+		pi.Number = content.Issue.GetNumber()
+		pi.Title = content.Issue.GetTitle()
+		pi.URL = content.Issue.GetHTMLURL()
+		pi.State = content.Issue.GetState()
+	case content != nil && content.PullRequest != nil:
+		pi.Kind = "pull"
+		pi.Number = content.PullRequest.GetNumber()
+		pi.Title = content.PullRequest.GetTitle()
+		pi.URL = content.PullRequest.GetHTMLURL()
+		pi.State = content.PullRequest.GetState()
+	default:
+		pi.Kind = "draft"
+		if content != nil && content.DraftIssue != nil {
+			pi.Title = content.DraftIssue.GetTitle()
+		}
+	}
+	pi.Repository = repoFromHTMLURL(org, pi.URL)
+
+	for _, f := range item.Fields {
+		if f.GetID() == statusID {
+			pi.Status = optionName(f.Value)
+		}
+	}
+	return pi
+}
+
+// repoFromHTMLURL extracts the repository from an issue or pull request
+// URL such as https://github.com/ecmwf/eccodes/issues/12, qualified with
+// its owner when that is not org.
+func repoFromHTMLURL(org, url string) string {
+	parts := strings.Split(strings.TrimPrefix(url, "https://github.com/"), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		return ""
+	}
+	if strings.EqualFold(parts[0], org) {
+		return parts[1]
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// optionName returns the option name of a single-select field value,
+// which the API returns as an object with a name that is either a string
+// or a {raw, html} pair.
+func optionName(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		switch name := v["name"].(type) {
+		case string:
+			return name
+		case map[string]any:
+			raw, _ := name["raw"].(string)
+			return raw
+		}
+	}
+	return ""
+}
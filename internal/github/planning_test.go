@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestFetchPlanning(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/milestones", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sort") != "due_on" {
+			t.Errorf("sort = %q, want due_on", r.URL.Query().Get("sort"))
+		}
+		w.Write([]byte(`[{"number": 3, "title": "2.40.0", "due_on": "2026-06-01T00:00:00Z", "open_issues": 2, "closed_issues": 6}]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/fdb/milestones", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/orgs/ecmwf/projectsV2/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"number": 7, "title": "Roadmap"}`))
+	})
+	mux.HandleFunc("/orgs/ecmwf/projectsV2/7/fields", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id": 1, "name": "Title", "data_type": "title"},
+			{"id": 2, "name": "Status", "data_type": "single_select", "options": [
+				{"id": "a", "name": {"raw": "Todo"}}, {"id": "b", "name": {"raw": "In progress"}}, {"id": "c", "name": {"raw": "Done"}}
+			]}
+		]`))
+	})
+	mux.HandleFunc("/orgs/ecmwf/projectsV2/7/items", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("fields"); got != "2" {
+			t.Errorf("fields = %q, want the status field", got)
+		}
+		if r.URL.Query().Get("after") == "" {
+			w.Header().Set("Link", `<`+r.URL.Path+`?after=cursor1>; rel="next"`)
+			w.Write([]byte(`[
+				{"id": 10, "content_type": "Issue", "content": {"number": 12, "title": "GRIB3 support", "state": "open", "html_url": "https://github.com/ecmwf/eccodes/issues/12"},
+				 "fields": [{"id": 2, "name": "Status", "data_type": "single_select", "value": {"id": "b", "name": {"raw": "In progress", "html": "In progress"}}}]},
+				{"id": 11, "content_type": "PullRequest", "content": {"number": 40, "title": "Upstream fix", "state": "open", "html_url": "https://github.com/other/lib/pull/40"}, "fields": []}
+			]`))
+			return
+		}
+		w.Write([]byte(`[
+			{"id": 12, "content_type": "DraftIssue", "content": {"title": "Plan 2027"}, "fields": [{"id": 2, "value": {"name": "Todo"}}]},
+			{"id": 13, "content_type": "Issue", "archived_at": "2026-01-01T00:00:00Z", "content": {"number": 1, "title": "Archived"}}
+		]`))
+	})
+	mux.HandleFunc("/orgs/ecmwf/projectsV2/8", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	repos := []config.RepositoryConfig{{Name: "eccodes"}, {Name: "fdb"}}
+	projects := []config.ProjectConfig{{Number: 7}, {Number: 8}}
+	result := c.FetchPlanning(context.Background(), "ecmwf", repos, projects)
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.SucceededRepos) != 1 || len(result.FailedRepos) != 1 || result.FailedRepos[0] != "fdb" {
+		t.Errorf("succeeded %v, failed %v; want fdb to fail", result.SucceededRepos, result.FailedRepos)
+	}
+	if len(result.Milestones) != 1 || result.Milestones[0].Repository != "eccodes" || result.Milestones[0].Progress() != 75 {
+		t.Errorf("milestones = %+v", result.Milestones)
+	}
+
+	if len(result.Boards) != 2 {
+		t.Fatalf("got %d boards, want 2", len(result.Boards))
+	}
+	board := result.Boards[0]
+	if board.Title != "Roadmap" || len(board.Columns) != 3 || board.Columns[1] != "In progress" || board.URL != "https://github.com/orgs/ecmwf/projects/7" {
+		t.Errorf("board = %+v", board)
+	}
+	want := []ProjectItem{
+		{Repository: "eccodes", Kind: "issue", Number: 12, Title: "GRIB3 support", URL: "https://github.com/ecmwf/eccodes/issues/12", State: "open", Status: "In progress"},
+		{Repository: "other/lib", Kind: "pull", Number: 40, Title: "Upstream fix", URL: "https://github.com/other/lib/pull/40", State: "open"},
+		{Kind: "draft", Title: "Plan 2027", Status: "Todo"},
+	}
+	if len(board.Items) != len(want) {
+		t.Fatalf("items = %+v, want %d without the archived one", board.Items, len(want))
+	}
+	for i := range want {
+		if board.Items[i] != want[i] {
+			t.Errorf("items[%d] = %+v, want %+v", i, board.Items[i], want[i])
+		}
+	}
+	if !result.Boards[1].FetchFailed {
+		t.Errorf("missing project = %+v, want FetchFailed", result.Boards[1])
+	}
+}
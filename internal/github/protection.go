@@ -0,0 +1,111 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// requiredTTL is how long required contexts are reused; branch protection
+// changes rarely and costs two requests per branch to read.
+const requiredTTL = 30 * time.Minute
+
+// requiredRetryTTL is how long required contexts are reused when a source
+// could not be read, so a passing error does not hide a requirement for
+// long.
+const requiredRetryTTL = 2 * time.Minute
+
+type requiredEntry struct {
+	contexts []string
+	expires  time.Time
+}
+
+// cachedRequiredContexts is requiredContexts with a per-branch cache.
+func (c *Client) cachedRequiredContexts(ctx context.Context, org, repo, branch string) ([]string, RateInfo) {
+	key := org + "/" + repo + "@" + branch
+	c.mu.Lock()
+	e, ok := c.required[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(e.expires) {
+		return e.contexts, RateInfo{}
+	}
+
+	contexts, rate, err := c.requiredContexts(ctx, org, repo, branch)
+	ttl := requiredTTL
+	if err != nil {
+		ttl = requiredRetryTTL
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.required == nil {
+		c.required = make(map[string]requiredEntry)
+	}
+	c.required[key] = requiredEntry{contexts: contexts, expires: time.Now().Add(ttl)}
+	return contexts, rate
+}
+
+// requiredContexts returns the status check contexts that must pass on
+// branch, from classic branch protection and from rulesets. Sources that
+// cannot be read are skipped: reading branch protection needs admin rights
+// on the repository, which a read-only dashboard token usually lacks. err
+// reports them, alongside the contexts that could be read.
+func (c *Client) requiredContexts(ctx context.Context, org, repo, branch string) (contexts []string, rate RateInfo, err error) {
+	checks, resp, protErr := c.gh.Repositories.GetRequiredStatusChecks(ctx, org, repo, branch)
+	if resp != nil {
+		rate = rateFromResponse(resp)
+	}
+	switch {
+	case protErr == nil:
+		for _, rc := range checks.GetChecks() {
+			contexts = append(contexts, rc.Context)
+		}
+		contexts = append(contexts, checks.GetContexts()...)
+	case isNotFound(protErr):
+		// branch not protected
+	default:
+		slog.Debug("reading branch protection failed", "category", "checks", "org", org, "repo", repo, "branch", branch, "error", protErr)
+		err = protErr
+	}
+
+	rules, resp, rulesErr := c.gh.Repositories.GetRulesForBranch(ctx, org, repo, branch, nil)
+	if resp != nil {
+		rate = rateFromResponse(resp)
+	}
+	if rulesErr != nil {
+		slog.Debug("reading branch rulesets failed", "category", "checks", "org", org, "repo", repo, "branch", branch, "error", rulesErr)
+		err = errors.Join(err, rulesErr)
+	} else {
+		for _, rule := range rules.RequiredStatusChecks {
+			for _, rc := range rule.Parameters.RequiredStatusChecks {
+				contexts = append(contexts, rc.Context)
+			}
+		}
+	}
+
+	slices.Sort(contexts)
+	return slices.Compact(contexts), rate, err
+}
+
+// markRequired flags the checks whose name is a required context and
+// returns the required contexts no check reported. Checks skipped on
+// purpose are not among checks, but their names count as reported.
+func markRequired(checks []Check, required, skipped []string) (missing []string) {
+	reported := make(map[string]bool, len(checks)+len(skipped))
+	for _, name := range skipped {
+		reported[name] = true
+	}
+	for i := range checks {
+		if slices.Contains(required, checks[i].Name) {
+			checks[i].Required = true
+			reported[checks[i].Name] = true
+		}
+	}
+	for _, ctx := range required {
+		if !reported[ctx] {
+			missing = append(missing, ctx)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRequiredContexts(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/branches/develop/protection/required_status_checks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"strict": true, "contexts": ["test"], "checks": [{"context": "test"}]}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/rules/branches/develop", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"type": "required_status_checks", "ruleset_id": 1, "parameters": {
+			"strict_required_status_checks_policy": false,
+			"required_status_checks": [{"context": "build (ubuntu)"}, {"context": "test"}]
+		}}]`))
+	})
+	// atlas: not protected, and rulesets unreadable
+	mux.HandleFunc("/repos/ecmwf/atlas/branches/main/protection/required_status_checks", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Branch not protected"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/ecmwf/atlas/rules/branches/main", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Forbidden"}`, http.StatusForbidden)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c := newTestClient(t, srv)
+
+	got, _, err := c.requiredContexts(context.Background(), "ecmwf", "eccodes", "develop")
+	if want := []string{"build (ubuntu)", "test"}; !slices.Equal(got, want) || err != nil {
+		t.Errorf("eccodes/develop required = %q, %v; want %q", got, err, want)
+	}
+	got, _, err = c.requiredContexts(context.Background(), "ecmwf", "atlas", "main")
+	if len(got) != 0 {
+		t.Errorf("atlas/main required = %q, want none", got)
+	}
+	if err == nil {
+		t.Error("atlas/main: unreadable rulesets not reported")
+	}
+}
+
+func TestMarkRequired(t *testing.T) {
+	checks := []Check{{Name: "test"}, {Name: "lint"}, {Name: "test"}}
+	missing := markRequired(checks, []string{"docs", "test", "windows"}, []string{"windows"})
+
+	if !checks[0].Required || checks[1].Required || !checks[2].Required {
+		t.Errorf("required flags = %v %v %v, want true false true", checks[0].Required, checks[1].Required, checks[2].Required)
+	}
+	if !slices.Equal(missing, []string{"docs"}) {
+		t.Errorf("missing = %q, want [docs]; skipped windows counts as reported", missing)
+	}
+}
+
+func TestCachedRequiredContextsRetriesFailures(t *testing.T) {
+	var calls int
+	fail := true
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/branches/develop/protection/required_status_checks", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if fail {
+			http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"contexts": ["test"]}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/rules/branches/develop", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	c.cachedRequiredContexts(ctx, "ecmwf", "eccodes", "develop")
+	if e := c.required["ecmwf/eccodes@develop"]; time.Until(e.expires) > requiredRetryTTL {
+		t.Errorf("failed lookup cached until %v, want at most %v", e.expires, requiredRetryTTL)
+	}
+
+	// Once expired, the lookup is retried and its result kept longer.
+	c.required["ecmwf/eccodes@develop"] = requiredEntry{expires: time.Now().Add(-time.Second)}
+	fail = false
+	if got, _ := c.cachedRequiredContexts(ctx, "ecmwf", "eccodes", "develop"); !slices.Equal(got, []string{"test"}) {
+		t.Errorf("required = %q, want [test]", got)
+	}
+	if e := c.required["ecmwf/eccodes@develop"]; time.Until(e.expires) <= requiredRetryTTL {
+		t.Errorf("successful lookup cached until %v, want %v", e.expires, requiredTTL)
+	}
+	if calls < 2 {
+		t.Errorf("protection read %d times, want a retry", calls)
+	}
+}
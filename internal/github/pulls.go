@@ -3,11 +3,14 @@ package github
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	gh "github.com/google/go-github/v83/github"
 	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func (c *Client) FetchPullRequests(ctx context.Context, org string, repos []config.RepositoryConfig) PRsFetchResult {
@@ -26,16 +29,21 @@ func (c *Client) FetchPullRequests(ctx context.Context, org string, repos []conf
 			},
 		}
 
-		repoFailed := false
+		repoCtx, span := tracing.StartRepoSpan(ctx, "prs", org, repo.Name)
+		extra, err := c.openPRsGraphQL(repoCtx, org, repo.Name)
+		if err != nil {
+			slog.Warn("fetching linked issues and merge queue failed", "category", "prs", "org", org, "repo", repo.Name, "error", err)
+		}
+		var repoErr error
 		for {
-			if ctx.Err() != nil {
+			if repoCtx.Err() != nil {
 				break
 			}
 
-			prs, resp, err := c.gh.PullRequests.List(ctx, org, repo.Name, opts)
+			prs, resp, err := c.gh.PullRequests.List(repoCtx, org, repo.Name, opts)
 			if err != nil {
-				log.Printf("Error fetching PRs for %s/%s: %v", org, repo.Name, err)
-				repoFailed = true
+				slog.Error("fetching pull requests failed", "category", "prs", "org", org, "repo", repo.Name, "error", err)
+				repoErr = err
 				break
 			}
 			if resp != nil {
@@ -56,8 +64,14 @@ func (c *Client) FetchPullRequests(ctx context.Context, org string, repos []conf
 					Draft:        ghPR.GetDraft(),
 					BaseBranch:   ghPR.GetBase().GetRef(),
 					HeadBranch:   ghPR.GetHead().GetRef(),
+					HeadSHA:      ghPR.GetHead().GetSHA(),
 					Comments:     ghPR.GetComments(),
 				}
+				pr.ClosingIssues = addIssueRefs(closingRefs(org, repo.Name, ghPR.GetBody()), extra[pr.Number].ClosingIssues...)
+				pr.MergeQueue = extra[pr.Number].MergeQueue
+				if am := ghPR.GetAutoMerge(); am != nil {
+					pr.AutoMerge = &AutoMerge{EnabledBy: am.GetEnabledBy().GetLogin(), MergeMethod: am.GetMergeMethod()}
+				}
 
 				// Set author association
 				if ghPR.AuthorAssociation != nil {
@@ -76,10 +90,10 @@ func (c *Client) FetchPullRequests(ctx context.Context, org string, repos []conf
 				}
 
 				// Fetch additional details
-				rate, err := c.fetchPRDetails(ctx, org, repo.Name, ghPR.GetNumber(), &pr)
+				rate, err := c.fetchPRDetails(repoCtx, org, repo.Name, ghPR.GetNumber(), &pr)
 				result.Rate = rate
 				if err != nil {
-					log.Printf("Error fetching PR details for %s/%s#%d: %v", org, repo.Name, ghPR.GetNumber(), err)
+					slog.Error("fetching pull request details failed", "category", "prs", "org", org, "repo", repo.Name, "pr", ghPR.GetNumber(), "error", err)
 				}
 
 				result.PullRequests = append(result.PullRequests, pr)
@@ -90,8 +104,9 @@ func (c *Client) FetchPullRequests(ctx context.Context, org string, repos []conf
 			}
 			opts.Page = resp.NextPage
 		}
+		tracing.EndSpan(span, repoErr)
 
-		if repoFailed {
+		if repoErr != nil {
 			result.FailedRepos = append(result.FailedRepos, repo.Name)
 		} else {
 			result.SucceededRepos = append(result.SucceededRepos, repo.Name)
@@ -116,7 +131,13 @@ func (c *Client) FetchPullRequests(ctx context.Context, org string, repos []conf
 	return result
 }
 
-func (c *Client) fetchPRDetails(ctx context.Context, org, repo string, number int, pr *PullRequest) (RateInfo, error) {
+func (c *Client) fetchPRDetails(ctx context.Context, org, repo string, number int, pr *PullRequest) (rate RateInfo, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "fetch PR details", trace.WithAttributes(
+		attribute.String("repo", repo),
+		attribute.Int("pr", number),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	var lastRate RateInfo
 
 	// Fetch reviews with pagination
@@ -193,6 +214,13 @@ func (c *Client) fetchPRDetails(ctx context.Context, org, repo string, number in
 		ListOptions: gh.ListOptions{PerPage: 100},
 	}
 
+	runs, rate, err := c.workflowRunsForSHA(ctx, org, repo, fullPR.GetHead().GetSHA())
+	if err != nil {
+		slog.Warn("fetching workflow runs failed", "category", "prs", "org", org, "repo", repo, "pr", number, "error", err)
+	} else if rate.Limit > 0 {
+		lastRate = rate
+	}
+
 	for {
 		if ctx.Err() != nil {
 			return lastRate, ctx.Err()
@@ -211,12 +239,7 @@ func (c *Client) fetchPRDetails(ctx context.Context, org, repo string, number in
 				continue
 			}
 
-			pr.Checks = append(pr.Checks, Check{
-				Name:       check.GetName(),
-				Status:     check.GetStatus(),
-				Conclusion: check.GetConclusion(),
-				URL:        check.GetHTMLURL(),
-			})
+			pr.Checks = append(pr.Checks, newCheck(check, runs))
 
 			switch ClassifyCheck(check.GetStatus(), check.GetConclusion()) {
 			case "running":
@@ -234,5 +257,51 @@ func (c *Client) fetchPRDetails(ctx context.Context, org, repo string, number in
 		checkOpts.Page = resp.NextPage
 	}
 
+	statuses, rate, err := c.commitStatuses(ctx, org, repo, fullPR.GetHead().GetSHA())
+	if err != nil {
+		slog.Warn("fetching commit statuses failed", "category", "prs", "org", org, "repo", repo, "pr", number, "error", err)
+	} else if rate.Limit > 0 {
+		lastRate = rate
+	}
+	for _, check := range statuses {
+		pr.Checks = append(pr.Checks, check)
+		switch ClassifyCheck(check.Status, check.Conclusion) {
+		case "running":
+			pr.ChecksRunning++
+		case "success":
+			pr.ChecksSuccess++
+		default:
+			pr.ChecksFailure++
+		}
+	}
+
 	return lastRate, nil
 }
+
+// FetchMergedPullRequests looks up pull requests that are no longer open,
+// one request each, and returns those that were merged with State and
+// MergedAt set. Pull requests that cannot be read are logged and left out.
+func (c *Client) FetchMergedPullRequests(ctx context.Context, org string, prs []PullRequest) ([]PullRequest, RateInfo) {
+	var merged []PullRequest
+	var rate RateInfo
+	for _, pr := range prs {
+		if ctx.Err() != nil {
+			break
+		}
+		full, resp, err := c.gh.PullRequests.Get(ctx, org, pr.Repository, pr.Number)
+		if resp != nil {
+			rate = rateFromResponse(resp)
+		}
+		if err != nil {
+			slog.Warn("fetching closed pull request failed", "category", "prs", "org", org, "repo", pr.Repository, "pr", pr.Number, "error", err)
+			continue
+		}
+		if !full.GetMerged() {
+			continue
+		}
+		pr.State = "merged"
+		pr.MergedAt = full.GetMergedAt().Time
+		merged = append(merged, pr)
+	}
+	return merged, rate
+}
@@ -0,0 +1,37 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchMergedPullRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"number": 1, "state": "closed", "merged": true, "merged_at": "2026-10-18T08:00:00Z"}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/pulls/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"number": 2, "state": "closed", "merged": false}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/pulls/3", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	merged, _ := c.FetchMergedPullRequests(context.Background(), "ecmwf", []PullRequest{
+		{Repository: "eccodes", Number: 1, Title: "Merged"},
+		{Repository: "eccodes", Number: 2, Title: "Closed"},
+		{Repository: "eccodes", Number: 3, Title: "Unreadable"},
+	})
+	if len(merged) != 1 {
+		t.Fatalf("got %d merged pull requests, want 1", len(merged))
+	}
+	if pr := merged[0]; pr.Number != 1 || pr.Title != "Merged" || pr.State != "merged" || !pr.MergedAt.Equal(time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("merged = %+v", pr)
+	}
+}
@@ -0,0 +1,272 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	gh "github.com/google/go-github/v83/github"
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing"
+)
+
+// maxRecentReleases is how many of a repository's newest releases are
+// kept for the releases feed.
+const maxRecentReleases = 10
+
+// ReleaseInfo is what the release readiness page needs to know about a
+// repository beyond its issues, pull requests and checks.
+type ReleaseInfo struct {
+	Repository string
+	Branch     string // release branch
+
+	// LatestRelease is nil when the repository has no published release.
+	// LatestTag is the first tag GitHub lists, which may be newer than the
+	// release's tag.
+	LatestRelease *Release
+	LatestTag     string
+
+	// SinceTag is the tag commits are counted from: the latest release's,
+	// else LatestTag. CommitsSinceTag is how far Branch is ahead of it.
+	SinceTag        string
+	CommitsSinceTag int
+	CompareURL      string
+
+	// Releases are the newest published releases, newest first, up to
+	// maxRecentReleases. Drafts are left out.
+	Releases []Release
+
+	// Milestone is the open milestone due soonest (the oldest open one if
+	// none has a due date); nil when there is none.
+	Milestone *Milestone
+
+	// FetchFailed is set when any request failed; the store then keeps
+	// the earlier result.
+	FetchFailed bool
+}
+
+// Release is a published GitHub release. It is shared between copies of a
+// ReleaseInfo and never modified after the fetch.
+type Release struct {
+	Repository  string
+	Tag         string
+	Name        string
+	URL         string
+	Author      string
+	PublishedAt time.Time
+	Prerelease  bool
+	Assets      []Asset
+}
+
+// Asset is a file attached to a release, such as a source tarball.
+type Asset struct {
+	Name      string
+	URL       string
+	Downloads int
+}
+
+// Downloads returns the download count summed over the release's assets.
+func (r Release) Downloads() int {
+	n := 0
+	for _, a := range r.Assets {
+		n += a.Downloads
+	}
+	return n
+}
+
+// Milestone is a repository milestone. Like Release, it is shared and
+// never modified after the fetch.
+type Milestone struct {
+	Repository   string
+	Number       int
+	Title        string
+	URL          string
+	DueOn        time.Time // zero when no due date is set
+	OpenIssues   int       // including pull requests
+	ClosedIssues int
+}
+
+// Overdue reports whether the milestone was due before now.
+func (m Milestone) Overdue(now time.Time) bool {
+	return !m.DueOn.IsZero() && m.DueOn.Before(now)
+}
+
+// Progress returns the share of closed issues as a whole percentage.
+func (m Milestone) Progress() int {
+	total := m.OpenIssues + m.ClosedIssues
+	if total == 0 {
+		return 0
+	}
+	return m.ClosedIssues * 100 / total
+}
+
+// FetchReleaseInfo fetches the latest release, tag and milestone of each
+// repository and counts the commits made on its release branch since.
+func (c *Client) FetchReleaseInfo(ctx context.Context, org string, repos []config.RepositoryConfig) ReleasesFetchResult {
+	var result ReleasesFetchResult
+	successCount := 0
+
+	for _, repo := range repos {
+		if ctx.Err() != nil {
+			break
+		}
+
+		repoCtx, span := tracing.StartRepoSpan(ctx, "releases", org, repo.Name)
+		info, rate, err := c.releaseInfo(repoCtx, org, repo)
+		if rate.Limit > 0 {
+			result.Rate = rate
+		}
+		tracing.EndSpan(span, err)
+		result.Releases = append(result.Releases, info)
+
+		if err != nil {
+			slog.Error("fetching release info failed", "category", "releases", "org", org, "repo", repo.Name, "error", err)
+			result.FailedRepos = append(result.FailedRepos, repo.Name)
+		} else {
+			result.SucceededRepos = append(result.SucceededRepos, repo.Name)
+			successCount++
+		}
+	}
+
+	attempted := len(result.SucceededRepos) + len(result.FailedRepos)
+	for i := attempted; i < len(repos); i++ {
+		result.FailedRepos = append(result.FailedRepos, repos[i].Name)
+	}
+
+	if successCount == 0 && len(repos) > 0 {
+		if ctx.Err() != nil {
+			result.Err = ctx.Err()
+		} else {
+			result.Err = fmt.Errorf("all %d repos failed", len(repos))
+		}
+	}
+
+	return result
+}
+
+// releaseInfo makes up to five requests: latest release, recent releases,
+// tags, the comparison of the tag with the release branch, and open
+// milestones.
+func (c *Client) releaseInfo(ctx context.Context, org string, repo config.RepositoryConfig) (ReleaseInfo, RateInfo, error) {
+	info := ReleaseInfo{Repository: repo.Name, Branch: repo.ReleaseBranchName()}
+	var rate RateInfo
+	track := func(resp *gh.Response) {
+		if resp != nil {
+			rate = rateFromResponse(resp)
+		}
+	}
+	fail := func(err error) (ReleaseInfo, RateInfo, error) {
+		return ReleaseInfo{Repository: repo.Name, Branch: info.Branch, FetchFailed: true}, rate, err
+	}
+
+	release, resp, err := c.gh.Repositories.GetLatestRelease(ctx, org, repo.Name)
+	track(resp)
+	switch {
+	case isNotFound(err):
+		// No published release yet.
+	case err != nil:
+		return fail(fmt.Errorf("latest release: %w", err))
+	default:
+		latest := newRelease(repo.Name, release)
+		info.LatestRelease = &latest
+		info.SinceTag = latest.Tag
+	}
+
+	releases, resp, err := c.gh.Repositories.ListReleases(ctx, org, repo.Name, &gh.ListOptions{PerPage: maxRecentReleases})
+	track(resp)
+	if err != nil {
+		return fail(fmt.Errorf("releases: %w", err))
+	}
+	for _, r := range releases {
+		if !r.GetDraft() {
+			info.Releases = append(info.Releases, newRelease(repo.Name, r))
+		}
+	}
+
+	tags, resp, err := c.gh.Repositories.ListTags(ctx, org, repo.Name, &gh.ListOptions{PerPage: 1})
+	track(resp)
+	if err != nil {
+		return fail(fmt.Errorf("tags: %w", err))
+	}
+	if len(tags) > 0 {
+		info.LatestTag = tags[0].GetName()
+		if info.SinceTag == "" {
+			info.SinceTag = info.LatestTag
+		}
+	}
+
+	if info.SinceTag != "" {
+		cmp, resp, err := c.gh.Repositories.CompareCommits(ctx, org, repo.Name, info.SinceTag, info.Branch, &gh.ListOptions{PerPage: 1})
+		track(resp)
+		if err != nil {
+			return fail(fmt.Errorf("commits since %s: %w", info.SinceTag, err))
+		}
+		info.CommitsSinceTag = cmp.GetAheadBy()
+		info.CompareURL = cmp.GetHTMLURL()
+	}
+
+	milestones, resp, err := c.gh.Issues.ListMilestones(ctx, org, repo.Name, &gh.MilestoneListOptions{
+		State:       "open",
+		ListOptions: gh.ListOptions{PerPage: 100},
+	})
+	track(resp)
+	if err != nil {
+		return fail(fmt.Errorf("milestones: %w", err))
+	}
+	if m := currentMilestone(milestones); m != nil {
+		milestone := newMilestone(repo.Name, m)
+		info.Milestone = &milestone
+	}
+
+	return info, rate, nil
+}
+
+func newRelease(repo string, r *gh.RepositoryRelease) Release {
+	release := Release{
+		Repository:  repo,
+		Tag:         r.GetTagName(),
+		Name:        r.GetName(),
+		URL:         r.GetHTMLURL(),
+		Author:      r.GetAuthor().GetLogin(),
+		PublishedAt: r.GetPublishedAt().Time,
+		Prerelease:  r.GetPrerelease(),
+	}
+	for _, a := range r.Assets {
+		release.Assets = append(release.Assets, Asset{
+			Name:      a.GetName(),
+			URL:       a.GetBrowserDownloadURL(),
+			Downloads: a.GetDownloadCount(),
+		})
+	}
+	return release
+}
+
+func newMilestone(repo string, m *gh.Milestone) Milestone {
+	return Milestone{
+		Repository:   repo,
+		Number:       m.GetNumber(),
+		Title:        m.GetTitle(),
+		URL:          m.GetHTMLURL(),
+		DueOn:        m.GetDueOn().Time,
+		OpenIssues:   m.GetOpenIssues(),
+		ClosedIssues: m.GetClosedIssues(),
+	}
+}
+
+// currentMilestone picks the open milestone due soonest, or the oldest
+// open one when none has a due date.
+func currentMilestone(milestones []*gh.Milestone) *gh.Milestone {
+	var current *gh.Milestone
+	for _, m := range milestones {
+		switch {
+		case current == nil:
+			current = m
+		case m.DueOn != nil && (current.DueOn == nil || m.GetDueOn().Before(current.GetDueOn().Time)):
+			current = m
+		case m.DueOn == nil && current.DueOn == nil && m.GetNumber() < current.GetNumber():
+			current = m
+		}
+	}
+	return current
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestFetchReleaseInfo(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "2.39.0", "name": "ecCodes 2.39.0", "html_url": "https://github.com/ecmwf/eccodes/releases/tag/2.39.0",
+			"published_at": "2026-02-10T12:00:00Z", "prerelease": false}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "10" {
+			t.Errorf("per_page = %q, want 10", r.URL.Query().Get("per_page"))
+		}
+		w.Write([]byte(`[
+			{"tag_name": "2.40.0", "draft": true},
+			{"tag_name": "2.40.0-rc1", "name": "ecCodes 2.40.0 RC1", "prerelease": true, "author": {"login": "alice"},
+				"published_at": "2026-03-01T09:00:00Z", "assets": [{"name": "eccodes-2.40.0-rc1-Source.tar.gz", "download_count": 7}]},
+			{"tag_name": "2.39.0", "name": "ecCodes 2.39.0", "author": {"login": "bob"}, "published_at": "2026-02-10T12:00:00Z",
+				"assets": [{"name": "eccodes-2.39.0-Source.tar.gz", "download_count": 120, "browser_download_url": "https://dl/2.39.0.tar.gz"},
+					{"name": "eccodes-2.39.0-Source.zip", "download_count": 30}]}
+		]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name": "2.40.0-rc1", "commit": {"sha": "tag123"}}]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/compare/2.39.0...master", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ahead_by": 12, "html_url": "https://github.com/ecmwf/eccodes/compare/2.39.0...master"}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/milestones", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "open" {
+			t.Errorf("state = %q, want open", r.URL.Query().Get("state"))
+		}
+		w.Write([]byte(`[
+			{"number": 5, "title": "Someday"},
+			{"number": 4, "title": "2.41.0", "due_on": "2026-09-01T00:00:00Z"},
+			{"number": 3, "title": "2.40.0", "due_on": "2026-06-01T00:00:00Z", "open_issues": 1, "closed_issues": 3}
+		]`))
+	})
+	// fdb has neither releases, tags nor milestones.
+	mux.HandleFunc("/repos/ecmwf/fdb/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/ecmwf/fdb/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/fdb/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/fdb/milestones", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/repos/ecmwf/atlas/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	repos := []config.RepositoryConfig{
+		{Name: "eccodes", Branches: []string{"develop", "master"}},
+		{Name: "fdb", Branches: []string{"develop"}},
+		{Name: "atlas", Branches: []string{"main"}},
+	}
+	result := c.FetchReleaseInfo(context.Background(), "ecmwf", repos)
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.SucceededRepos) != 2 || len(result.FailedRepos) != 1 || result.FailedRepos[0] != "atlas" {
+		t.Errorf("succeeded %v, failed %v; want atlas to fail", result.SucceededRepos, result.FailedRepos)
+	}
+	if len(result.Releases) != 3 {
+		t.Fatalf("got %d release infos, want 3", len(result.Releases))
+	}
+
+	got := result.Releases[0]
+	if got.Branch != "master" || got.LatestRelease == nil || got.LatestRelease.Tag != "2.39.0" || got.LatestTag != "2.40.0-rc1" {
+		t.Errorf("eccodes = %+v", got)
+	}
+	if want := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC); !got.LatestRelease.PublishedAt.Equal(want) {
+		t.Errorf("PublishedAt = %v, want %v", got.LatestRelease.PublishedAt, want)
+	}
+	if got.SinceTag != "2.39.0" || got.CommitsSinceTag != 12 {
+		t.Errorf("commits since = %d since %q, want 12 since the release tag", got.CommitsSinceTag, got.SinceTag)
+	}
+	if len(got.Releases) != 2 {
+		t.Fatalf("got %d recent releases, want 2 without the draft", len(got.Releases))
+	}
+	if rc := got.Releases[0]; rc.Tag != "2.40.0-rc1" || !rc.Prerelease || rc.Author != "alice" || rc.Repository != "eccodes" {
+		t.Errorf("newest release = %+v", rc)
+	}
+	if rel := got.Releases[1]; rel.Downloads() != 150 || rel.Assets[0].URL != "https://dl/2.39.0.tar.gz" {
+		t.Errorf("release 2.39.0 = %+v, want 150 downloads", rel)
+	}
+	if m := got.Milestone; m == nil || m.Number != 3 || m.Progress() != 75 {
+		t.Errorf("milestone = %+v, want 2.40.0 due soonest", m)
+	}
+
+	if fdb := result.Releases[1]; fdb.FetchFailed || fdb.LatestRelease != nil || fdb.SinceTag != "" || fdb.Milestone != nil {
+		t.Errorf("fdb = %+v, want empty but not failed", fdb)
+	}
+	if atlas := result.Releases[2]; !atlas.FetchFailed || atlas.Branch != "main" {
+		t.Errorf("atlas = %+v, want a failed placeholder", atlas)
+	}
+}
@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	gh "github.com/google/go-github/v83/github"
+)
+
+// Scheduled workflow runs fetched per workflow: the streak of failures is
+// counted over all of them, the panel shows the latest few.
+const (
+	scheduledRunsFetched = 30
+	scheduledRunsShown   = 10
+)
+
+// ScheduledWorkflow is the recent history of a workflow that runs
+// independently of branch pushes, such as a nightly build.
+type ScheduledWorkflow struct {
+	Repository string
+	File       string        // workflow file name, as configured
+	Name       string        // workflow name, from its latest run
+	URL        string        // the workflow's runs on GitHub
+	Runs       []WorkflowRun // latest first, at most scheduledRunsShown
+
+	// FailureStreak counts the consecutive failed runs up to the latest
+	// completed one; StreakCapped means every fetched run failed and older
+	// runs may have too.
+	FailureStreak int
+	StreakCapped  bool
+
+	// FetchFailed is set when the runs could not be fetched; the entry
+	// then carries no runs and the store keeps any earlier history.
+	FetchFailed bool
+}
+
+// WorkflowRun is one run of a scheduled workflow.
+type WorkflowRun struct {
+	ID         int64
+	Status     string
+	Conclusion string
+	Event      string
+	Branch     string
+	HeadSHA    string
+	URL        string
+	RunAttempt int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Latest returns the most recent run, or a zero run if there is none.
+func (w ScheduledWorkflow) Latest() WorkflowRun {
+	if len(w.Runs) == 0 {
+		return WorkflowRun{}
+	}
+	return w.Runs[0]
+}
+
+// Result classifies the run like a check: "running", "success" or
+// "failure"; cancelled and skipped runs are "neutral".
+func (r WorkflowRun) Result() string {
+	if r.Conclusion == "cancelled" || r.Conclusion == "skipped" {
+		return "neutral"
+	}
+	return ClassifyCheck(r.Status, r.Conclusion)
+}
+
+// scheduledWorkflow fetches the latest scheduled runs of a workflow file on
+// any branch and commit. Runs started by hand or by pushes are left out, so
+// they neither break nor extend the streak.
+func (c *Client) scheduledWorkflow(ctx context.Context, org, repo, file string) (ScheduledWorkflow, RateInfo, error) {
+	wf := ScheduledWorkflow{
+		Repository: repo,
+		File:       file,
+		Name:       file,
+		URL:        fmt.Sprintf("https://github.com/%s/%s/actions/workflows/%s", org, repo, file),
+	}
+	var rate RateInfo
+	page, resp, err := c.gh.Actions.ListWorkflowRunsByFileName(ctx, org, repo, file, &gh.ListWorkflowRunsOptions{
+		Event:       "schedule",
+		ListOptions: gh.ListOptions{PerPage: scheduledRunsFetched},
+	})
+	if resp != nil {
+		rate = rateFromResponse(resp)
+	}
+	if err != nil {
+		wf.FetchFailed = true
+		return wf, rate, err
+	}
+
+	var runs []WorkflowRun
+	for _, run := range page.WorkflowRuns {
+		runs = append(runs, WorkflowRun{
+			ID:         run.GetID(),
+			Status:     run.GetStatus(),
+			Conclusion: run.GetConclusion(),
+			Event:      run.GetEvent(),
+			Branch:     run.GetHeadBranch(),
+			HeadSHA:    run.GetHeadSHA(),
+			URL:        run.GetHTMLURL(),
+			RunAttempt: run.GetRunAttempt(),
+			CreatedAt:  run.GetCreatedAt().Time,
+			UpdatedAt:  run.GetUpdatedAt().Time,
+		})
+	}
+	if len(page.WorkflowRuns) > 0 && page.WorkflowRuns[0].GetName() != "" {
+		wf.Name = page.WorkflowRuns[0].GetName()
+	}
+	var sawSuccess bool
+	wf.FailureStreak, sawSuccess = failureStreak(runs)
+	wf.StreakCapped = !sawSuccess && wf.FailureStreak > 0 && len(runs) == scheduledRunsFetched
+	if len(runs) > scheduledRunsShown {
+		runs = runs[:scheduledRunsShown]
+	}
+	wf.Runs = runs
+	return wf, rate, nil
+}
+
+// failureStreak counts the failed runs (latest first) since the last
+// success. Runs still in progress, cancelled and skipped runs are ignored.
+func failureStreak(runs []WorkflowRun) (streak int, sawSuccess bool) {
+	for _, run := range runs {
+		switch run.Result() {
+		case "running", "neutral":
+			continue
+		case "success":
+			return streak, true
+		}
+		streak++
+	}
+	return streak, false
+}
+
+// fetchScheduled fetches every scheduled workflow of a repository. A
+// workflow that cannot be fetched is returned with FetchFailed set.
+func (c *Client) fetchScheduled(ctx context.Context, org, repo string, files []string, result *ChecksFetchResult) {
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return
+		}
+		wf, rate, err := c.scheduledWorkflow(ctx, org, repo, file)
+		if err != nil {
+			slog.Warn("fetching scheduled workflow runs failed", "category", "checks", "org", org, "repo", repo, "workflow", file, "error", err)
+		}
+		if rate.Limit > 0 {
+			result.Rate = rate
+		}
+		result.Scheduled = append(result.Scheduled, wf)
+	}
+}
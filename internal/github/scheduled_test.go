@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+func TestFetchBranchChecks_Scheduled(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/ecmwf/eccodes/commits", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"sha": "abc123"}]`))
+	})
+	for _, path := range []string{"/repos/ecmwf/eccodes/commits/abc123/check-runs", "/repos/ecmwf/eccodes/actions/runs", "/repos/ecmwf/eccodes/commits/abc123/status"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{}`)) })
+	}
+	mux.HandleFunc("/repos/ecmwf/eccodes/actions/workflows/nightly.yml/runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("head_sha") != "" {
+			t.Error("scheduled runs must not be filtered by commit")
+		}
+		if event := r.URL.Query().Get("event"); event != "schedule" {
+			t.Errorf("runs filtered by event %q, want schedule", event)
+		}
+		w.Write([]byte(`{"total_count": 5, "workflow_runs": [
+			{"id": 5, "name": "Nightly regression", "status": "in_progress", "event": "schedule", "created_at": "2026-05-05T02:00:00Z"},
+			{"id": 4, "name": "Nightly regression", "status": "completed", "conclusion": "failure", "event": "schedule", "created_at": "2026-05-04T02:00:00Z"},
+			{"id": 3, "name": "Nightly regression", "status": "completed", "conclusion": "cancelled", "event": "schedule", "created_at": "2026-05-03T02:00:00Z"},
+			{"id": 2, "name": "Nightly regression", "status": "completed", "conclusion": "timed_out", "event": "schedule", "created_at": "2026-05-02T02:00:00Z"},
+			{"id": 1, "name": "Nightly regression", "status": "completed", "conclusion": "success", "event": "schedule", "created_at": "2026-05-01T02:00:00Z"}
+		]}`))
+	})
+	mux.HandleFunc("/repos/ecmwf/eccodes/actions/workflows/missing.yml/runs", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestClient(t, srv)
+	repos := []config.RepositoryConfig{{Name: "eccodes", Branches: []string{"develop"}, Scheduled: []string{"nightly.yml", "missing.yml"}}}
+	result := c.FetchBranchChecks(context.Background(), "ecmwf", repos, config.FailuresConfig{})
+	if result.Err != nil || len(result.Scheduled) != 2 {
+		t.Fatalf("FetchBranchChecks() = %+v, want two scheduled workflows", result)
+	}
+
+	nightly, missing := result.Scheduled[0], result.Scheduled[1]
+	if nightly.Name != "Nightly regression" || nightly.URL != "https://github.com/ecmwf/eccodes/actions/workflows/nightly.yml" || len(nightly.Runs) != 5 {
+		t.Errorf("nightly = %+v", nightly)
+	}
+	if nightly.FailureStreak != 2 || nightly.StreakCapped {
+		t.Errorf("streak = %d capped %v, want 2 failures since the last success", nightly.FailureStreak, nightly.StreakCapped)
+	}
+	if latest := nightly.Latest(); latest.ID != 5 || latest.Result() != "running" {
+		t.Errorf("Latest() = %+v, want the running run", latest)
+	}
+	if !missing.FetchFailed || missing.Name != "missing.yml" || len(missing.Runs) != 0 {
+		t.Errorf("missing = %+v, want a failed fetch", missing)
+	}
+}
+
+func TestFailureStreak(t *testing.T) {
+	run := func(conclusion string) WorkflowRun { return WorkflowRun{Status: "completed", Conclusion: conclusion} }
+	tests := []struct {
+		runs       []WorkflowRun
+		streak     int
+		sawSuccess bool
+	}{
+		{nil, 0, false},
+		{[]WorkflowRun{run("success"), run("failure")}, 0, true},
+		{[]WorkflowRun{run("failure"), run("skipped"), run("failure"), run("success")}, 2, true},
+		{[]WorkflowRun{run("failure"), run("failure")}, 2, false},
+	}
+	for i, tt := range tests {
+		streak, sawSuccess := failureStreak(tt.runs)
+		if streak != tt.streak || sawSuccess != tt.sawSuccess {
+			t.Errorf("case %d: failureStreak() = %d, %v, want %d, %v", i, streak, sawSuccess, tt.streak, tt.sawSuccess)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+
+	gh "github.com/google/go-github/v83/github"
+)
+
+// Check sources: check runs (GitHub Actions and Checks API apps) and legacy
+// commit statuses (Jenkins, ReadTheDocs, ...).
+const (
+	SourceCheckRun = "check_run"
+	SourceStatus   = "status"
+)
+
+// statusWorkflow groups commit statuses, which belong to no workflow run.
+const statusWorkflow = "Commit statuses"
+
+// commitStatuses returns the latest commit status per context for sha,
+// mapped to checks: pending statuses get Status "pending", the others
+// Status "completed" with the state (success, failure, error) as
+// Conclusion, so ClassifyCheck and the templates treat them like check runs.
+func (c *Client) commitStatuses(ctx context.Context, org, repo, sha string) ([]Check, RateInfo, error) {
+	var rate RateInfo
+	var checks []Check
+	opts := &gh.ListOptions{PerPage: 100}
+	for {
+		combined, resp, err := c.gh.Repositories.GetCombinedStatus(ctx, org, repo, sha, opts)
+		if err != nil {
+			return nil, rate, err
+		}
+		if resp != nil {
+			rate = rateFromResponse(resp)
+		}
+		for _, s := range combined.Statuses {
+			checks = append(checks, newStatusCheck(s))
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return checks, rate, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func newStatusCheck(s *gh.RepoStatus) Check {
+	check := Check{
+		Name:     s.GetContext(),
+		Status:   "completed",
+		URL:      s.GetTargetURL(),
+		Source:   SourceStatus,
+		Workflow: statusWorkflow,
+	}
+	if s.GetState() == "pending" {
+		check.Status = "pending"
+	} else {
+		check.Conclusion = s.GetState()
+	}
+	return check
+}
@@ -17,6 +17,8 @@ type Issue struct {
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	Labels            []Label
+	Milestone         string // title; empty when the issue has none
+	MilestoneNumber   int
 }
 
 type Label struct {
@@ -39,10 +41,12 @@ type PullRequest struct {
 	Labels            []Label
 
 	// PR specific fields
-	State          string // open, closed, merged
+	State          string    // open, closed, merged
+	MergedAt       time.Time // zero unless merged
 	Draft          bool
 	BaseBranch     string
 	HeadBranch     string
+	HeadSHA        string
 	ReviewStatus   string // approved, changes_requested, pending
 	Reviewers      []Reviewer
 	MergeableState string // clean, blocked, unstable, dirty
@@ -50,6 +54,16 @@ type PullRequest struct {
 	ReviewComments int
 	Checks         []Check
 
+	// ClosingIssues are the issues the PR closes, from its description and
+	// the links set on GitHub.
+	ClosingIssues []IssueRef
+
+	// AutoMerge and MergeQueue are nil unless auto-merge is enabled or the
+	// PR is in its base branch's merge queue. Both are shared between
+	// copies and never modified after the fetch.
+	AutoMerge  *AutoMerge
+	MergeQueue *MergeQueueEntry
+
 	// Check counts
 	ChecksSuccess int
 	ChecksFailure int
@@ -63,10 +77,30 @@ type Reviewer struct {
 }
 
 type Check struct {
+	ID         int64 // check run ID; 0 for commit statuses
 	Name       string
 	Status     string // completed, in_progress, queued
 	Conclusion string // success, failure, neutral, cancelled, skipped, timed_out
 	URL        string
+	Source     string // SourceCheckRun or SourceStatus
+	Required   bool   // a required status check on the branch
+	Flaky      bool   // a failing check known to be flaky; set by the handlers, not fetched
+
+	// Zero for checks that have not started or finished, and for commit
+	// statuses, which carry no timing.
+	StartedAt   time.Time
+	CompletedAt time.Time
+
+	// Workflow run the check belongs to. RunID is 0 for checks posted by
+	// other apps, whose Workflow is the app name.
+	Workflow   string
+	RunID      int64
+	RunAttempt int
+	Event      string // push, pull_request, schedule, ...
+
+	// Failure explains a failed check run on a tracked branch; nil for
+	// other checks and for pull request checks.
+	Failure *FailureDetails
 }
 
 type BranchCheck struct {
@@ -76,6 +110,11 @@ type BranchCheck struct {
 	CommitURL  string
 	UpdatedAt  time.Time
 	Checks     []Check
+
+	// RequiredChecks are the contexts branch protection or rulesets require
+	// (empty if none or unreadable); MissingRequired those no check reported.
+	RequiredChecks  []string
+	MissingRequired []string
 }
 
 // Fetch result types — bundle data, failed repos, rate info, and error.
@@ -98,6 +137,40 @@ type PRsFetchResult struct {
 
 type ChecksFetchResult struct {
 	BranchChecks   []BranchCheck
+	Scheduled      []ScheduledWorkflow
+	SucceededRepos []string
+	FailedRepos    []string
+	Rate           RateInfo
+	Err            error
+}
+
+type PlanningFetchResult struct {
+	Milestones     []Milestone
+	Boards         []ProjectBoard // one per configured project, in config order
+	SucceededRepos []string
+	FailedRepos    []string
+	Rate           RateInfo
+	Err            error
+}
+
+type DeploymentsFetchResult struct {
+	Environments   []Environment
+	SucceededRepos []string
+	FailedRepos    []string
+	Rate           RateInfo
+	Err            error
+}
+
+type ReleasesFetchResult struct {
+	Releases       []ReleaseInfo
+	SucceededRepos []string
+	FailedRepos    []string
+	Rate           RateInfo
+	Err            error
+}
+
+type ComparisonsFetchResult struct {
+	Comparisons    []BranchComparison
 	SucceededRepos []string
 	FailedRepos    []string
 	Rate           RateInfo
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	gh "github.com/google/go-github/v83/github"
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+// VerifyRepositories checks that every configured repository and branch
+// exists and is visible to the client's token. It returns one message per
+// missing repository or branch; err is set only for failures that are not a
+// plain "not found" (network errors, bad credentials, rate limiting).
+func (c *Client) VerifyRepositories(ctx context.Context, org string, repos []config.RepositoryConfig) ([]string, error) {
+	var problems []string
+	for _, repo := range repos {
+		if _, _, err := c.gh.Repositories.Get(ctx, org, repo.Name); err != nil {
+			if isNotFound(err) {
+				problems = append(problems, fmt.Sprintf("repository %s/%s not found or not accessible", org, repo.Name))
+				continue
+			}
+			return problems, fmt.Errorf("checking %s/%s: %w", org, repo.Name, err)
+		}
+
+		for _, branch := range verifiedBranches(repo) {
+			if _, _, err := c.gh.Repositories.GetBranch(ctx, org, repo.Name, branch, 0); err != nil {
+				if isNotFound(err) {
+					problems = append(problems, fmt.Sprintf("branch %q not found in %s/%s", branch, org, repo.Name))
+					continue
+				}
+				return problems, fmt.Errorf("checking %s/%s branch %s: %w", org, repo.Name, branch, err)
+			}
+		}
+	}
+	return problems, nil
+}
+
+// verifiedBranches returns the tracked branches, those of branch
+// comparisons and an explicit release branch, each once.
+func verifiedBranches(repo config.RepositoryConfig) []string {
+	seen := make(map[string]bool)
+	var branches []string
+	add := func(b string) {
+		if !seen[b] {
+			seen[b] = true
+			branches = append(branches, b)
+		}
+	}
+	for _, b := range repo.Branches {
+		add(b)
+	}
+	for _, pair := range repo.Compare {
+		add(pair.Base)
+		add(pair.Head)
+	}
+	if repo.ReleaseBranch != "" {
+		add(repo.ReleaseBranch)
+	}
+	return branches
+}
+
+func isNotFound(err error) bool {
+	var errResp *gh.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
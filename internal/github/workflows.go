@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+
+	gh "github.com/google/go-github/v83/github"
+)
+
+// workflowRun is the part of an Actions workflow run a check run inherits.
+type workflowRun struct {
+	name    string
+	id      int64
+	attempt int
+	event   string
+}
+
+// workflowRunsForSHA returns the workflow runs for a commit, keyed by check
+// suite ID, which is how check runs refer to them.
+func (c *Client) workflowRunsForSHA(ctx context.Context, org, repo, sha string) (map[int64]workflowRun, RateInfo, error) {
+	var rate RateInfo
+	runs := make(map[int64]workflowRun)
+	opts := &gh.ListWorkflowRunsOptions{
+		HeadSHA:     sha,
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, org, repo, opts)
+		if err != nil {
+			return nil, rate, err
+		}
+		if resp != nil {
+			rate = rateFromResponse(resp)
+		}
+		for _, run := range page.WorkflowRuns {
+			runs[run.GetCheckSuiteID()] = workflowRun{
+				name:    run.GetName(),
+				id:      run.GetID(),
+				attempt: run.GetRunAttempt(),
+				event:   run.GetEvent(),
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return runs, rate, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// newCheck converts a check run, attaching its workflow run if it has one.
+// Checks posted by other apps (external CI) are grouped under the app name.
+func newCheck(run *gh.CheckRun, runs map[int64]workflowRun) Check {
+	check := Check{
+		ID:         run.GetID(),
+		Name:       run.GetName(),
+		Status:     run.GetStatus(),
+		Conclusion: run.GetConclusion(),
+		URL:        run.GetHTMLURL(),
+		Source:     SourceCheckRun,
+
+		StartedAt:   run.GetStartedAt().Time,
+		CompletedAt: run.GetCompletedAt().Time,
+	}
+	if wr, ok := runs[run.GetCheckSuite().GetID()]; ok {
+		check.Workflow = wr.name
+		check.RunID = wr.id
+		check.RunAttempt = wr.attempt
+		check.Event = wr.event
+	} else {
+		check.Workflow = run.GetApp().GetName()
+	}
+	return check
+}
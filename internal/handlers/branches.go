@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// sortByDivergence orders comparisons with the most diverged first; ties
+// keep config order, then base and head names.
+func sortByDivergence(comparisons []github.BranchComparison, repoNames []string) {
+	repoIndex := make(map[string]int, len(repoNames))
+	for i, name := range repoNames {
+		repoIndex[name] = i
+	}
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		a, b := comparisons[i], comparisons[j]
+		if a.Divergence() != b.Divergence() {
+			return a.Divergence() > b.Divergence()
+		}
+		if a.Repository != b.Repository {
+			return repoIndex[a.Repository] < repoIndex[b.Repository]
+		}
+		if a.Base != b.Base {
+			return a.Base < b.Base
+		}
+		return a.Head < b.Head
+	})
+}
+
+// BranchDivergence lists how far the configured branch pairs have drifted
+// apart, flagging pairs where the base has commits the head lacks.
+func (h *Handler) BranchDivergence(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
+	comparisons, lastUpdate := h.storage.GetBranchComparisons()
+	comparisons = keepVisible(comparisons, func(c github.BranchComparison) string { return c.Repository }, allowed)
+
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	if repo != "" {
+		comparisons = keepVisible(comparisons, func(c github.BranchComparison) string { return c.Repository }, func(name string) bool { return name == repo })
+	}
+	sortByDivergence(comparisons, settings.RepoNames)
+	logging.FromContext(r.Context()).Debug("serving branch divergence", "comparisons", len(comparisons))
+
+	missing := 0
+	for _, c := range comparisons {
+		if c.BehindBy > 0 {
+			missing++
+		}
+	}
+
+	staleMap, staleList := h.computeStaleness(storage.CategoryBranches, settings.FetchIntervals.Branches, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Comparisons   []github.BranchComparison
+		MissingCount  int // pairs whose head lacks commits from the base
+		LastUpdate    time.Time
+		Repo          string
+		RepoNames     []string
+		StaleRepos    map[string]bool
+		StaleRepoList []string
+	}{
+		PageID:        "builds",
+		Organization:  settings.Organization,
+		Version:       h.version,
+		Comparisons:   comparisons,
+		MissingCount:  missing,
+		LastUpdate:    lastUpdate,
+		Repo:          repo,
+		RepoNames:     settings.RepoNames,
+		StaleRepos:    staleMap,
+		StaleRepoList: staleList,
+	}
+
+	renderTemplate(w, r, h.branchesTemplate, "base", data)
+}
@@ -1,19 +1,24 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 	"sort"
 	"time"
 
 	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
 	"github.com/ozaq/ecmwf-dash/internal/storage"
 )
 
 // RepoBranches carries per-repo branch config without importing the config package.
 type RepoBranches struct {
-	Name     string
-	Branches []string
+	Name      string
+	Branches  []string
+	DependsOn []string // upstream repositories, see config.RepositoryConfig
+
+	// ReleaseBranch is the branch releases are tagged from, with the
+	// config default applied; see config.RepositoryConfig.ReleaseBranchName.
+	ReleaseBranch string
 }
 
 type RepositoryStatus struct {
@@ -22,10 +27,11 @@ type RepositoryStatus struct {
 	Stale    bool
 }
 
-// HasDetails reports whether any branch has failures or running checks.
+// HasDetails reports whether any branch has failures, running checks or
+// missing required checks.
 func (rs *RepositoryStatus) HasDetails() bool {
 	for i := range rs.Branches {
-		if rs.Branches[i].FailureCount > 0 || rs.Branches[i].RunningCount > 0 {
+		if rs.Branches[i].HasDetails() {
 			return true
 		}
 	}
@@ -36,14 +42,36 @@ type BranchStatus struct {
 	Branch        string
 	IsMain        bool // true for main/master — used by TV template CSS class
 	Checks        []github.Check
+	Workflows     []github.CheckGroup // Checks grouped by workflow run
 	HasChecks     bool
 	CommitSHA     string
 	CommitURL     string
 	SuccessCount  int
-	FailureCount  int
+	FailureCount  int // failed required checks (all checks if none are required)
 	RunningCount  int
 	OverallStatus string
 	StatusClass   string
+
+	// Required-check awareness, from branch protection and rulesets. With
+	// no RequiredChecks every check counts as required.
+	RequiredChecks       []string
+	MissingRequired      []string
+	OptionalFailureCount int
+
+	// UpstreamFailures lists the repositories this one depends on that
+	// fail on the same branch; set only when this branch fails too.
+	UpstreamFailures []string
+}
+
+// HasDetails reports whether the branch is listed in the expanded details.
+func (bs BranchStatus) HasDetails() bool {
+	return bs.FailureCount > 0 || bs.OptionalFailureCount > 0 || bs.RunningCount > 0 || len(bs.MissingRequired) > 0
+}
+
+// IsOptional reports whether check is not required on this branch. It is
+// always false when the branch has no required checks.
+func (bs BranchStatus) IsOptional(check github.Check) bool {
+	return len(bs.RequiredChecks) > 0 && !check.Required
 }
 
 // sortByConfigOrder sorts repositories to match config.yaml order.
@@ -107,7 +135,10 @@ func groupByRepository(branchChecks []github.BranchCheck, repoConfig []RepoBranc
 				bs.HasChecks = len(bc.Checks) > 0
 				bs.CommitSHA = bc.CommitSHA
 				bs.CommitURL = bc.CommitURL
+				bs.RequiredChecks = bc.RequiredChecks
+				bs.MissingRequired = bc.MissingRequired
 				computeBranchCounts(&bs)
+				bs.Workflows = github.GroupChecks(bs.Checks)
 				hasData = true
 			}
 			rs.Branches = append(rs.Branches, bs)
@@ -137,8 +168,12 @@ func groupByRepository(branchChecks []github.BranchCheck, repoConfig []RepoBranc
 			HasChecks: len(bc.Checks) > 0,
 			CommitSHA: bc.CommitSHA,
 			CommitURL: bc.CommitURL,
+
+			RequiredChecks:  bc.RequiredChecks,
+			MissingRequired: bc.MissingRequired,
 		}
 		computeBranchCounts(&bs)
+		bs.Workflows = github.GroupChecks(bs.Checks)
 		rs.Branches = append(rs.Branches, bs)
 	}
 	// Collect and sort unknown repos alphabetically.
@@ -155,12 +190,19 @@ func groupByRepository(branchChecks []github.BranchCheck, repoConfig []RepoBranc
 }
 
 func (h *Handler) BuildStatus(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
 	branchChecks, lastUpdate := h.storage.GetBranchChecks()
-	log.Printf("Serving /builds - Branch checks: %d", len(branchChecks))
+	branchChecks = keepVisible(branchChecks, func(bc github.BranchCheck) string { return bc.Repository }, allowed)
+	logging.FromContext(r.Context()).Debug("serving builds", "branch_checks", len(branchChecks))
 
-	repositories := groupByRepository(branchChecks, h.repoConfig)
+	flaky := flakyIndex(h.flakyChecks(settings.RepoNames))
+	for i := range branchChecks {
+		markFlaky(branchChecks[i].Repository, branchChecks[i].Checks, flaky)
+	}
+	repositories := groupByRepository(branchChecks, settings.RepoConfig)
+	markUpstreamFailures(repositories, settings.RepoConfig)
 
-	repo := sanitizeRepo(r.URL.Query().Get("repo"), h.repoNames)
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
 	if repo != "" {
 		var filtered []*RepositoryStatus
 		for _, r := range repositories {
@@ -171,16 +213,24 @@ func (h *Handler) BuildStatus(w http.ResponseWriter, r *http.Request) {
 		repositories = filtered
 	}
 
-	staleMap, staleList := h.computeStaleness(storage.CategoryChecks, h.fetchIntervals.Actions, lastUpdate)
+	staleMap, staleList := h.computeStaleness(storage.CategoryChecks, settings.FetchIntervals.Actions, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
 	for _, r := range repositories {
 		r.Stale = staleMap[r.Name]
 	}
 
+	scheduled := keepVisible(h.storage.GetScheduledWorkflows(), func(wf github.ScheduledWorkflow) string { return wf.Repository }, allowed)
+	if repo != "" {
+		scheduled = keepVisible(scheduled, func(wf github.ScheduledWorkflow) string { return wf.Repository }, func(name string) bool { return name == repo })
+	}
+	sortScheduled(scheduled, settings.RepoNames)
+
 	data := struct {
 		PageID        string
 		Organization  string
 		Version       string
 		Repositories  []*RepositoryStatus
+		Scheduled     []github.ScheduledWorkflow
 		LastUpdate    time.Time
 		Repo          string
 		RepoNames     []string
@@ -188,31 +238,34 @@ func (h *Handler) BuildStatus(w http.ResponseWriter, r *http.Request) {
 		StaleRepoList []string
 	}{
 		PageID:        "builds",
-		Organization:  h.organization,
+		Organization:  settings.Organization,
 		Version:       h.version,
 		Repositories:  repositories,
+		Scheduled:     scheduled,
 		LastUpdate:    lastUpdate,
 		Repo:          repo,
-		RepoNames:     h.repoNames,
+		RepoNames:     settings.RepoNames,
 		StaleRepos:    staleMap,
 		StaleRepoList: staleList,
 	}
 
-	renderTemplate(w, h.buildTemplate, "base", data)
+	renderTemplate(w, r, h.buildTemplate, "base", data)
 }
 
 func (h *Handler) BuildsDashboard(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
 	branchChecks, lastUpdate := h.storage.GetBranchChecks()
-	log.Printf("Serving /builds-dashboard - Branch checks: %d", len(branchChecks))
+	branchChecks = keepVisible(branchChecks, func(bc github.BranchCheck) string { return bc.Repository }, allowed)
+	logging.FromContext(r.Context()).Debug("serving builds dashboard", "branch_checks", len(branchChecks))
 
-	repositories := groupByRepository(branchChecks, h.repoConfig)
+	repositories := groupByRepository(branchChecks, settings.RepoConfig)
 
 	// Ensure all configured repos appear, even without data
 	repoSet := make(map[string]bool, len(repositories))
 	for _, repo := range repositories {
 		repoSet[repo.Name] = true
 	}
-	for _, rc := range h.repoConfig {
+	for _, rc := range settings.RepoConfig {
 		if !repoSet[rc.Name] {
 			rs := &RepositoryStatus{Name: rc.Name}
 			for _, branch := range rc.Branches {
@@ -225,12 +278,17 @@ func (h *Handler) BuildsDashboard(w http.ResponseWriter, r *http.Request) {
 			repositories = append(repositories, rs)
 		}
 	}
-	sortByConfigOrder(repositories, h.repoNames)
+	sortByConfigOrder(repositories, settings.RepoNames)
 
-	staleMap, staleList := h.computeStaleness(storage.CategoryChecks, h.fetchIntervals.Actions, lastUpdate)
+	staleMap, staleList := h.computeStaleness(storage.CategoryChecks, settings.FetchIntervals.Actions, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
 	for _, repo := range repositories {
 		repo.Stale = staleMap[repo.Name]
 	}
+	repositories, staleList = restrictToKiosk(r, repositories, staleList)
+	// After restricting, so a scoped screen never names repositories it
+	// may not show as upstream causes.
+	markUpstreamFailures(repositories, settings.RepoConfig)
 
 	data := struct {
 		Organization  string
@@ -239,34 +297,53 @@ func (h *Handler) BuildsDashboard(w http.ResponseWriter, r *http.Request) {
 		StaleRepos    map[string]bool
 		StaleRepoList []string
 	}{
-		Organization:  h.organization,
+		Organization:  settings.Organization,
 		Repositories:  repositories,
 		LastUpdate:    lastUpdate,
 		StaleRepos:    staleMap,
 		StaleRepoList: staleList,
 	}
 
-	renderTemplate(w, h.dashboardTemplate, "builds_dashboard.html", data)
+	renderTemplate(w, r, h.dashboardTemplate, "builds_dashboard.html", data)
 }
 
+// computeBranchCounts tallies the branch's checks and derives its overall
+// status from the required checks only: optional failures downgrade
+// "Passed" to a warning, and a required check that never reported counts
+// against the branch.
 func computeBranchCounts(bs *BranchStatus) {
+	requiredRunning := 0
 	for _, check := range bs.Checks {
+		optional := bs.IsOptional(check)
 		switch github.ClassifyCheck(check.Status, check.Conclusion) {
 		case "running":
 			bs.RunningCount++
+			if !optional {
+				requiredRunning++
+			}
 		case "success":
 			bs.SuccessCount++
 		default:
-			bs.FailureCount++
+			if optional {
+				bs.OptionalFailureCount++
+			} else {
+				bs.FailureCount++
+			}
 		}
 	}
 	switch {
-	case bs.RunningCount > 0:
+	case requiredRunning > 0:
 		bs.OverallStatus = "Running"
 		bs.StatusClass = "status-running"
 	case bs.FailureCount > 0:
 		bs.OverallStatus = "Failed"
 		bs.StatusClass = "status-failure"
+	case len(bs.MissingRequired) > 0:
+		bs.OverallStatus = "Required check missing"
+		bs.StatusClass = "status-failure"
+	case bs.OptionalFailureCount > 0:
+		bs.OverallStatus = "Passed with warnings"
+		bs.StatusClass = "status-warning"
 	case bs.SuccessCount > 0:
 		bs.OverallStatus = "Passed"
 		bs.StatusClass = "status-success"
@@ -276,6 +353,21 @@ func computeBranchCounts(bs *BranchStatus) {
 	}
 }
 
+// sortScheduled orders scheduled workflows by repository, in config order,
+// then by workflow name.
+func sortScheduled(workflows []github.ScheduledWorkflow, repoNames []string) {
+	repoIndex := make(map[string]int, len(repoNames))
+	for i, name := range repoNames {
+		repoIndex[name] = i
+	}
+	sort.SliceStable(workflows, func(i, j int) bool {
+		if workflows[i].Repository != workflows[j].Repository {
+			return repoIndex[workflows[i].Repository] < repoIndex[workflows[j].Repository]
+		}
+		return workflows[i].Name < workflows[j].Name
+	})
+}
+
 func isMainBranch(branch string) bool {
 	return branch == "main" || branch == "master"
 }
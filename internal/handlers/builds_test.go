@@ -147,6 +147,68 @@ func TestComputeBranchCounts(t *testing.T) {
 	}
 }
 
+func TestComputeBranchCountsRequired(t *testing.T) {
+	tests := []struct {
+		name         string
+		checks       []github.Check
+		missing      []string
+		wantFailure  int
+		wantOptional int
+		wantStatus   string
+		wantClass    string
+	}{
+		{
+			name: "optional_failure_is_warning",
+			checks: []github.Check{
+				{Name: "test", Status: "completed", Conclusion: "success", Required: true},
+				{Name: "lint", Status: "completed", Conclusion: "failure"},
+			},
+			wantOptional: 1, wantStatus: "Passed with warnings", wantClass: "status-warning",
+		},
+		{
+			name: "required_failure",
+			checks: []github.Check{
+				{Name: "test", Status: "completed", Conclusion: "failure", Required: true},
+				{Name: "lint", Status: "completed", Conclusion: "failure"},
+			},
+			wantFailure: 1, wantOptional: 1, wantStatus: "Failed", wantClass: "status-failure",
+		},
+		{
+			name: "optional_running_ignored",
+			checks: []github.Check{
+				{Name: "test", Status: "completed", Conclusion: "success", Required: true},
+				{Name: "nightly", Status: "in_progress"},
+			},
+			wantStatus: "Passed", wantClass: "status-success",
+		},
+		{
+			name: "required_missing",
+			checks: []github.Check{
+				{Name: "test", Status: "completed", Conclusion: "success", Required: true},
+			},
+			missing:    []string{"docs"},
+			wantStatus: "Required check missing", wantClass: "status-failure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := &BranchStatus{Checks: tt.checks, RequiredChecks: []string{"docs", "test"}, MissingRequired: tt.missing}
+			computeBranchCounts(bs)
+
+			if bs.FailureCount != tt.wantFailure || bs.OptionalFailureCount != tt.wantOptional {
+				t.Errorf("failures = %d required, %d optional; want %d, %d", bs.FailureCount, bs.OptionalFailureCount, tt.wantFailure, tt.wantOptional)
+			}
+			if bs.OverallStatus != tt.wantStatus || bs.StatusClass != tt.wantClass {
+				t.Errorf("status = %q/%q, want %q/%q", bs.OverallStatus, bs.StatusClass, tt.wantStatus, tt.wantClass)
+			}
+			if !bs.HasDetails() && tt.wantStatus != "Passed" {
+				t.Error("HasDetails() = false, want true")
+			}
+		})
+	}
+}
+
 func TestSortByConfigOrder(t *testing.T) {
 	configOrder := []string{"eccodes", "atlas", "odc"}
 	repos := []*RepositoryStatus{
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+)
+
+// findCheck returns the branch check and check run with the given ID in
+// repo.
+func findCheck(branchChecks []github.BranchCheck, repo string, id int64) (github.BranchCheck, github.Check, bool) {
+	for _, bc := range branchChecks {
+		if bc.Repository != repo {
+			continue
+		}
+		for _, check := range bc.Checks {
+			if check.ID == id {
+				return bc, check, true
+			}
+		}
+	}
+	return github.BranchCheck{}, github.Check{}, false
+}
+
+// CheckDetail shows why a check run on a tracked branch failed: its output
+// summary, annotations and log tail. Checks no longer at a branch head are
+// gone.
+func (h *Handler) CheckDetail(w http.ResponseWriter, r *http.Request) {
+	settings, _ := h.visibleSettings(r, h.settings())
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if repo == "" || err != nil || id <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	branchChecks, lastUpdate := h.storage.GetBranchChecks()
+	bc, check, ok := findCheck(branchChecks, repo, id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	logging.FromContext(r.Context()).Debug("serving check detail", "repo", repo, "check_run", id)
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Repo          string
+		Branch        github.BranchCheck
+		Check         github.Check
+		LastUpdate    time.Time
+		StaleRepoList []string
+	}{
+		PageID:       "builds",
+		Organization: settings.Organization,
+		Version:      h.version,
+		Repo:         repo,
+		Branch:       bc,
+		Check:        check,
+		LastUpdate:   lastUpdate,
+	}
+
+	renderTemplate(w, r, h.checkTemplate, "base", data)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+func TestCheckDetailHandler(t *testing.T) {
+	h, store := newTestHandler(t)
+	store.SetBranchChecks([]github.BranchCheck{{
+		Repository: "eccodes",
+		Branch:     "develop",
+		CommitSHA:  "abc1234567",
+		Checks: []github.Check{
+			{ID: 5, Name: "build", Workflow: "CI", Status: "completed", Conclusion: "failure", Failure: &github.FailureDetails{
+				Title:           "1 error",
+				Summary:         "Compilation failed",
+				AnnotationCount: 1,
+				Annotations:     []github.Annotation{{Path: "src/grib.c", StartLine: 42, Level: "failure", Message: "undefined symbol grib_x"}},
+				Log:             []string{"make: *** [all] Error 2"},
+			}},
+			{ID: 6, Name: "lint", Workflow: "CI", Status: "completed", Conclusion: "success"},
+		},
+	}})
+
+	rec := httptest.NewRecorder()
+	h.BuildStatus(rec, httptest.NewRequest(http.MethodGet, "/builds", nil))
+	assertResponse(t, rec, http.StatusOK, `href="builds/check?repo=eccodes&amp;id=5"`, "<code>src/grib.c:42</code> undefined symbol grib_x")
+
+	rec = httptest.NewRecorder()
+	h.CheckDetail(rec, httptest.NewRequest(http.MethodGet, "/builds/check?repo=eccodes&id=5", nil))
+	assertResponse(t, rec, http.StatusOK, "Compilation failed", "src/grib.c:42", "make: *** [all] Error 2", "abc1234", `href="../static/base.css"`)
+	if strings.Contains(rec.Body.String(), "abc1234567") {
+		t.Error("commit SHA should be abbreviated")
+	}
+
+	rec = httptest.NewRecorder()
+	h.CheckDetail(rec, httptest.NewRequest(http.MethodGet, "/builds/check?repo=eccodes&id=6", nil))
+	assertResponse(t, rec, http.StatusOK, "No output was recorded")
+
+	for _, query := range []string{"repo=eccodes&id=99", "repo=atlas&id=5", "repo=unknown&id=5", "repo=eccodes&id=x"} {
+		rec = httptest.NewRecorder()
+		h.CheckDetail(rec, httptest.NewRequest(http.MethodGet, "/builds/check?"+query, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", query, rec.Code)
+		}
+	}
+}
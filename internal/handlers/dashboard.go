@@ -2,41 +2,86 @@ package handlers
 
 import (
 	"html/template"
-	"log"
 	"net/http"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
 	"github.com/ozaq/ecmwf-dash/internal/storage"
 )
 
 type Handler struct {
-	storage           storage.Store
-	template          *template.Template
-	prTemplate        *template.Template
-	buildTemplate     *template.Template
-	dashboardTemplate *template.Template
-	organization      string
-	version           string
-	repoNames         []string
-	repoConfig        []RepoBranches
-	fetchIntervals    FetchIntervals
+	storage             storage.Store
+	template            *template.Template
+	prTemplate          *template.Template
+	buildTemplate       *template.Template
+	dashboardTemplate   *template.Template
+	kioskTemplate       *template.Template
+	trendsTemplate      *template.Template
+	flakyTemplate       *template.Template
+	checkTemplate       *template.Template
+	graphTemplate       *template.Template
+	branchesTemplate    *template.Template
+	releaseTemplate     *template.Template
+	repoTemplate        *template.Template
+	planningTemplate    *template.Template
+	queueTemplate       *template.Template
+	deploymentsTemplate *template.Template
+	releasesTemplate    *template.Template
+	kiosks              KioskStatusSource
+	visibility          RepoVisibility
+	publicRepos         PublicRepos
+	version             string
+
+	// mu guards the settings below, which are replaced on config reload.
+	mu             sync.RWMutex
+	organization   string
+	repoNames      []string
+	repoConfig     []RepoBranches
+	fetchIntervals FetchIntervals
+	trends         TrendSettings
 }
 
 // HandlerConfig groups the parameters needed to construct a Handler.
 type HandlerConfig struct {
-	Store          storage.Store
-	IssuesTmpl     *template.Template
-	PRsTmpl        *template.Template
-	BuildTmpl      *template.Template
-	DashboardTmpl  *template.Template
+	Store           storage.Store
+	IssuesTmpl      *template.Template
+	PRsTmpl         *template.Template
+	BuildTmpl       *template.Template
+	DashboardTmpl   *template.Template
+	KioskTmpl       *template.Template
+	TrendsTmpl      *template.Template
+	FlakyTmpl       *template.Template
+	CheckTmpl       *template.Template
+	GraphTmpl       *template.Template
+	BranchesTmpl    *template.Template
+	ReleaseTmpl     *template.Template
+	RepoTmpl        *template.Template
+	PlanningTmpl    *template.Template
+	QueueTmpl       *template.Template
+	DeploymentsTmpl *template.Template
+	ReleasesTmpl    *template.Template
+	Kiosks          KioskStatusSource // nil when authentication is disabled
+	Visibility      RepoVisibility    // nil when per-viewer filtering is disabled
+	PublicRepos     PublicRepos       // nil when authentication is disabled
+	Organization    string
+	Version         string
+	RepoNames       []string
+	RepoConfig      []RepoBranches
+	FetchIntervals  FetchIntervals
+	Trends          TrendSettings
+}
+
+// RepoSettings groups the handler settings that can change on config reload.
+type RepoSettings struct {
 	Organization   string
-	Version        string
 	RepoNames      []string
 	RepoConfig     []RepoBranches
 	FetchIntervals FetchIntervals
+	Trends         TrendSettings
 }
 
 func New(cfg HandlerConfig) *Handler {
@@ -55,23 +100,102 @@ func New(cfg HandlerConfig) *Handler {
 	if cfg.DashboardTmpl == nil {
 		panic("DashboardTmpl must not be nil")
 	}
+	if cfg.KioskTmpl == nil {
+		panic("KioskTmpl must not be nil")
+	}
+	if cfg.TrendsTmpl == nil {
+		panic("TrendsTmpl must not be nil")
+	}
+	if cfg.FlakyTmpl == nil {
+		panic("FlakyTmpl must not be nil")
+	}
+	if cfg.CheckTmpl == nil {
+		panic("CheckTmpl must not be nil")
+	}
+	if cfg.GraphTmpl == nil {
+		panic("GraphTmpl must not be nil")
+	}
+	if cfg.BranchesTmpl == nil {
+		panic("BranchesTmpl must not be nil")
+	}
+	if cfg.ReleaseTmpl == nil {
+		panic("ReleaseTmpl must not be nil")
+	}
+	if cfg.RepoTmpl == nil {
+		panic("RepoTmpl must not be nil")
+	}
+	if cfg.PlanningTmpl == nil {
+		panic("PlanningTmpl must not be nil")
+	}
+	if cfg.QueueTmpl == nil {
+		panic("QueueTmpl must not be nil")
+	}
+	if cfg.DeploymentsTmpl == nil {
+		panic("DeploymentsTmpl must not be nil")
+	}
+	if cfg.ReleasesTmpl == nil {
+		panic("ReleasesTmpl must not be nil")
+	}
 	return &Handler{
-		storage:           cfg.Store,
-		template:          cfg.IssuesTmpl,
-		prTemplate:        cfg.PRsTmpl,
-		buildTemplate:     cfg.BuildTmpl,
-		dashboardTemplate: cfg.DashboardTmpl,
-		organization:      cfg.Organization,
-		version:           cfg.Version,
-		repoNames:         cfg.RepoNames,
-		repoConfig:        cfg.RepoConfig,
-		fetchIntervals:    cfg.FetchIntervals,
+		storage:             cfg.Store,
+		template:            cfg.IssuesTmpl,
+		prTemplate:          cfg.PRsTmpl,
+		buildTemplate:       cfg.BuildTmpl,
+		dashboardTemplate:   cfg.DashboardTmpl,
+		kioskTemplate:       cfg.KioskTmpl,
+		trendsTemplate:      cfg.TrendsTmpl,
+		flakyTemplate:       cfg.FlakyTmpl,
+		checkTemplate:       cfg.CheckTmpl,
+		graphTemplate:       cfg.GraphTmpl,
+		branchesTemplate:    cfg.BranchesTmpl,
+		releaseTemplate:     cfg.ReleaseTmpl,
+		repoTemplate:        cfg.RepoTmpl,
+		planningTemplate:    cfg.PlanningTmpl,
+		queueTemplate:       cfg.QueueTmpl,
+		deploymentsTemplate: cfg.DeploymentsTmpl,
+		releasesTemplate:    cfg.ReleasesTmpl,
+		kiosks:              cfg.Kiosks,
+		visibility:          cfg.Visibility,
+		publicRepos:         cfg.PublicRepos,
+		organization:        cfg.Organization,
+		version:             cfg.Version,
+		repoNames:           cfg.RepoNames,
+		repoConfig:          cfg.RepoConfig,
+		fetchIntervals:      cfg.FetchIntervals,
+		trends:              cfg.Trends,
+	}
+}
+
+// UpdateSettings atomically replaces the repository list, organization,
+// fetch intervals and trend settings. Requests already in flight finish with the old settings.
+func (h *Handler) UpdateSettings(s RepoSettings) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.organization = s.Organization
+	h.repoNames = s.RepoNames
+	h.repoConfig = s.RepoConfig
+	h.fetchIntervals = s.FetchIntervals
+	h.trends = s.Trends
+}
+
+// settings returns a consistent snapshot of the reloadable settings.
+func (h *Handler) settings() RepoSettings {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return RepoSettings{
+		Organization:   h.organization,
+		RepoNames:      h.repoNames,
+		RepoConfig:     h.repoConfig,
+		FetchIntervals: h.fetchIntervals,
+		Trends:         h.trends,
 	}
 }
 
 func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
 	issues, lastUpdate := h.storage.GetIssues()
-	log.Printf("Serving /issues - Issues: %d", len(issues))
+	issues = keepVisible(issues, func(i github.Issue) string { return i.Repository }, allowed)
+	logging.FromContext(r.Context()).Debug("serving issues", "count", len(issues))
 
 	// Get query params
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
@@ -81,7 +205,8 @@ func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
 
 	sortBy := sanitizeSort(r.URL.Query().Get("sort"))
 	order := sanitizeOrder(r.URL.Query().Get("order"))
-	repo := sanitizeRepo(r.URL.Query().Get("repo"), h.repoNames)
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	prFilter := sanitizePRFilter(r.URL.Query().Get("pr"))
 
 	// Filter by repo
 	if repo != "" {
@@ -94,23 +219,38 @@ func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		issues = filtered
 	}
 
+	prs, _ := h.storage.GetPullRequests()
+	linked := openPRsByIssue(keepVisible(prs, func(pr github.PullRequest) string { return pr.Repository }, allowed))
+	if prFilter != "" {
+		filtered := issues[:0]
+		for _, issue := range issues {
+			if (len(linkedPRs(linked, issue)) > 0) == (prFilter == "open") {
+				filtered = append(filtered, issue)
+			}
+		}
+		issues = filtered
+	}
+
 	// Sort issues
 	sortIssues(issues, sortBy, order)
 
 	// Paginate
 	start, end, totalPages := paginate(len(issues), page, itemsPerPage)
-	var pageIssues []github.Issue
+	var pageIssues []IssueRow
 	if start < len(issues) {
-		pageIssues = issues[start:end]
+		for _, issue := range issues[start:end] {
+			pageIssues = append(pageIssues, IssueRow{Issue: issue, OpenPRs: linkedPRs(linked, issue)})
+		}
 	}
 
-	staleMap, staleList := h.computeStaleness(storage.CategoryIssues, h.fetchIntervals.Issues, lastUpdate)
+	staleMap, staleList := h.computeStaleness(storage.CategoryIssues, settings.FetchIntervals.Issues, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
 
 	data := struct {
 		PageID        string
 		Organization  string
 		Version       string
-		Issues        []github.Issue
+		Issues        []IssueRow
 		LastUpdate    time.Time
 		CurrentPage   int
 		TotalPages    int
@@ -119,12 +259,13 @@ func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		Order         string
 		NextOrder     string
 		Repo          string
+		PRFilter      string
 		RepoNames     []string
 		StaleRepos    map[string]bool
 		StaleRepoList []string
 	}{
 		PageID:        "issues",
-		Organization:  h.organization,
+		Organization:  settings.Organization,
 		Version:       h.version,
 		Issues:        pageIssues,
 		LastUpdate:    lastUpdate,
@@ -135,12 +276,13 @@ func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		Order:         order,
 		NextOrder:     getNextOrder(order),
 		Repo:          repo,
-		RepoNames:     h.repoNames,
+		PRFilter:      prFilter,
+		RepoNames:     settings.RepoNames,
 		StaleRepos:    staleMap,
 		StaleRepoList: staleList,
 	}
 
-	renderTemplate(w, h.template, "base", data)
+	renderTemplate(w, r, h.template, "base", data)
 }
 
 func sortIssues(issues []github.Issue, sortBy, order string) {
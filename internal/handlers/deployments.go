@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// DeploymentRow is a repository's row of the environments matrix.
+type DeploymentRow struct {
+	Repo  string
+	Cells []*github.Environment // one per column; nil when never deployed there
+}
+
+// deploymentMatrix lays out the environments with a column per environment
+// name, sorted, and a row per repository with deployments, in config order.
+func deploymentMatrix(envs []github.Environment, repoNames []string) ([]string, []DeploymentRow) {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, env := range envs {
+		if !seen[env.Name] {
+			seen[env.Name] = true
+			columns = append(columns, env.Name)
+		}
+	}
+	sort.Strings(columns)
+	col := make(map[string]int, len(columns))
+	for i, name := range columns {
+		col[name] = i
+	}
+
+	byRepo := make(map[string][]*github.Environment)
+	for i := range envs {
+		env := &envs[i]
+		if byRepo[env.Repository] == nil {
+			byRepo[env.Repository] = make([]*github.Environment, len(columns))
+		}
+		byRepo[env.Repository][col[env.Name]] = env
+	}
+	var rows []DeploymentRow
+	for _, name := range repoNames {
+		if cells, ok := byRepo[name]; ok {
+			rows = append(rows, DeploymentRow{Repo: name, Cells: cells})
+		}
+	}
+	return columns, rows
+}
+
+// Deployments shows the latest deployment to each environment of each
+// repository and, when an environment is picked, its recent history.
+func (h *Handler) Deployments(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
+	envs, lastUpdate := h.storage.GetDeployments()
+	envs = keepVisible(envs, func(e github.Environment) string { return e.Repository }, allowed)
+
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	if repo != "" {
+		envs = keepVisible(envs, func(e github.Environment) string { return e.Repository }, func(name string) bool { return name == repo })
+	}
+	columns, rows := deploymentMatrix(envs, settings.RepoNames)
+
+	var history *github.Environment
+	if name := r.URL.Query().Get("environment"); repo != "" && name != "" {
+		for i := range envs {
+			if envs[i].Name == name {
+				history = &envs[i]
+				break
+			}
+		}
+	}
+	logging.FromContext(r.Context()).Debug("serving deployments", "environments", len(envs))
+
+	staleMap, staleList := h.computeStaleness(storage.CategoryDeployments, settings.FetchIntervals.Deployments, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Columns       []string // environment names
+		Rows          []DeploymentRow
+		History       *github.Environment // the picked environment; nil for none
+		LastUpdate    time.Time
+		Repo          string
+		RepoNames     []string
+		StaleRepos    map[string]bool
+		StaleRepoList []string
+	}{
+		PageID:        "deployments",
+		Organization:  settings.Organization,
+		Version:       h.version,
+		Columns:       columns,
+		Rows:          rows,
+		History:       history,
+		LastUpdate:    lastUpdate,
+		Repo:          repo,
+		RepoNames:     settings.RepoNames,
+		StaleRepos:    staleMap,
+		StaleRepoList: staleList,
+	}
+
+	renderTemplate(w, r, h.deploymentsTemplate, "base", data)
+}
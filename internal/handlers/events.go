@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// eventFeed serves the events of the given kinds that keep accepts (all
+// with a nil keep) as an Atom feed, for the repositories allowed by
+// feedSettings or, like the HTML page at page, for the repository in the
+// repo parameter. lastUpdate is when the events' category was last fetched.
+func (h *Handler) eventFeed(w http.ResponseWriter, r *http.Request, settings RepoSettings, allowed func(string) bool, keep func(storage.Event) bool, what, page string, lastUpdate time.Time, kinds ...string) {
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	if repo != "" {
+		allowed = func(name string) bool { return name == repo }
+	}
+	wanted := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		wanted[kind] = true
+	}
+
+	scope := settings.Organization
+	if repo != "" {
+		scope = repo
+	}
+	feed := newAtomFeed(r, scope+" "+what, page)
+	feed.Author = atomPerson{Name: settings.Organization}
+	for _, e := range keepVisible(h.storage.GetEvents(), func(e storage.Event) string { return e.Repository }, allowed) {
+		if wanted[e.Kind] && (keep == nil || keep(e)) {
+			feed.Entries = append(feed.Entries, eventEntry(e))
+		}
+	}
+	writeAtom(w, r, feed, lastUpdate)
+}
+
+// IssuesFeed serves newly opened issues as an Atom feed. Like /issues, the
+// pr parameter keeps those with ("open") or without ("none") an open pull
+// request that closes them, as of the latest fetch.
+func (h *Handler) IssuesFeed(w http.ResponseWriter, r *http.Request) {
+	if !checkFeedParams(w, r, "pr") {
+		return
+	}
+	settings, allowed := h.feedSettings(r)
+	var keep func(storage.Event) bool
+	if prFilter := sanitizePRFilter(r.URL.Query().Get("pr")); prFilter != "" {
+		prs, _ := h.storage.GetPullRequests()
+		linked := openPRsByIssue(keepVisible(prs, func(pr github.PullRequest) string { return pr.Repository }, allowed))
+		keep = func(e storage.Event) bool {
+			issue := github.Issue{Repository: e.Repository, Number: e.Number}
+			return (len(linkedPRs(linked, issue)) > 0) == (prFilter == "open")
+		}
+	}
+	issues, _, _ := h.storage.LastFetchTimes()
+	h.eventFeed(w, r, settings, allowed, keep, "issues", "issues", issues, storage.EventIssueOpened)
+}
+
+// PullRequestsFeed serves newly opened and merged pull requests as an
+// Atom feed.
+func (h *Handler) PullRequestsFeed(w http.ResponseWriter, r *http.Request) {
+	if !checkFeedParams(w, r) {
+		return
+	}
+	settings, allowed := h.feedSettings(r)
+	_, prs, _ := h.storage.LastFetchTimes()
+	h.eventFeed(w, r, settings, allowed, nil, "pull requests", "pulls", prs, storage.EventPROpened, storage.EventPRMerged)
+}
+
+// BuildsFeed serves branches starting to fail or passing again as an Atom
+// feed.
+func (h *Handler) BuildsFeed(w http.ResponseWriter, r *http.Request) {
+	if !checkFeedParams(w, r) {
+		return
+	}
+	settings, allowed := h.feedSettings(r)
+	_, _, checks := h.storage.LastFetchTimes()
+	h.eventFeed(w, r, settings, allowed, nil, "builds", "builds", checks, storage.EventBuildChanged)
+}
+
+// eventEntry describes an event. Entry IDs are built from GitHub URLs
+// rather than anything local to the dashboard: the issue or pull request
+// page, with "#merged" for merges, and the commit page with the branch,
+// status and time for builds, which can flip back and forth on a commit.
+func eventEntry(e storage.Event) atomEntry {
+	entry := atomEntry{
+		ID:         e.URL,
+		Updated:    atomTime(e.At),
+		Published:  atomTime(e.At),
+		Links:      []atomLink{{Rel: "alternate", Type: "text/html", Href: e.URL}},
+		Categories: []atomCategory{{Term: e.Repository}},
+	}
+	for _, label := range e.Labels {
+		entry.Categories = append(entry.Categories, atomCategory{Term: label})
+	}
+	if e.Author != "" {
+		entry.Author = &atomPerson{Name: e.Author}
+	}
+
+	switch e.Kind {
+	case storage.EventIssueOpened:
+		entry.Title = fmt.Sprintf("%s#%d opened: %s", e.Repository, e.Number, e.Title)
+		entry.Summary = fmt.Sprintf("Issue opened by %s.", e.Author)
+	case storage.EventPROpened:
+		entry.Title = fmt.Sprintf("%s#%d opened: %s", e.Repository, e.Number, e.Title)
+		entry.Summary = fmt.Sprintf("Pull request into %s opened by %s.", e.Branch, e.Author)
+	case storage.EventPRMerged:
+		entry.ID = e.URL + "#merged"
+		entry.Title = fmt.Sprintf("%s#%d merged: %s", e.Repository, e.Number, e.Title)
+		entry.Summary = fmt.Sprintf("Pull request by %s merged into %s.", e.Author, e.Branch)
+	case storage.EventBuildChanged:
+		sha := shortSHA(e.CommitSHA)
+		entry.ID = fmt.Sprintf("%s#%s:%s@%s", e.URL, e.Branch, e.Status, atomTime(e.At))
+		if e.Status == "failure" {
+			entry.Title = fmt.Sprintf("%s %s is failing", e.Repository, e.Branch)
+			entry.Summary = fmt.Sprintf("Required checks of %s on %s fail; they passed before.", sha, e.Branch)
+		} else {
+			entry.Title = fmt.Sprintf("%s %s is passing again", e.Repository, e.Branch)
+			entry.Summary = fmt.Sprintf("Required checks of %s on %s pass again.", sha, e.Branch)
+		}
+		entry.Categories = append(entry.Categories, atomCategory{Term: e.Branch})
+	}
+	return entry
+}
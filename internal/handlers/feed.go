@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/auth"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+)
+
+// maxFeedEntries caps the entries of an Atom feed.
+const maxFeedEntries = 50
+
+// atomFeed is an Atom (RFC 4287) feed document.
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// atomTime formats t as an RFC 3339 timestamp in UTC.
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// checkFeedParams responds with 400 and returns false if r has a query
+// parameter other than repo, the kiosk token and the feed's extra filters,
+// so a feed never claims a filter it does not apply.
+func checkFeedParams(w http.ResponseWriter, r *http.Request, extra ...string) bool {
+	for name := range r.URL.Query() {
+		if name != "repo" && name != auth.KioskParam && !slices.Contains(extra, name) {
+			http.Error(w, fmt.Sprintf("Feeds do not support the %q parameter", name), http.StatusBadRequest)
+			return false
+		}
+	}
+	return true
+}
+
+// feedSettings is visibleSettings narrowed for feed readers using a kiosk
+// token: to the token's repositories and, since feeds leave the kiosk
+// screen, to public repositories. Repositories whose visibility cannot be
+// determined are hidden.
+func (h *Handler) feedSettings(r *http.Request) (RepoSettings, func(string) bool) {
+	settings, allowed := h.visibleSettings(r, h.settings())
+	k, ok := auth.KioskFromContext(r.Context())
+	if !ok {
+		return settings, allowed
+	}
+	var public map[string]bool
+	if h.publicRepos != nil {
+		public = h.publicRepos.Public(r.Context(), settings.Organization, settings.RepoNames)
+	}
+	visible := allowed
+	allowed = func(repo string) bool {
+		return visible(repo) && k.Allows(repo) && (h.publicRepos == nil || public[repo])
+	}
+	settings.RepoNames = keepVisible(settings.RepoNames, func(name string) string { return name }, allowed)
+	settings.RepoConfig = keepVisible(settings.RepoConfig, func(rc RepoBranches) string { return rc.Name }, allowed)
+	return settings, allowed
+}
+
+// newAtomFeed starts a feed served at r. Its ID is the request URL, which
+// is stable for a given set of filters; the links are relative, so the
+// feed also works behind a path prefix. page is the HTML page showing the
+// same data, with the same query. A kiosk token in the query is left out.
+func newAtomFeed(r *http.Request, title, page string) atomFeed {
+	query := r.URL.Query()
+	query.Del(auth.KioskParam)
+	encoded := query.Encode()
+	if encoded != "" {
+		encoded = "?" + encoded
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		scheme = proto
+	}
+	id := url.URL{Scheme: scheme, Host: r.Host, Path: r.URL.Path, RawQuery: query.Encode()}
+
+	return atomFeed{
+		ID:    id.String(),
+		Title: title,
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: path.Base(r.URL.Path) + encoded},
+			{Rel: "alternate", Type: "text/html", Href: page + encoded},
+		},
+	}
+}
+
+// writeAtom renders feed, which is updated as of its newest entry or, with
+// no entries, as of lastUpdate. Like renderTemplate, it writes nothing on
+// error.
+func writeAtom(w http.ResponseWriter, r *http.Request, feed atomFeed, lastUpdate time.Time) {
+	if len(feed.Entries) > maxFeedEntries {
+		feed.Entries = feed.Entries[:maxFeedEntries]
+	}
+	// RFC 3339 timestamps in UTC compare in time order as strings.
+	for _, e := range feed.Entries {
+		if e.Updated > feed.Updated {
+			feed.Updated = e.Updated
+		}
+	}
+	if feed.Updated == "" {
+		feed.Updated = atomTime(lastUpdate)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		logging.FromContext(r.Context()).Error("encoding feed failed", "feed", feed.Title, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	if _, err := buf.WriteTo(w); err != nil {
+		logging.FromContext(r.Context()).Error("writing response failed", "feed", feed.Title, "error", err)
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// minAlternations is how many isolated failures on a branch mark a check
+// flaky when no rerun of the same commit was seen flipping.
+const minAlternations = 2
+
+// FlakyCheck summarises the pass/fail history of one check.
+type FlakyCheck struct {
+	Repository   string
+	Workflow     string
+	Name         string
+	Commits      int     // commits the check passed or failed on
+	Reruns       int     // commits it both failed and passed on
+	Alternations int     // failures between two passes on the same branch
+	Score        float64 // (Reruns + Alternations) / Commits
+	Flaky        bool
+}
+
+// ScorePercent returns Score as a whole percentage.
+func (c FlakyCheck) ScorePercent() int { return int(math.Round(c.Score * 100)) }
+
+type flakyKey struct {
+	repo, workflow, name string
+}
+
+// outcome reduces a conclusion to "pass" or "fail". Cancelled, skipped and
+// neutral runs say nothing about flakiness and return "".
+func outcome(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return "pass"
+	case "failure", "timed_out", "error":
+		return "fail"
+	}
+	return ""
+}
+
+// analyzeFlakiness scores the checks of repo whose conclusion changed
+// without a code change: reruns of one commit that both failed and passed,
+// and single failures between passes on a branch. Checks with neither are
+// omitted.
+func analyzeFlakiness(repo string, history []storage.CheckOutcomes) []FlakyCheck {
+	type commit struct {
+		branch     string
+		pass, fail bool
+		last       string // outcome of the latest run
+	}
+
+	var out []FlakyCheck
+	for _, h := range history {
+		commits := make(map[string]*commit)
+		var order []string
+		for _, s := range h.Samples {
+			o := outcome(s.Conclusion)
+			if o == "" {
+				continue
+			}
+			c, ok := commits[s.CommitSHA]
+			if !ok {
+				c = &commit{branch: s.Branch}
+				commits[s.CommitSHA] = c
+				order = append(order, s.CommitSHA)
+			}
+			c.pass = c.pass || o == "pass"
+			c.fail = c.fail || o == "fail"
+			c.last = o
+		}
+
+		fc := FlakyCheck{Repository: repo, Workflow: h.Workflow, Name: h.Name, Commits: len(order)}
+		byBranch := make(map[string][]string)
+		for _, sha := range order {
+			c := commits[sha]
+			if c.pass && c.fail {
+				fc.Reruns++
+			}
+			byBranch[c.branch] = append(byBranch[c.branch], c.last)
+		}
+		for _, seq := range byBranch {
+			for i := 1; i < len(seq)-1; i++ {
+				if seq[i-1] == "pass" && seq[i] == "fail" && seq[i+1] == "pass" {
+					fc.Alternations++
+				}
+			}
+		}
+		if fc.Reruns+fc.Alternations == 0 {
+			continue
+		}
+		fc.Score = float64(fc.Reruns+fc.Alternations) / float64(fc.Commits)
+		fc.Flaky = fc.Reruns > 0 || fc.Alternations >= minAlternations
+		out = append(out, fc)
+	}
+	return out
+}
+
+// flakyChecks analyses every repo in repos. Flaky checks sort first, then
+// by descending score.
+func (h *Handler) flakyChecks(repos []string) []FlakyCheck {
+	var checks []FlakyCheck
+	for _, repo := range repos {
+		checks = append(checks, analyzeFlakiness(repo, h.storage.CheckOutcomes(repo))...)
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		if checks[i].Flaky != checks[j].Flaky {
+			return checks[i].Flaky
+		}
+		return checks[i].Score > checks[j].Score
+	})
+	return checks
+}
+
+// flakyIndex returns the keys of the checks marked flaky.
+func flakyIndex(checks []FlakyCheck) map[flakyKey]bool {
+	index := make(map[flakyKey]bool)
+	for _, c := range checks {
+		if c.Flaky {
+			index[flakyKey{c.Repository, c.Workflow, c.Name}] = true
+		}
+	}
+	return index
+}
+
+// markFlaky sets Flaky on the failing checks of repo that are known to be flaky.
+func markFlaky(repo string, checks []github.Check, index map[flakyKey]bool) {
+	for i := range checks {
+		c := &checks[i]
+		if github.ClassifyCheck(c.Status, c.Conclusion) == "failure" && index[flakyKey{repo, c.Workflow, c.Name}] {
+			c.Flaky = true
+		}
+	}
+}
+
+func (h *Handler) FlakyChecks(w http.ResponseWriter, r *http.Request) {
+	settings, _ := h.visibleSettings(r, h.settings())
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	repos := settings.RepoNames
+	if repo != "" {
+		repos = []string{repo}
+	}
+
+	checks := h.flakyChecks(repos)
+	_, _, lastUpdate := h.storage.LastFetchTimes()
+	logging.FromContext(r.Context()).Debug("serving flaky checks", "repo", repo, "checks", len(checks))
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Repo          string
+		RepoNames     []string
+		Checks        []FlakyCheck
+		LastUpdate    time.Time
+		StaleRepoList []string
+	}{
+		PageID:       "builds",
+		Organization: settings.Organization,
+		Version:      h.version,
+		Repo:         repo,
+		RepoNames:    settings.RepoNames,
+		Checks:       checks,
+		LastUpdate:   lastUpdate,
+	}
+
+	renderTemplate(w, r, h.flakyTemplate, "base", data)
+}
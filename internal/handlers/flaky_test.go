@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+func outcomes(name string, samples ...storage.OutcomeSample) storage.CheckOutcomes {
+	return storage.CheckOutcomes{Workflow: "CI", Name: name, Samples: samples}
+}
+
+func sample(branch, sha, conclusion string) storage.OutcomeSample {
+	return storage.OutcomeSample{Branch: branch, CommitSHA: sha, Conclusion: conclusion}
+}
+
+func TestAnalyzeFlakiness(t *testing.T) {
+	history := []storage.CheckOutcomes{
+		// Failed, then passed on rerun of the same commit.
+		outcomes("build",
+			sample("develop", "a", "success"),
+			sample("develop", "b", "failure"),
+			sample("develop", "b", "success"),
+			sample("develop", "c", "success"),
+		),
+		// Two isolated failures on develop.
+		outcomes("test",
+			sample("develop", "a", "success"),
+			sample("develop", "b", "failure"),
+			sample("develop", "c", "success"),
+			sample("develop", "d", "timed_out"),
+			sample("develop", "e", "success"),
+		),
+		// One isolated failure: suspicious but not flaky yet. The failure on
+		// the PR branch does not sit between develop's passes.
+		outcomes("docs",
+			sample("develop", "a", "success"),
+			sample("feature", "x", "failure"),
+			sample("develop", "b", "failure"),
+			sample("develop", "c", "success"),
+			sample("develop", "d", "cancelled"),
+		),
+		// Broken, then fixed.
+		outcomes("lint",
+			sample("develop", "a", "success"),
+			sample("develop", "b", "failure"),
+			sample("develop", "c", "failure"),
+		),
+	}
+
+	got := analyzeFlakiness("eccodes", history)
+	if len(got) != 3 {
+		t.Fatalf("got %d checks, want build, test and docs: %+v", len(got), got)
+	}
+	byName := make(map[string]FlakyCheck)
+	for _, c := range got {
+		byName[c.Name] = c
+	}
+
+	build := byName["build"]
+	if !build.Flaky || build.Reruns != 1 || build.Commits != 3 || build.ScorePercent() != 33 {
+		t.Errorf("build = %+v, want flaky with one rerun over 3 commits", build)
+	}
+	test := byName["test"]
+	if !test.Flaky || test.Alternations != 2 || test.Reruns != 0 {
+		t.Errorf("test = %+v, want flaky with two alternations", test)
+	}
+	docs := byName["docs"]
+	if docs.Flaky || docs.Alternations != 1 || docs.Commits != 4 {
+		t.Errorf("docs = %+v, want one alternation over 4 commits, not flaky", docs)
+	}
+}
+
+func TestMarkFlaky(t *testing.T) {
+	index := map[flakyKey]bool{{"eccodes", "CI", "build"}: true}
+	checks := []github.Check{
+		{Name: "build", Workflow: "CI", Status: "completed", Conclusion: "failure"},
+		{Name: "build", Workflow: "CI", Status: "in_progress"},
+		{Name: "test", Workflow: "CI", Status: "completed", Conclusion: "failure"},
+	}
+	markFlaky("eccodes", checks, index)
+	if !checks[0].Flaky || checks[1].Flaky || checks[2].Flaky {
+		t.Errorf("flaky = %v %v %v, want only the failing build", checks[0].Flaky, checks[1].Flaky, checks[2].Flaky)
+	}
+	markFlaky("atlas", checks[2:], index)
+	if checks[2].Flaky {
+		t.Error("check of another repo marked flaky")
+	}
+}
+
+func TestFlakyHandler(t *testing.T) {
+	h, store := newTestHandler(t)
+	run := func(sha, conclusion string) {
+		store.MergeBranchChecks([]github.BranchCheck{{
+			Repository: "eccodes",
+			Branch:     "develop",
+			CommitSHA:  sha,
+			Checks: []github.Check{
+				{Name: "build", Workflow: "CI", Status: "completed", Conclusion: conclusion, RunAttempt: 1},
+			},
+		}}, nil, []string{"eccodes"})
+	}
+	run("a", "success")
+	run("b", "success")
+	run("b", "failure") // a rerun of b failed
+	run("c", "failure")
+
+	rec := httptest.NewRecorder()
+	h.FlakyChecks(rec, httptest.NewRequest(http.MethodGet, "/flaky", nil))
+	assertResponse(t, rec, http.StatusOK, "Flaky Checks", "eccodes", `class="flaky-row"`, "33%")
+
+	rec = httptest.NewRecorder()
+	h.FlakyChecks(rec, httptest.NewRequest(http.MethodGet, "/flaky?repo=atlas", nil))
+	assertResponse(t, rec, http.StatusOK, "No flaky checks detected")
+
+	rec = httptest.NewRecorder()
+	h.BuildStatus(rec, httptest.NewRequest(http.MethodGet, "/builds", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, `<span class="detail-flaky">flaky</span>`) || !strings.Contains(body, "failure (flaky)") {
+		t.Error("failing flaky check not marked on /builds")
+	}
+
+	store.SetPullRequests([]github.PullRequest{{
+		Repository: "eccodes",
+		Number:     7,
+		Title:      "Fix",
+		State:      "open",
+		Checks:     []github.Check{{Name: "build", Workflow: "CI", Status: "completed", Conclusion: "failure"}},
+	}})
+	rec = httptest.NewRecorder()
+	h.PullRequests(rec, httptest.NewRequest(http.MethodGet, "/pulls", nil))
+	assertResponse(t, rec, http.StatusOK, "failure (flaky)", " flaky\">")
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"html/template"
 	"math/rand/v2"
+	"time"
 )
 
 // affirmations are the celebratory messages shown when all checks pass.
@@ -37,5 +39,34 @@ func TemplateFuncs() template.FuncMap {
 		"affirm": func() string {
 			return affirmations[rand.IntN(len(affirmations))]
 		},
+		"duration": formatDuration,
+		"percent":  formatPercent,
+		"shortSHA": shortSHA,
 	}
 }
+
+// shortSHA abbreviates a commit SHA to the 7 characters GitHub shows.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// formatDuration renders a check duration compactly: 45s, 3m12s, 1h02m.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// formatPercent renders a fraction as a signed percentage: 0.34 → +34%.
+func formatPercent(f float64) string {
+	return fmt.Sprintf("%+.0f%%", f*100)
+}
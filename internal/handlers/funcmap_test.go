@@ -4,11 +4,12 @@ import (
 	"bytes"
 	"html/template"
 	"testing"
+	"time"
 )
 
 func TestTemplateFuncsKeys(t *testing.T) {
 	fm := TemplateFuncs()
-	for _, key := range []string{"add", "mul", "affirm"} {
+	for _, key := range []string{"add", "mul", "affirm", "duration", "percent", "shortSHA"} {
 		if _, ok := fm[key]; !ok {
 			t.Errorf("TemplateFuncs() missing key %q", key)
 		}
@@ -50,6 +51,36 @@ func TestTemplateFuncsMul(t *testing.T) {
 	}
 }
 
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{3*time.Minute + 12*time.Second, "3m12s"},
+		{62*time.Minute + 20*time.Second, "1h02m"},
+		{1500 * time.Millisecond, "2s"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+	if got := formatPercent(0.34); got != "+34%" {
+		t.Errorf("formatPercent(0.34) = %q, want +34%%", got)
+	}
+}
+
+func TestShortSHA(t *testing.T) {
+	if got := shortSHA("abc1234567890"); got != "abc1234" {
+		t.Errorf("shortSHA() = %q, want abc1234", got)
+	}
+	if got := shortSHA("abc"); got != "abc" {
+		t.Errorf("shortSHA(short) = %q, want it unchanged", got)
+	}
+}
+
 func TestTemplateFuncsAffirm(t *testing.T) {
 	fm := TemplateFuncs()
 	affirm := fm["affirm"].(func() string)
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// Layout of the dependency graph, in SVG user units. Upstream repositories
+// sit left of the ones depending on them.
+const (
+	graphNodeWidth  = 160
+	graphNodeHeight = 48
+	graphColumnGap  = 80
+	graphRowGap     = 24
+	graphMargin     = 12
+)
+
+// GraphNode is a repository placed on the dependency graph.
+type GraphNode struct {
+	Name        string
+	X, Y        int
+	Branches    []BranchStatus // in config order; empty without data
+	StatusClass string         // worst branch status
+	Upstream    bool           // a failing branch may be caused upstream
+	Stale       bool
+}
+
+// GraphEdge connects an upstream repository to one depending on it.
+type GraphEdge struct {
+	From, To string
+	Path     string // SVG path data
+	Broken   bool   // From is failing on a branch To fails on too
+}
+
+// upstreamOf returns the transitive dependencies of every configured
+// repository, nearest first. Dependencies on repositories missing from
+// repoConfig (hidden from the viewer) are dropped.
+func upstreamOf(repoConfig []RepoBranches) map[string][]string {
+	deps := make(map[string][]string, len(repoConfig))
+	for _, rc := range repoConfig {
+		deps[rc.Name] = nil
+	}
+	for _, rc := range repoConfig {
+		for _, dep := range rc.DependsOn {
+			if _, ok := deps[dep]; ok && dep != rc.Name {
+				deps[rc.Name] = append(deps[rc.Name], dep)
+			}
+		}
+	}
+
+	upstream := make(map[string][]string, len(deps))
+	for _, rc := range repoConfig {
+		seen := map[string]bool{rc.Name: true}
+		queue := append([]string(nil), deps[rc.Name]...)
+		for len(queue) > 0 {
+			dep := queue[0]
+			queue = queue[1:]
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			upstream[rc.Name] = append(upstream[rc.Name], dep)
+			queue = append(queue, deps[dep]...)
+		}
+	}
+	return upstream
+}
+
+// markUpstreamFailures sets UpstreamFailures on every failing branch whose
+// repository depends, directly or not, on a repository failing on the
+// branch of the same name.
+func markUpstreamFailures(repos []*RepositoryStatus, repoConfig []RepoBranches) {
+	failing := make(map[branchKey]bool)
+	for _, rs := range repos {
+		for _, bs := range rs.Branches {
+			if bs.StatusClass == "status-failure" {
+				failing[branchKey{rs.Name, bs.Branch}] = true
+			}
+		}
+	}
+
+	upstream := upstreamOf(repoConfig)
+	for _, rs := range repos {
+		for i := range rs.Branches {
+			bs := &rs.Branches[i]
+			bs.UpstreamFailures = nil
+			if bs.StatusClass != "status-failure" {
+				continue
+			}
+			for _, dep := range upstream[rs.Name] {
+				if failing[branchKey{dep, bs.Branch}] {
+					bs.UpstreamFailures = append(bs.UpstreamFailures, dep)
+				}
+			}
+		}
+	}
+}
+
+// statusRank orders branch statuses from best to worst, for colouring a
+// repository by its worst branch.
+var statusRank = map[string]int{
+	"status-neutral": 0,
+	"status-success": 1,
+	"status-warning": 2,
+	"status-running": 3,
+	"status-failure": 4,
+}
+
+// layoutGraph places the configured repositories in columns by their
+// longest dependency chain, each column in config order, and routes an
+// edge from every dependency to its dependent.
+func layoutGraph(repoConfig []RepoBranches, statuses []*RepositoryStatus) (nodes []GraphNode, edges []GraphEdge, width, height int) {
+	byName := make(map[string]*RepositoryStatus, len(statuses))
+	for _, rs := range statuses {
+		byName[rs.Name] = rs
+	}
+	byConfig := make(map[string]RepoBranches, len(repoConfig))
+	for _, rc := range repoConfig {
+		byConfig[rc.Name] = rc
+	}
+
+	// Validation rejects cycles, so following dependencies terminates.
+	column := make(map[string]int, len(repoConfig))
+	var depth func(rc RepoBranches) int
+	depth = func(rc RepoBranches) int {
+		if c, ok := column[rc.Name]; ok {
+			return c
+		}
+		c := 0
+		for _, dep := range rc.DependsOn {
+			if up, ok := byConfig[dep]; ok && dep != rc.Name {
+				c = max(c, depth(up)+1)
+			}
+		}
+		column[rc.Name] = c
+		return c
+	}
+
+	rows := make(map[int]int)
+	index := make(map[string]int, len(repoConfig))
+	columns, maxRows := 0, 0
+	for _, rc := range repoConfig {
+		if _, dup := index[rc.Name]; dup {
+			continue
+		}
+		c := depth(rc)
+		n := GraphNode{
+			Name:        rc.Name,
+			X:           graphMargin + c*(graphNodeWidth+graphColumnGap),
+			Y:           graphMargin + rows[c]*(graphNodeHeight+graphRowGap),
+			StatusClass: "status-neutral",
+		}
+		rows[c]++
+		columns = max(columns, c+1)
+		maxRows = max(maxRows, rows[c])
+		if rs, ok := byName[rc.Name]; ok {
+			n.Branches = rs.Branches
+			n.Stale = rs.Stale
+			for _, bs := range rs.Branches {
+				if statusRank[bs.StatusClass] > statusRank[n.StatusClass] {
+					n.StatusClass = bs.StatusClass
+				}
+				if len(bs.UpstreamFailures) > 0 {
+					n.Upstream = true
+				}
+			}
+		}
+		index[rc.Name] = len(nodes)
+		nodes = append(nodes, n)
+	}
+
+	linked := make(map[string]bool, len(nodes))
+	for _, rc := range repoConfig {
+		if linked[rc.Name] {
+			continue
+		}
+		linked[rc.Name] = true
+		to := nodes[index[rc.Name]]
+		for _, dep := range rc.DependsOn {
+			i, ok := index[dep]
+			if !ok || dep == rc.Name {
+				continue
+			}
+			from := nodes[i]
+			edges = append(edges, GraphEdge{
+				From:   dep,
+				To:     rc.Name,
+				Path:   edgePath(from, to),
+				Broken: blames(to, dep),
+			})
+		}
+	}
+
+	width = 2*graphMargin + columns*graphNodeWidth + max(columns-1, 0)*graphColumnGap
+	height = 2*graphMargin + maxRows*graphNodeHeight + max(maxRows-1, 0)*graphRowGap
+	return nodes, edges, width, height
+}
+
+// edgePath draws a curve from the right side of from to the left side of to.
+func edgePath(from, to GraphNode) string {
+	x1, y1 := from.X+graphNodeWidth, from.Y+graphNodeHeight/2
+	x2, y2 := to.X, to.Y+graphNodeHeight/2
+	mid := (x1 + x2) / 2
+	return fmt.Sprintf("M%d %d C%d %d, %d %d, %d %d", x1, y1, mid, y1, mid, y2, x2, y2)
+}
+
+// blames reports whether any branch of n is marked as possibly broken by dep.
+func blames(n GraphNode, dep string) bool {
+	for _, bs := range n.Branches {
+		for _, up := range bs.UpstreamFailures {
+			if up == dep {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DependencyGraph renders the configured repository dependencies coloured
+// by build status.
+func (h *Handler) DependencyGraph(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
+	branchChecks, lastUpdate := h.storage.GetBranchChecks()
+	branchChecks = keepVisible(branchChecks, func(bc github.BranchCheck) string { return bc.Repository }, allowed)
+	logging.FromContext(r.Context()).Debug("serving dependency graph", "branch_checks", len(branchChecks))
+
+	repositories := groupByRepository(branchChecks, settings.RepoConfig)
+	markUpstreamFailures(repositories, settings.RepoConfig)
+
+	staleMap, staleList := h.computeStaleness(storage.CategoryChecks, settings.FetchIntervals.Actions, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
+	for _, rs := range repositories {
+		rs.Stale = staleMap[rs.Name]
+	}
+
+	nodes, edges, width, height := layoutGraph(settings.RepoConfig, repositories)
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Nodes         []GraphNode
+		Edges         []GraphEdge
+		Width         int
+		Height        int
+		NodeWidth     int
+		NodeHeight    int
+		LastUpdate    time.Time
+		StaleRepoList []string
+	}{
+		PageID:        "builds",
+		Organization:  settings.Organization,
+		Version:       h.version,
+		Nodes:         nodes,
+		Edges:         edges,
+		Width:         width,
+		Height:        height,
+		NodeWidth:     graphNodeWidth,
+		NodeHeight:    graphNodeHeight,
+		LastUpdate:    lastUpdate,
+		StaleRepoList: staleList,
+	}
+
+	renderTemplate(w, r, h.graphTemplate, "base", data)
+}
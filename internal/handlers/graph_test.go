@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+// stack is eckit → metkit → fdb, with fdb also depending on eckit directly
+// and multio on fdb.
+var stack = []RepoBranches{
+	{Name: "eckit", Branches: []string{"master", "develop"}},
+	{Name: "metkit", Branches: []string{"master", "develop"}, DependsOn: []string{"eckit"}},
+	{Name: "fdb", Branches: []string{"master", "develop"}, DependsOn: []string{"metkit", "eckit"}},
+	{Name: "multio", Branches: []string{"develop"}, DependsOn: []string{"fdb"}},
+}
+
+func repoStatus(name string, statusByBranch map[string]string, branches ...string) *RepositoryStatus {
+	rs := &RepositoryStatus{Name: name}
+	for _, b := range branches {
+		rs.Branches = append(rs.Branches, BranchStatus{Branch: b, StatusClass: statusByBranch[b]})
+	}
+	return rs
+}
+
+func TestUpstreamOf(t *testing.T) {
+	got := upstreamOf(stack)
+	want := map[string][]string{
+		"metkit": {"eckit"},
+		"fdb":    {"metkit", "eckit"},
+		"multio": {"fdb", "metkit", "eckit"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("upstreamOf() = %v, want %v", got, want)
+	}
+
+	// Hidden repositories break the chain rather than leak.
+	visible := []RepoBranches{stack[0], stack[3]}
+	if got := upstreamOf(visible); len(got["multio"]) != 0 {
+		t.Errorf("upstreamOf() through hidden repos = %v, want none", got["multio"])
+	}
+}
+
+func TestMarkUpstreamFailures(t *testing.T) {
+	repos := []*RepositoryStatus{
+		repoStatus("eckit", map[string]string{"develop": "status-failure", "master": "status-success"}, "master", "develop"),
+		repoStatus("metkit", map[string]string{"develop": "status-success", "master": "status-failure"}, "master", "develop"),
+		repoStatus("fdb", map[string]string{"develop": "status-failure", "master": "status-success"}, "master", "develop"),
+		repoStatus("multio", map[string]string{"develop": "status-failure"}, "develop"),
+	}
+	markUpstreamFailures(repos, stack)
+
+	tests := []struct {
+		repo, branch string
+		want         []string
+	}{
+		{"eckit", "develop", nil},
+		{"metkit", "master", nil},             // eckit master passes
+		{"fdb", "develop", []string{"eckit"}}, // metkit develop passes
+		{"fdb", "master", nil},                // fdb master passes itself
+		{"multio", "develop", []string{"fdb", "eckit"}},
+	}
+	for _, tt := range tests {
+		for _, rs := range repos {
+			if rs.Name != tt.repo {
+				continue
+			}
+			for _, bs := range rs.Branches {
+				if bs.Branch == tt.branch && !reflect.DeepEqual(bs.UpstreamFailures, tt.want) {
+					t.Errorf("%s %s: UpstreamFailures = %v, want %v", tt.repo, tt.branch, bs.UpstreamFailures, tt.want)
+				}
+			}
+		}
+	}
+}
+
+func TestLayoutGraph(t *testing.T) {
+	repos := []*RepositoryStatus{
+		repoStatus("eckit", map[string]string{"develop": "status-failure", "master": "status-success"}, "master", "develop"),
+		repoStatus("fdb", map[string]string{"develop": "status-failure", "master": "status-running"}, "master", "develop"),
+	}
+	markUpstreamFailures(repos, stack)
+	nodes, edges, width, height := layoutGraph(stack, repos)
+
+	columns := map[string]int{"eckit": 0, "metkit": 1, "fdb": 2, "multio": 3}
+	if len(nodes) != len(columns) {
+		t.Fatalf("got %d nodes, want %d", len(nodes), len(columns))
+	}
+	for _, n := range nodes {
+		if want := graphMargin + columns[n.Name]*(graphNodeWidth+graphColumnGap); n.X != want {
+			t.Errorf("%s: X = %d, want %d (column %d)", n.Name, n.X, want, columns[n.Name])
+		}
+	}
+	if nodes[0].StatusClass != "status-failure" || nodes[1].StatusClass != "status-neutral" {
+		t.Errorf("status classes = %q, %q; want worst branch, neutral without data", nodes[0].StatusClass, nodes[1].StatusClass)
+	}
+	if !nodes[2].Upstream {
+		t.Error("fdb should be marked as possibly broken upstream")
+	}
+	if width != 2*graphMargin+4*graphNodeWidth+3*graphColumnGap || height != 2*graphMargin+graphNodeHeight {
+		t.Errorf("size = %dx%d", width, height)
+	}
+
+	broken := map[string]bool{}
+	for _, e := range edges {
+		broken[e.From+">"+e.To] = e.Broken
+	}
+	want := map[string]bool{"eckit>metkit": false, "metkit>fdb": false, "eckit>fdb": true, "fdb>multio": false}
+	if !reflect.DeepEqual(broken, want) {
+		t.Errorf("edges = %v, want %v", broken, want)
+	}
+}
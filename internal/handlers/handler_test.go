@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"context"
+	"encoding/xml"
+	"fmt"
 	"html/template"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ozaq/ecmwf-dash/internal/auth"
 	"github.com/ozaq/ecmwf-dash/internal/github"
 	"github.com/ozaq/ecmwf-dash/internal/storage"
 )
@@ -47,29 +52,101 @@ func newTestHandler(t *testing.T) (*Handler, *storage.Memory) {
 	if err != nil {
 		t.Fatalf("parse dashboard template: %v", err)
 	}
+	kioskTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "kiosk_tokens.html"))
+	if err != nil {
+		t.Fatalf("parse kiosk tokens template: %v", err)
+	}
+
+	trendsTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "trends.html"))
+	if err != nil {
+		t.Fatalf("parse trends template: %v", err)
+	}
+	flakyTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "flaky.html"))
+	if err != nil {
+		t.Fatalf("parse flaky template: %v", err)
+	}
+	checkTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "check.html"))
+	if err != nil {
+		t.Fatalf("parse check template: %v", err)
+	}
+	graphTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "graph.html"))
+	if err != nil {
+		t.Fatalf("parse graph template: %v", err)
+	}
+	branchesTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "branches.html"))
+	if err != nil {
+		t.Fatalf("parse branches template: %v", err)
+	}
+
+	releaseTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "release.html"))
+	if err != nil {
+		t.Fatalf("parse release template: %v", err)
+	}
+
+	repoTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "repo.html"))
+	if err != nil {
+		t.Fatalf("parse repo template: %v", err)
+	}
+
+	planningTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "planning.html"))
+	if err != nil {
+		t.Fatalf("parse planning template: %v", err)
+	}
+
+	queueTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "queue.html"))
+	if err != nil {
+		t.Fatalf("parse queue template: %v", err)
+	}
+
+	deploymentsTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "deployments.html"))
+	if err != nil {
+		t.Fatalf("parse deployments template: %v", err)
+	}
+
+	releasesTmpl, err := template.New("base.html").Funcs(testFuncs).ParseFiles(basePath, filepath.Join(dir, "releases.html"))
+	if err != nil {
+		t.Fatalf("parse releases template: %v", err)
+	}
 
 	store := storage.New()
 	repoNames := []string{"eccodes", "atlas"}
 	repoConfig := []RepoBranches{
-		{Name: "eccodes", Branches: []string{"master", "develop"}},
-		{Name: "atlas", Branches: []string{"main", "develop"}},
+		{Name: "eccodes", Branches: []string{"master", "develop"}, ReleaseBranch: "master"},
+		{Name: "atlas", Branches: []string{"main", "develop"}, DependsOn: []string{"eccodes"}, ReleaseBranch: "main"},
 	}
 	intervals := FetchIntervals{
 		Issues:       5 * time.Minute,
 		PullRequests: 5 * time.Minute,
 		Actions:      5 * time.Minute,
+		Branches:     time.Hour,
+		Releases:     30 * time.Minute,
+		Planning:     30 * time.Minute,
+		Deployments:  15 * time.Minute,
 	}
 	h := New(HandlerConfig{
-		Store:          store,
-		IssuesTmpl:     issuesTmpl,
-		PRsTmpl:        prsTmpl,
-		BuildTmpl:      buildsTmpl,
-		DashboardTmpl:  dashboardTmpl,
-		Organization:   "ecmwf",
-		Version:        "test",
-		RepoNames:      repoNames,
-		RepoConfig:     repoConfig,
-		FetchIntervals: intervals,
+		Store:           store,
+		IssuesTmpl:      issuesTmpl,
+		PRsTmpl:         prsTmpl,
+		BuildTmpl:       buildsTmpl,
+		DashboardTmpl:   dashboardTmpl,
+		KioskTmpl:       kioskTmpl,
+		TrendsTmpl:      trendsTmpl,
+		FlakyTmpl:       flakyTmpl,
+		CheckTmpl:       checkTmpl,
+		GraphTmpl:       graphTmpl,
+		BranchesTmpl:    branchesTmpl,
+		ReleaseTmpl:     releaseTmpl,
+		RepoTmpl:        repoTmpl,
+		PlanningTmpl:    planningTmpl,
+		QueueTmpl:       queueTmpl,
+		DeploymentsTmpl: deploymentsTmpl,
+		ReleasesTmpl:    releasesTmpl,
+		Organization:    "ecmwf",
+		Version:         "test",
+		RepoNames:       repoNames,
+		RepoConfig:      repoConfig,
+		FetchIntervals:  intervals,
+		Trends:          TrendSettings{Runs: 20, RegressionThreshold: 0.25},
 	})
 	return h, store
 }
@@ -114,6 +191,50 @@ func TestDashboardHandler(t *testing.T) {
 
 		assertResponse(t, rec, http.StatusOK, "eccodes")
 	})
+
+	t.Run("linked_prs", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		store.SetIssues([]github.Issue{
+			{Repository: "eccodes", Number: 42, Title: "Fix grib decoder", Author: "alice", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Repository: "atlas", Number: 7, Title: "Add mesh support", Author: "bob", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		})
+		store.SetPullRequests([]github.PullRequest{
+			{Repository: "eccodes", Number: 100, Title: "Decoder fix", URL: "#pr100", ClosingIssues: []github.IssueRef{{Repository: "eccodes", Number: 42, URL: "#"}}},
+			{Repository: "atlas", Number: 9, Title: "Cross-repo fix", URL: "#pr9", ClosingIssues: []github.IssueRef{{Repository: "eccodes", Number: 42, URL: "#"}}},
+		})
+
+		rec := httptest.NewRecorder()
+		h.Dashboard(rec, httptest.NewRequest(http.MethodGet, "/issues", nil))
+		assertResponse(t, rec, http.StatusOK, "PR open #100", "PR open atlas#9", `name="pr"`)
+
+		rec = httptest.NewRecorder()
+		h.Dashboard(rec, httptest.NewRequest(http.MethodGet, "/issues?pr=open", nil))
+		assertResponse(t, rec, http.StatusOK, "Fix grib decoder", `value="open" selected`, "pr=open")
+		if strings.Contains(rec.Body.String(), "Add mesh support") {
+			t.Error("pr=open should hide issues without an open PR")
+		}
+
+		rec = httptest.NewRecorder()
+		h.Dashboard(rec, httptest.NewRequest(http.MethodGet, "/issues?pr=none", nil))
+		assertResponse(t, rec, http.StatusOK, "Add mesh support")
+		if strings.Contains(rec.Body.String(), "Fix grib decoder") {
+			t.Error("pr=none should hide issues with an open PR")
+		}
+	})
+
+	t.Run("kiosk_scope", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/builds-dashboard", nil)
+		req = req.WithContext(auth.WithKiosk(req.Context(), auth.Kiosk{Name: "atlas-room", Repos: []string{"atlas"}}))
+
+		h.BuildsDashboard(rec, req)
+
+		assertResponse(t, rec, http.StatusOK, "atlas")
+		if strings.Contains(rec.Body.String(), "eccodes") {
+			t.Error("kiosk scoped to atlas should not see eccodes")
+		}
+	})
 }
 
 func TestPullRequestsHandler(t *testing.T) {
@@ -151,6 +272,40 @@ func TestPullRequestsHandler(t *testing.T) {
 
 		assertResponse(t, rec, http.StatusOK, "Refactor decoder", "eccodes")
 	})
+
+	t.Run("matrix_checks", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		store.SetPullRequests([]github.PullRequest{{
+			Repository: "eccodes", Number: 101, Title: "Matrix", Author: "alice", URL: "#",
+			CreatedAt: time.Now(), UpdatedAt: time.Now(), BaseBranch: "develop",
+			Checks: []github.Check{
+				{Name: "build (ubuntu)", Status: "completed", Conclusion: "success", URL: "#", Workflow: "CI", RunID: 1},
+				{Name: "build (macos)", Status: "completed", Conclusion: "failure", URL: "#", Workflow: "CI", RunID: 1},
+				{Name: "lint", Status: "completed", Conclusion: "success", URL: "#", Workflow: "CI", RunID: 1},
+			},
+			ChecksSuccess: 2, ChecksFailure: 1,
+		}})
+
+		rec := httptest.NewRecorder()
+		h.PullRequests(rec, httptest.NewRequest(http.MethodGet, "/pulls", nil))
+
+		assertResponse(t, rec, http.StatusOK, "CI / build ×2: 1 passed, 1 failed", "CI / lint: success")
+	})
+
+	t.Run("closing_issues", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		store.SetPullRequests([]github.PullRequest{{
+			Repository: "eccodes", Number: 100, Title: "Decoder fix", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now(),
+			ClosingIssues: []github.IssueRef{
+				{Repository: "eccodes", Number: 42, URL: "https://github.com/ecmwf/eccodes/issues/42"},
+				{Repository: "atlas", Number: 7, URL: "https://github.com/ecmwf/atlas/issues/7"},
+			},
+		}})
+
+		rec := httptest.NewRecorder()
+		h.PullRequests(rec, httptest.NewRequest(http.MethodGet, "/pulls", nil))
+		assertResponse(t, rec, http.StatusOK, "Closes", `href="https://github.com/ecmwf/eccodes/issues/42"`, ">#42</a>", ">atlas#7</a>")
+	})
 }
 
 func TestBuildStatusHandler(t *testing.T) {
@@ -188,6 +343,667 @@ func TestBuildStatusHandler(t *testing.T) {
 
 		assertResponse(t, rec, http.StatusOK, "eccodes")
 	})
+
+	t.Run("workflow_groups", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		store.SetBranchChecks([]github.BranchCheck{{
+			Repository: "eccodes",
+			Branch:     "develop",
+			CommitSHA:  "def456",
+			Checks: []github.Check{
+				{Name: "build (ubuntu, gcc-12)", Status: "completed", Conclusion: "success", URL: "#", Workflow: "CI", RunID: 1, RunAttempt: 2, Event: "push"},
+				{Name: "build (macos, clang)", Status: "completed", Conclusion: "failure", URL: "#", Workflow: "CI", RunID: 1, RunAttempt: 2, Event: "push"},
+				{Name: "docs", Status: "completed", Conclusion: "success", URL: "#", Workflow: "Docs", RunID: 2, Event: "push"},
+			},
+		}})
+
+		rec := httptest.NewRecorder()
+		h.BuildStatus(rec, httptest.NewRequest(http.MethodGet, "/builds", nil))
+
+		assertResponse(t, rec, http.StatusOK, "CI", "attempt 2", "2 variants, 1 failed", "build (macos, clang)", "CI / build (ubuntu, gcc-12): success")
+		if strings.Contains(rec.Body.String(), `detail-workflow-name">Docs`) {
+			t.Error("passing workflow should not be listed in the details")
+		}
+	})
+
+	t.Run("required_checks", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		store.SetBranchChecks([]github.BranchCheck{{
+			Repository: "eccodes",
+			Branch:     "develop",
+			CommitSHA:  "def456",
+			Checks: []github.Check{
+				{Name: "test", Status: "completed", Conclusion: "success", URL: "#", Workflow: "CI", Required: true},
+				{Name: "lint", Status: "completed", Conclusion: "failure", URL: "#", Workflow: "CI"},
+			},
+			RequiredChecks:  []string{"docs/readthedocs.org:eccodes", "test"},
+			MissingRequired: []string{"docs/readthedocs.org:eccodes"},
+		}})
+
+		rec := httptest.NewRecorder()
+		h.BuildStatus(rec, httptest.NewRequest(http.MethodGet, "/builds", nil))
+
+		assertResponse(t, rec, http.StatusOK, "0 failed", "1 optional failed", "1 required missing",
+			"docs/readthedocs.org:eccodes", "required check missing", "CI / test: success (required)", "CI / lint: failure (optional)")
+	})
+
+	t.Run("scheduled_workflows", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		created := time.Date(2026, 5, 4, 2, 0, 0, 0, time.UTC)
+		store.MergeScheduledWorkflows([]github.ScheduledWorkflow{
+			{Repository: "atlas", File: "nightly.yml", Name: "Atlas nightly", URL: "#", Runs: []github.WorkflowRun{
+				{Status: "completed", Conclusion: "success", Event: "schedule", URL: "#", CreatedAt: created},
+			}},
+			{Repository: "eccodes", File: "regression.yml", Name: "Regression", URL: "#", FailureStreak: 3, Runs: []github.WorkflowRun{
+				{Status: "completed", Conclusion: "failure", Event: "schedule", URL: "#", CreatedAt: created},
+			}},
+			{Repository: "eccodes", File: "stress.yml", Name: "stress.yml", URL: "#", FetchFailed: true},
+		}, nil)
+
+		rec := httptest.NewRecorder()
+		h.BuildStatus(rec, httptest.NewRequest(http.MethodGet, "/builds", nil))
+		assertResponse(t, rec, http.StatusOK, "Scheduled workflows", "failing for 3 runs", "May 4, 02:00", "Runs could not be fetched")
+		body := rec.Body.String()
+		if strings.Index(body, "Regression") > strings.Index(body, "Atlas nightly") {
+			t.Error("scheduled workflows should follow config order (eccodes before atlas)")
+		}
+
+		rec = httptest.NewRecorder()
+		h.BuildStatus(rec, httptest.NewRequest(http.MethodGet, "/builds?repo=atlas", nil))
+		if strings.Contains(rec.Body.String(), "Regression") {
+			t.Error("repo filter should hide other repos' scheduled workflows")
+		}
+	})
+
+	t.Run("upstream_failures", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		failing := []github.Check{{Name: "ci", Status: "completed", Conclusion: "failure", URL: "#"}}
+		store.SetBranchChecks([]github.BranchCheck{
+			{Repository: "eccodes", Branch: "develop", CommitSHA: "abc123", Checks: failing},
+			{Repository: "atlas", Branch: "develop", CommitSHA: "def456", Checks: failing},
+			{Repository: "atlas", Branch: "main", CommitSHA: "789abc", Checks: failing},
+		})
+
+		rec := httptest.NewRecorder()
+		h.BuildStatus(rec, httptest.NewRequest(http.MethodGet, "/builds?repo=atlas", nil))
+		assertResponse(t, rec, http.StatusOK, "Possibly caused by upstream", `href="builds?repo=eccodes"`)
+		if n := strings.Count(rec.Body.String(), "Possibly caused by upstream"); n != 1 {
+			t.Errorf("upstream note shown %d times, want once (develop only)", n)
+		}
+
+		rec = httptest.NewRecorder()
+		h.BuildsDashboard(rec, httptest.NewRequest(http.MethodGet, "/builds-dashboard", nil))
+		assertResponse(t, rec, http.StatusOK, "Possibly caused by upstream: eccodes")
+
+		rec = httptest.NewRecorder()
+		h.DependencyGraph(rec, httptest.NewRequest(http.MethodGet, "/graph", nil))
+		assertResponse(t, rec, http.StatusOK, "graph-edge-broken", "atlas depends on eccodes", "upstream?")
+	})
+}
+
+func TestBranchDivergenceHandler(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.BranchDivergence(rec, httptest.NewRequest(http.MethodGet, "/branches", nil))
+		assertResponse(t, rec, http.StatusOK, "No branch comparisons available")
+	})
+
+	t.Run("with_data", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		store.MergeBranchComparisons([]github.BranchComparison{
+			{Repository: "eccodes", Base: "master", Head: "develop", URL: "#", AheadBy: 3, MergeBaseSHA: "abcdef1234567", MergeBaseAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+			{Repository: "atlas", Base: "main", Head: "develop", URL: "#", AheadBy: 40, BehindBy: 2},
+			{Repository: "atlas", Base: "main", Head: "release", URL: "#", FetchFailed: true},
+		}, nil, []string{"eccodes", "atlas"})
+
+		rec := httptest.NewRecorder()
+		h.BranchDivergence(rec, httptest.NewRequest(http.MethodGet, "/branches", nil))
+		assertResponse(t, rec, http.StatusOK, "main has 2 commits missing from develop", "Behind their base: 1", "Mar 2, 2026", "abcdef1", "Could not be compared")
+		body := rec.Body.String()
+		if strings.Index(body, "main...develop") > strings.Index(body, "master...develop") {
+			t.Error("most diverged pair should come first")
+		}
+
+		rec = httptest.NewRecorder()
+		h.BranchDivergence(rec, httptest.NewRequest(http.MethodGet, "/branches?repo=eccodes", nil))
+		if strings.Contains(rec.Body.String(), "main...develop") {
+			t.Error("repo filter should hide other repos' comparisons")
+		}
+	})
+}
+
+func releaseRequest(name string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/repo/"+name+"/release", nil)
+	req.SetPathValue("name", name)
+	return req
+}
+
+func TestRepoReleaseHandler(t *testing.T) {
+	t.Run("unknown_repo", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.RepoRelease(rec, releaseRequest("fdb"))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("loading", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.RepoRelease(rec, releaseRequest("eccodes"))
+		assertResponse(t, rec, http.StatusOK, "Release data is still loading", "release branch <code>master</code>", "No build data yet")
+	})
+
+	t.Run("no_release_data", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		h.repoConfig = append(h.repoConfig, RepoBranches{Name: "fdb", Branches: []string{"develop", "main"}, ReleaseBranch: "main"})
+		store.SetPullRequests([]github.PullRequest{
+			{Repository: "fdb", Number: 4, Title: "Fix for main", URL: "#", BaseBranch: "main"},
+		})
+		rec := httptest.NewRecorder()
+		h.RepoRelease(rec, releaseRequest("fdb"))
+		assertResponse(t, rec, http.StatusOK, "release branch <code>main</code>", "Fix for main")
+	})
+
+	t.Run("with_data", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		store.MergeReleaseInfo([]github.ReleaseInfo{{
+			Repository:      "eccodes",
+			Branch:          "master",
+			LatestRelease:   &github.Release{Tag: "2.39.0", URL: "#", PublishedAt: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)},
+			LatestTag:       "2.39.0",
+			SinceTag:        "2.39.0",
+			CommitsSinceTag: 12,
+			Milestone:       &github.Milestone{Number: 3, Title: "2.40.0", URL: "#", OpenIssues: 1, ClosedIssues: 3},
+		}}, nil, []string{"eccodes"})
+		store.SetPullRequests([]github.PullRequest{
+			{Repository: "eccodes", Number: 7, Title: "Hotfix for master", URL: "#", BaseBranch: "master"},
+			{Repository: "eccodes", Number: 8, Title: "Feature for develop", URL: "#", BaseBranch: "develop"},
+			{Repository: "atlas", Number: 9, Title: "Atlas into main", URL: "#", BaseBranch: "main"},
+		})
+		store.SetIssues([]github.Issue{
+			{Repository: "eccodes", Number: 1, Title: "Planned for 2.40", URL: "#", MilestoneNumber: 3},
+			{Repository: "eccodes", Number: 2, Title: "Unplanned", URL: "#"},
+		})
+		store.SetBranchChecks([]github.BranchCheck{
+			{Repository: "eccodes", Branch: "develop", Checks: []github.Check{{Name: "ci", Status: "completed", Conclusion: "failure", URL: "#"}}},
+		})
+
+		rec := httptest.NewRecorder()
+		h.RepoRelease(rec, releaseRequest("eccodes"))
+		assertResponse(t, rec, http.StatusOK, "2.39.0", "Feb 10, 2026", "12 commits", "3 closed, 1 open (75%)",
+			"Hotfix for master", "Planned for 2.40", "1 failed", `href="../../static/builds.css"`)
+		body := rec.Body.String()
+		for _, hidden := range []string{"Feature for develop", "Atlas into main", "Unplanned"} {
+			if strings.Contains(body, hidden) {
+				t.Errorf("body should not contain %q", hidden)
+			}
+		}
+	})
+}
+
+func repoRequest(name string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/repo/"+name, nil)
+	req.SetPathValue("name", name)
+	return req
+}
+
+func TestRepoDetailHandler(t *testing.T) {
+	t.Run("unknown_repo", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.RepoDetail(rec, repoRequest("fdb"))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.RepoDetail(rec, repoRequest("atlas"))
+		assertResponse(t, rec, http.StatusOK, "No build data yet", "Not fetched yet", `href="eccodes"`, `href="atlas/release"`, `href="../static/base.css"`)
+	})
+
+	t.Run("with_data", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		now := time.Now()
+		store.SetIssues([]github.Issue{
+			{Repository: "eccodes", Number: 1, Title: "Crash on GRIB2", URL: "#", Author: "outsider", IsExternal: true, UpdatedAt: now.Add(-time.Hour), Labels: []github.Label{{Name: "bug"}}},
+			{Repository: "eccodes", Number: 2, Title: "Another bug", URL: "#", Author: "alice", UpdatedAt: now, Labels: []github.Label{{Name: "bug"}, {Name: "grib2"}}},
+			{Repository: "eccodes", Number: 3, Title: "Unlabelled", URL: "#", Author: "alice", UpdatedAt: now},
+			{Repository: "atlas", Number: 4, Title: "Atlas issue", URL: "#", Author: "bob", IsExternal: true, UpdatedAt: now},
+		})
+		store.SetPullRequests([]github.PullRequest{
+			{Repository: "eccodes", Number: 10, Title: "Fix decoder", URL: "#", Author: "contributor", IsExternal: true, ReviewStatus: "approved", UpdatedAt: now},
+			{Repository: "eccodes", Number: 11, URL: "#", ReviewStatus: "changes_requested"},
+			{Repository: "eccodes", Number: 12, URL: "#", Draft: true, ReviewStatus: "pending"},
+		})
+		store.SetBranchChecks([]github.BranchCheck{
+			{Repository: "eccodes", Branch: "develop", CommitSHA: "abcdef1234567", CommitURL: "#", Checks: []github.Check{{ID: 42, Name: "unit-tests", Status: "completed", Conclusion: "failure", URL: "#"}}},
+		})
+
+		rec := httptest.NewRecorder()
+		h.RepoDetail(rec, repoRequest("eccodes"))
+		assertResponse(t, rec, http.StatusOK,
+			"abcdef1", `href="../builds/check?repo=eccodes&amp;id=42"`,
+			"Open pull requests</a> (3)", "<span class=\"count-success\">1</span> approved", "<span class=\"count-zero\">1</span> draft",
+			"Open issues</a> (3)", "No label 1",
+			"2 open issues and pull requests from 2 contributors", "Fix decoder", "Crash on GRIB2")
+		body := rec.Body.String()
+		if strings.Contains(body, "Atlas issue") {
+			t.Error("other repositories' issues should not be shown")
+		}
+		if strings.Index(body, "Fix decoder") > strings.Index(body, "Crash on GRIB2") {
+			t.Error("most recently updated external activity should come first")
+		}
+		if strings.Index(body, ">bug</span> 2") < 0 {
+			t.Error("bug label should be counted twice")
+		}
+	})
+}
+
+func TestRepoQueueHandler(t *testing.T) {
+	queueRequest := func(name string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/repo/"+name+"/queue", nil)
+		req.SetPathValue("name", name)
+		return req
+	}
+
+	t.Run("unknown_repo", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.RepoQueue(rec, queueRequest("fdb"))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.RepoQueue(rec, queueRequest("eccodes"))
+		assertResponse(t, rec, http.StatusOK, "No pull requests are queued", "Auto-merge enabled (0)")
+	})
+
+	t.Run("with_data", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		enqueued := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+		store.SetPullRequests([]github.PullRequest{
+			{Repository: "eccodes", Number: 11, Title: "Second in line", URL: "#", BaseBranch: "develop", ChecksRunning: 2,
+				MergeQueue: &github.MergeQueueEntry{Position: 2, State: "AWAITING_CHECKS", EnqueuedAt: enqueued}},
+			{Repository: "eccodes", Number: 10, Title: "First in line", URL: "#", BaseBranch: "develop", ChecksSuccess: 5,
+				MergeQueue: &github.MergeQueueEntry{Position: 1, State: "MERGEABLE", EnqueuedAt: enqueued}},
+			{Repository: "eccodes", Number: 12, Title: "Waiting for review", URL: "#", BaseBranch: "develop",
+				AutoMerge: &github.AutoMerge{EnabledBy: "alice", MergeMethod: "squash"}},
+			{Repository: "atlas", Number: 13, Title: "Other repo", URL: "#", BaseBranch: "develop",
+				MergeQueue: &github.MergeQueueEntry{Position: 1, State: "QUEUED"}},
+		})
+
+		rec := httptest.NewRecorder()
+		h.RepoQueue(rec, queueRequest("eccodes"))
+		assertResponse(t, rec, http.StatusOK, "Merge queue for <code>develop</code> (2)", "awaiting checks", "5 passed",
+			"Auto-merge enabled (1)", "Waiting for review", "enabled by alice")
+		body := rec.Body.String()
+		if strings.Index(body, "First in line") > strings.Index(body, "Second in line") {
+			t.Error("queued PRs should be in queue order")
+		}
+		if strings.Contains(body, "Other repo") {
+			t.Error("queue of eccodes should not list atlas PRs")
+		}
+
+		rec = httptest.NewRecorder()
+		h.PullRequests(rec, httptest.NewRequest(http.MethodGet, "/pulls", nil))
+		assertResponse(t, rec, http.StatusOK, `href="repo/eccodes/queue"`, "Queued #1", "Queued #2", "Auto-merge")
+	})
+}
+
+func TestMergeQueues(t *testing.T) {
+	prs := []github.PullRequest{
+		{Number: 1, BaseBranch: "master", MergeQueue: &github.MergeQueueEntry{Position: 1}},
+		{Number: 2, BaseBranch: "develop", MergeQueue: &github.MergeQueueEntry{Position: 2}},
+		{Number: 3, BaseBranch: "develop"},
+		{Number: 4, BaseBranch: "develop", MergeQueue: &github.MergeQueueEntry{Position: 1}},
+	}
+	var got []string
+	for _, q := range mergeQueues(prs) {
+		for _, pr := range q.PullRequests {
+			got = append(got, fmt.Sprintf("%s:%d", q.Branch, pr.Number))
+		}
+	}
+	if want := []string{"develop:4", "develop:2", "master:1"}; !slices.Equal(got, want) {
+		t.Errorf("mergeQueues = %v, want %v", got, want)
+	}
+}
+
+func TestDeploymentsHandler(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.Deployments(rec, httptest.NewRequest(http.MethodGet, "/deployments", nil))
+		assertResponse(t, rec, http.StatusOK, "No deployments found", `class="active" aria-current="page">Deployments`)
+	})
+
+	t.Run("with_data", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		deployed := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+		store.MergeDeployments([]github.Environment{
+			{Repository: "eccodes", Name: "docs", Deployments: []github.Deployment{
+				{ID: 2, Ref: "develop", SHA: "abcdef1234567", State: "success", UpdatedAt: deployed, URL: "https://docs.example/eccodes"},
+				{ID: 1, Ref: "develop", State: "failure", Description: "sphinx error", UpdatedAt: deployed.Add(-time.Hour)},
+			}},
+			{Repository: "eccodes", Name: "pypi", Deployments: []github.Deployment{{ID: 3, Ref: "2.40.0", State: "failure", UpdatedAt: deployed}}},
+			{Repository: "atlas", Name: "docs", Deployments: []github.Deployment{{ID: 4, Ref: "master", State: "in_progress", UpdatedAt: deployed}}},
+		}, nil, []string{"eccodes", "atlas"})
+
+		rec := httptest.NewRecorder()
+		h.Deployments(rec, httptest.NewRequest(http.MethodGet, "/deployments", nil))
+		assertResponse(t, rec, http.StatusOK, `<th scope="col">docs</th><th scope="col">pypi</th>`,
+			"deploy-dot status-success", "deploy-dot status-failure", "deploy-dot status-running",
+			`href="deployments?repo=eccodes&amp;environment=docs"`, "&mdash;")
+		body := rec.Body.String()
+		if strings.Index(body, ">eccodes</a>") > strings.Index(body, ">atlas</a>") {
+			t.Error("rows should follow config order")
+		}
+		if strings.Contains(body, "latest deployments") {
+			t.Error("no history without a picked environment")
+		}
+
+		rec = httptest.NewRecorder()
+		h.Deployments(rec, httptest.NewRequest(http.MethodGet, "/deployments?repo=eccodes&environment=docs", nil))
+		assertResponse(t, rec, http.StatusOK, "eccodes · docs: latest deployments", "sphinx error", "abcdef1", `href="https://docs.example/eccodes"`)
+		if strings.Contains(rec.Body.String(), ">atlas</a>") {
+			t.Error("repo filter should hide atlas")
+		}
+	})
+}
+
+func TestDeploymentMatrix(t *testing.T) {
+	envs := []github.Environment{
+		{Repository: "atlas", Name: "staging"},
+		{Repository: "eccodes", Name: "docs"},
+		{Repository: "atlas", Name: "docs"},
+	}
+	columns, rows := deploymentMatrix(envs, []string{"eccodes", "fdb", "atlas"})
+	if want := []string{"docs", "staging"}; !slices.Equal(columns, want) {
+		t.Errorf("columns = %v, want %v", columns, want)
+	}
+	if len(rows) != 2 || rows[0].Repo != "eccodes" || rows[1].Repo != "atlas" {
+		t.Fatalf("rows = %+v, want eccodes then atlas", rows)
+	}
+	if rows[0].Cells[0] == nil || rows[0].Cells[1] != nil || rows[1].Cells[1].Name != "staging" {
+		t.Errorf("cells = %+v, %+v", rows[0].Cells, rows[1].Cells)
+	}
+}
+
+// seedReleases stores two eccodes releases around one of atlas.
+func seedReleases(store *storage.Memory) {
+	day := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store.MergeReleaseInfo([]github.ReleaseInfo{
+		{Repository: "eccodes", Releases: []github.Release{
+			{Repository: "eccodes", Tag: "2.40.0-rc1", URL: "https://github.com/ecmwf/eccodes/releases/tag/2.40.0-rc1", Author: "alice", PublishedAt: day.AddDate(0, 0, 2), Prerelease: true},
+			{Repository: "eccodes", Tag: "2.39.0", Name: "ecCodes 2.39.0", URL: "https://github.com/ecmwf/eccodes/releases/tag/2.39.0", PublishedAt: day,
+				Assets: []github.Asset{{Name: "eccodes-2.39.0-Source.tar.gz", Downloads: 120}, {Name: "eccodes-2.39.0-Source.zip", Downloads: 30}}},
+		}},
+		{Repository: "atlas", Releases: []github.Release{
+			{Repository: "atlas", Tag: "0.40.0", URL: "https://github.com/ecmwf/atlas/releases/tag/0.40.0", Author: "bob", PublishedAt: day.AddDate(0, 0, 1)},
+		}},
+	}, nil, []string{"eccodes", "atlas"})
+}
+
+func TestReleasesHandler(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.Releases(rec, httptest.NewRequest(http.MethodGet, "/releases", nil))
+		assertResponse(t, rec, http.StatusOK, "No releases found", `class="active" aria-current="page">Releases`, `href="releases.atom"`)
+	})
+
+	t.Run("with_data", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		seedReleases(store)
+
+		rec := httptest.NewRecorder()
+		h.Releases(rec, httptest.NewRequest(http.MethodGet, "/releases", nil))
+		assertResponse(t, rec, http.StatusOK, "Releases: 3", "pre-release", "150 across 2 assets", "eccodes-2.39.0-Source.zip: 30", "ecCodes 2.39.0")
+		body := rec.Body.String()
+		rc, atlas, stable := strings.Index(body, "<code>2.40.0-rc1</code>"), strings.Index(body, "<code>0.40.0</code>"), strings.Index(body, "<code>2.39.0</code>")
+		if rc > atlas || atlas > stable {
+			t.Error("releases should be listed newest first across repositories")
+		}
+
+		rec = httptest.NewRecorder()
+		h.Releases(rec, httptest.NewRequest(http.MethodGet, "/releases?repo=atlas", nil))
+		assertResponse(t, rec, http.StatusOK, "Releases: 1", `href="releases.atom?repo=atlas"`)
+	})
+}
+
+func TestReleasesFeed(t *testing.T) {
+	h, store := newTestHandler(t)
+	seedReleases(store)
+
+	rec := httptest.NewRecorder()
+	h.ReleasesFeed(rec, httptest.NewRequest(http.MethodGet, "http://dash.example/releases.atom?repo=eccodes&"+auth.KioskParam+"=secret", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if strings.Contains(rec.Body.String(), "secret") {
+		t.Error("feed leaks the kiosk token")
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("parse feed: %v", err)
+	}
+	if feed.ID != "http://dash.example/releases.atom?repo=eccodes" || feed.Title != "eccodes releases" {
+		t.Errorf("feed id %q, title %q", feed.ID, feed.Title)
+	}
+	if feed.Updated != "2026-10-03T12:00:00Z" {
+		t.Errorf("feed updated = %q, want the newest release", feed.Updated)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("got %d entries, want the 2 eccodes releases", len(feed.Entries))
+	}
+	rc := feed.Entries[0]
+	if rc.ID != "https://github.com/ecmwf/eccodes/releases/tag/2.40.0-rc1" || rc.Title != "eccodes 2.40.0-rc1 (pre-release)" || rc.Author == nil || rc.Author.Name != "alice" {
+		t.Errorf("first entry = %+v", rc)
+	}
+	if got := feed.Entries[1].Summary; got != "ecCodes 2.39.0. Assets: eccodes-2.39.0-Source.tar.gz, eccodes-2.39.0-Source.zip" {
+		t.Errorf("summary = %q", got)
+	}
+
+	// A kiosk token scoped to atlas only gets atlas releases.
+	req := httptest.NewRequest(http.MethodGet, "/releases.atom", nil)
+	req = req.WithContext(auth.WithKiosk(req.Context(), auth.Kiosk{Name: "atlas-room", Repos: []string{"atlas"}}))
+	rec = httptest.NewRecorder()
+	h.ReleasesFeed(rec, req)
+	if body := rec.Body.String(); !strings.Contains(body, "0.40.0") || strings.Contains(body, "2.39.0") {
+		t.Error("kiosk feed shows releases outside the token's repositories")
+	}
+}
+
+// seedEvents fetches everything twice so the store notices an opened
+// issue and pull request, a merge and a failing build in eccodes and an
+// opened issue in atlas. It returns when they were opened: after the first
+// fetch, or they would count as reopened.
+func seedEvents(store *storage.Memory) time.Time {
+	opened := time.Now().UTC().Truncate(time.Second).Add(time.Minute)
+	repos := []string{"eccodes", "atlas"}
+	passing := []github.BranchCheck{{Repository: "eccodes", Branch: "develop", CommitSHA: "aaaaaaa1", CommitURL: "https://github.com/ecmwf/eccodes/commit/aaaaaaa1",
+		Checks: []github.Check{{Name: "ci", Status: "completed", Conclusion: "success"}}}}
+	store.MergeIssues(nil, nil, repos)
+	store.MergePullRequests([]github.PullRequest{{Repository: "eccodes", Number: 7, Title: "Old PR", URL: "https://github.com/ecmwf/eccodes/pull/7", Author: "carol", BaseBranch: "develop"}}, nil, repos)
+	store.MergeBranchChecks(passing, nil, repos)
+
+	store.MergeIssues([]github.Issue{
+		{Repository: "eccodes", Number: 8, Title: "GRIB2 decoding crash", URL: "https://github.com/ecmwf/eccodes/issues/8", Author: "alice", CreatedAt: opened, Labels: []github.Label{{Name: "bug"}}},
+		{Repository: "atlas", Number: 3, Title: "Atlas mesh question", URL: "https://github.com/ecmwf/atlas/issues/3", Author: "dave", CreatedAt: opened},
+	}, nil, repos)
+	store.MergePullRequests([]github.PullRequest{{Repository: "eccodes", Number: 9, Title: "Fix decoding crash", URL: "https://github.com/ecmwf/eccodes/pull/9", Author: "bob", BaseBranch: "develop", CreatedAt: opened.Add(time.Hour)}}, nil, repos)
+	store.RecordMergedPullRequests([]github.PullRequest{{Repository: "eccodes", Number: 7, Title: "Old PR", URL: "https://github.com/ecmwf/eccodes/pull/7", Author: "carol", BaseBranch: "develop", MergedAt: opened.Add(2 * time.Hour)}})
+	failing := []github.BranchCheck{{Repository: "eccodes", Branch: "develop", CommitSHA: "bbbbbbb2", CommitURL: "https://github.com/ecmwf/eccodes/commit/bbbbbbb2", UpdatedAt: opened.Add(3 * time.Hour),
+		Checks: []github.Check{{Name: "ci", Status: "completed", Conclusion: "failure"}}}}
+	store.MergeBranchChecks(failing, nil, repos)
+	return opened
+}
+
+func TestEventFeeds(t *testing.T) {
+	h, store := newTestHandler(t)
+	opened := seedEvents(store)
+
+	get := func(handler http.HandlerFunc, target string) atomFeed {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if ct := rec.Header().Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+			t.Errorf("%s: Content-Type = %q", target, ct)
+		}
+		var feed atomFeed
+		if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+			t.Fatalf("%s: parse feed: %v", target, err)
+		}
+		return feed
+	}
+
+	issues := get(h.IssuesFeed, "/issues.atom")
+	if issues.Title != "ecmwf issues" || len(issues.Entries) != 2 {
+		t.Fatalf("issues feed %q has %d entries, want 2", issues.Title, len(issues.Entries))
+	}
+	if e := issues.Entries[0]; e.ID != "https://github.com/ecmwf/eccodes/issues/8" && e.ID != "https://github.com/ecmwf/atlas/issues/3" {
+		t.Errorf("issue entry id = %q", e.ID)
+	}
+	issues = get(h.IssuesFeed, "/issues.atom?repo=eccodes")
+	if len(issues.Entries) != 1 {
+		t.Fatalf("eccodes issues feed has %d entries, want 1", len(issues.Entries))
+	}
+	e := issues.Entries[0]
+	if e.Title != "eccodes#8 opened: GRIB2 decoding crash" || e.Updated != atomTime(opened) || e.Author == nil || e.Author.Name != "alice" || len(e.Categories) != 2 || e.Categories[1].Term != "bug" {
+		t.Errorf("issue entry = %+v", e)
+	}
+
+	pulls := get(h.PullRequestsFeed, "/pulls.atom")
+	if len(pulls.Entries) != 2 {
+		t.Fatalf("pull requests feed has %d entries, want opened and merged", len(pulls.Entries))
+	}
+	if e := pulls.Entries[0]; e.ID != "https://github.com/ecmwf/eccodes/pull/7#merged" || e.Title != "eccodes#7 merged: Old PR" || e.Updated != atomTime(opened.Add(2*time.Hour)) {
+		t.Errorf("merged entry = %+v", e)
+	}
+	if e := pulls.Entries[1]; e.ID != "https://github.com/ecmwf/eccodes/pull/9" || e.Summary != "Pull request into develop opened by bob." {
+		t.Errorf("opened entry = %+v", e)
+	}
+	if pulls.Updated != atomTime(opened.Add(2*time.Hour)) {
+		t.Errorf("feed updated = %q, want the newest entry", pulls.Updated)
+	}
+
+	builds := get(h.BuildsFeed, "/builds.atom?repo=eccodes")
+	if len(builds.Entries) != 1 {
+		t.Fatalf("builds feed has %d entries, want 1", len(builds.Entries))
+	}
+	if e := builds.Entries[0]; e.ID != "https://github.com/ecmwf/eccodes/commit/bbbbbbb2#develop:failure@"+e.Updated || e.Title != "eccodes develop is failing" || !strings.Contains(e.Summary, "bbbbbbb") {
+		t.Errorf("build entry = %+v", e)
+	}
+	if builds := get(h.BuildsFeed, "/builds.atom?repo=atlas"); len(builds.Entries) != 0 || builds.Updated == "" {
+		t.Errorf("atlas builds feed = %+v, want no entries but an updated time", builds)
+	}
+
+	// As on /issues, pr keeps issues with or without an open pull request.
+	store.SetPullRequests([]github.PullRequest{{Repository: "eccodes", Number: 9, URL: "https://github.com/ecmwf/eccodes/pull/9",
+		ClosingIssues: []github.IssueRef{{Repository: "eccodes", Number: 8}}}})
+	if issues := get(h.IssuesFeed, "/issues.atom?pr=open"); len(issues.Entries) != 1 || issues.Entries[0].ID != "https://github.com/ecmwf/eccodes/issues/8" {
+		t.Errorf("pr=open feed = %+v, want eccodes#8 only", issues.Entries)
+	}
+	if issues := get(h.IssuesFeed, "/issues.atom?pr=none"); len(issues.Entries) != 1 || issues.Entries[0].ID != "https://github.com/ecmwf/atlas/issues/3" {
+		t.Errorf("pr=none feed = %+v, want atlas#3 only", issues.Entries)
+	}
+
+	// Parameters feeds cannot apply are rejected.
+	rec := httptest.NewRecorder()
+	h.PullRequestsFeed(rec, httptest.NewRequest(http.MethodGet, "/pulls.atom?repo=eccodes&pr=open", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"pr"`) {
+		t.Errorf("pr on pulls feed: status %d, body %q; want 400 naming it", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.Dashboard(rec, httptest.NewRequest(http.MethodGet, "/issues?repo=eccodes", nil))
+	assertResponse(t, rec, http.StatusOK, `href="issues.atom?repo=eccodes"`)
+	rec = httptest.NewRecorder()
+	h.Dashboard(rec, httptest.NewRequest(http.MethodGet, "/issues?repo=eccodes&pr=open", nil))
+	assertResponse(t, rec, http.StatusOK, `href="issues.atom?repo=eccodes&amp;pr=open"`)
+}
+
+func TestSummarizeReviews(t *testing.T) {
+	got := summarizeReviews([]github.PullRequest{
+		{ReviewStatus: "approved"},
+		{ReviewStatus: "approved", Draft: true},
+		{ReviewStatus: "changes_requested"},
+		{ReviewStatus: "pending"},
+		{},
+	})
+	want := ReviewSummary{Total: 5, Draft: 1, Approved: 1, ChangesRequested: 1, Pending: 2}
+	if got != want {
+		t.Errorf("summarizeReviews() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlanningHandler(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		rec := httptest.NewRecorder()
+		h.Planning(rec, httptest.NewRequest(http.MethodGet, "/planning", nil))
+		assertResponse(t, rec, http.StatusOK, "No open milestones; data may still be loading", `class="active" aria-current="page">Planning`)
+	})
+
+	t.Run("with_data", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		store.MergePlanning(
+			[]github.Milestone{
+				{Repository: "atlas", Number: 1, Title: "0.41", URL: "#", OpenIssues: 1, ClosedIssues: 1, DueOn: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{Repository: "eccodes", Number: 3, Title: "2.40.0", URL: "#", OpenIssues: 2, ClosedIssues: 6},
+			},
+			[]github.ProjectBoard{
+				{Number: 7, Title: "Roadmap", URL: "#", Columns: []string{"Todo", "Done"}, Items: []github.ProjectItem{
+					{Repository: "eccodes", Kind: "issue", Number: 12, Title: "GRIB3 support", URL: "#", Status: "Todo"},
+					{Repository: "atlas", Kind: "pull", Number: 5, Title: "Mesh rewrite", URL: "#", Status: "Removed column"},
+					{Kind: "draft", Title: "Plan 2027"},
+				}},
+				{Number: 8, FetchFailed: true, URL: "#"},
+			},
+			nil, []string{"eccodes", "atlas"})
+
+		rec := httptest.NewRecorder()
+		h.Planning(rec, httptest.NewRequest(http.MethodGet, "/planning", nil))
+		assertResponse(t, rec, http.StatusOK, `value="75"`, "6/8 closed (75%)", "overdue since", "No due date",
+			"GRIB3 support", "No status", "Mesh rewrite", "Plan 2027", "Project 8", "could not be fetched")
+		body := rec.Body.String()
+		if strings.Index(body, ">eccodes</a>") > strings.Index(body, ">atlas</a>") {
+			t.Error("milestones should follow config order")
+		}
+
+		rec = httptest.NewRecorder()
+		h.Planning(rec, httptest.NewRequest(http.MethodGet, "/planning?repo=eccodes", nil))
+		body = rec.Body.String()
+		for _, hidden := range []string{"0.41", "Mesh rewrite", "Plan 2027"} {
+			if strings.Contains(body, hidden) {
+				t.Errorf("repo filter should hide %q", hidden)
+			}
+		}
+	})
+}
+
+func TestLayoutBoard(t *testing.T) {
+	board := github.ProjectBoard{Columns: []string{"Todo", "Doing", "Done"}, Items: []github.ProjectItem{
+		{Title: "a", Status: "Done"}, {Title: "b"}, {Title: "c", Status: "Todo"}, {Title: "d", Status: "Done"},
+	}}
+	view := layoutBoard(board, func(item github.ProjectItem) bool { return item.Title != "d" })
+	var got []string
+	for _, col := range view.Columns {
+		got = append(got, fmt.Sprintf("%s:%d", col.Name, len(col.Items)))
+	}
+	if want := []string{"Todo:1", "Doing:0", "Done:1", "No status:1"}; !slices.Equal(got, want) {
+		t.Errorf("columns = %v, want %v", got, want)
+	}
 }
 
 func TestBuildsDashboardHandler(t *testing.T) {
@@ -220,6 +1036,29 @@ func TestBuildsDashboardHandler(t *testing.T) {
 
 		assertResponse(t, rec, http.StatusOK, "eccodes")
 	})
+
+	t.Run("kiosk_scope_hides_upstream", func(t *testing.T) {
+		h, store := newTestHandler(t)
+		failing := []github.Check{{Name: "ci", Status: "completed", Conclusion: "failure", URL: "#"}}
+		store.SetBranchChecks([]github.BranchCheck{
+			{Repository: "eccodes", Branch: "develop", CommitSHA: "abc123", Checks: failing},
+			{Repository: "atlas", Branch: "develop", CommitSHA: "def456", Checks: failing},
+		})
+
+		rec := httptest.NewRecorder()
+		h.BuildsDashboard(rec, httptest.NewRequest(http.MethodGet, "/builds-dashboard", nil))
+		assertResponse(t, rec, http.StatusOK, "Possibly caused by upstream: eccodes")
+
+		// atlas depends on eccodes, which a screen scoped to atlas must not name.
+		rec = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/builds-dashboard", nil)
+		req = req.WithContext(auth.WithKiosk(req.Context(), auth.Kiosk{Name: "atlas-room", Repos: []string{"atlas"}}))
+		h.BuildsDashboard(rec, req)
+		assertResponse(t, rec, http.StatusOK, "atlas")
+		if strings.Contains(rec.Body.String(), "eccodes") {
+			t.Error("kiosk scoped to atlas names eccodes as an upstream cause")
+		}
+	})
 }
 
 func TestDashboardHandlerStaleness(t *testing.T) {
@@ -433,3 +1272,202 @@ func assertResponse(t *testing.T, rec *httptest.ResponseRecorder, wantStatus int
 		}
 	}
 }
+
+func TestUpdateSettings(t *testing.T) {
+	h, store := newTestHandler(t)
+	store.SetIssues([]github.Issue{
+		{Repository: "eccodes", Number: 1, Title: "Eccodes issue", Author: "a", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{Repository: "fdb", Number: 2, Title: "FDB issue", Author: "b", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	})
+
+	// fdb is not configured yet, so the repo filter ignores it.
+	rec := httptest.NewRecorder()
+	h.Dashboard(rec, httptest.NewRequest(http.MethodGet, "/issues?repo=fdb", nil))
+	assertResponse(t, rec, http.StatusOK, "Eccodes issue", "FDB issue")
+
+	h.UpdateSettings(RepoSettings{
+		Organization:   "ecmwf",
+		RepoNames:      []string{"eccodes", "fdb"},
+		RepoConfig:     []RepoBranches{{Name: "eccodes", Branches: []string{"master"}}, {Name: "fdb", Branches: []string{"develop"}}},
+		FetchIntervals: h.settings().FetchIntervals,
+	})
+
+	rec = httptest.NewRecorder()
+	h.Dashboard(rec, httptest.NewRequest(http.MethodGet, "/issues?repo=fdb", nil))
+	assertResponse(t, rec, http.StatusOK, "FDB issue")
+	if strings.Contains(rec.Body.String(), "Eccodes issue") {
+		t.Error("filter by newly configured repo should exclude other repos")
+	}
+
+	rec = httptest.NewRecorder()
+	h.BuildsDashboard(rec, httptest.NewRequest(http.MethodGet, "/builds-dashboard", nil))
+	assertResponse(t, rec, http.StatusOK, "fdb")
+	if strings.Contains(rec.Body.String(), "atlas") {
+		t.Error("removed repo atlas should no longer appear on the dashboard")
+	}
+}
+
+type fakeKiosks []auth.KioskStatus
+
+func (f fakeKiosks) KioskStatus() []auth.KioskStatus { return f }
+
+func TestKioskTokensHandler(t *testing.T) {
+	h, _ := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.KioskTokens(rec, httptest.NewRequest(http.MethodGet, "/kiosk-tokens", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("without auth: status = %d, want 404", rec.Code)
+	}
+
+	h.kiosks = fakeKiosks{
+		{Name: "lobby"},
+		{Name: "atlas-room", Repos: []string{"atlas"}, LastUsed: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+	}
+	rec = httptest.NewRecorder()
+	h.KioskTokens(rec, httptest.NewRequest(http.MethodGet, "/kiosk-tokens", nil))
+	assertResponse(t, rec, http.StatusOK, "lobby", "Not since restart", "atlas-room", "Mar 1, 09:30:00 UTC")
+}
+
+// fakeVisibility hides the listed repositories from every viewer.
+type fakeVisibility []string
+
+func (f fakeVisibility) Visible(_ context.Context, _ string, repos []string) map[string]bool {
+	visible := make(map[string]bool)
+	for _, r := range repos {
+		visible[r] = !slices.Contains(f, r)
+	}
+	return visible
+}
+
+// Public treats the listed repositories as private.
+func (f fakeVisibility) Public(ctx context.Context, org string, repos []string) map[string]bool {
+	return f.Visible(ctx, org, repos)
+}
+
+func TestFeedsHidePrivateReposFromKiosks(t *testing.T) {
+	h, store := newTestHandler(t)
+	h.publicRepos = fakeVisibility{"atlas"}
+	seedReleases(store)
+	seedEvents(store)
+
+	kiosk := func(target string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		return req.WithContext(auth.WithKiosk(req.Context(), auth.Kiosk{Name: "wallscreen"}))
+	}
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		target  string
+		hidden  string
+	}{
+		{"releases", h.ReleasesFeed, "/releases.atom", "0.40.0"},
+		{"releases of the repo", h.ReleasesFeed, "/releases.atom?repo=atlas", "0.40.0"},
+		{"issues", h.IssuesFeed, "/issues.atom", "Atlas mesh question"},
+		{"issues of the repo", h.IssuesFeed, "/issues.atom?repo=atlas", "Atlas mesh question"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		tt.handler(rec, kiosk(tt.target))
+		if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), tt.hidden) {
+			t.Errorf("%s: kiosk feed shows private %q (status %d)", tt.name, tt.hidden, rec.Code)
+		}
+	}
+
+	// Signed-in viewers are subject to per-viewer visibility only.
+	rec := httptest.NewRecorder()
+	h.IssuesFeed(rec, httptest.NewRequest(http.MethodGet, "/issues.atom", nil))
+	if !strings.Contains(rec.Body.String(), "Atlas mesh question") {
+		t.Error("feed without a kiosk token hides a repository")
+	}
+}
+
+func TestVisibilityFiltering(t *testing.T) {
+	h, store := newTestHandler(t)
+	h.visibility = fakeVisibility{"atlas"}
+	store.SetIssues([]github.Issue{
+		{Repository: "eccodes", Number: 1, Title: "Eccodes grib bug", Author: "alice", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{Repository: "atlas", Number: 2, Title: "Atlas mesh feature", Author: "bob", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	})
+	store.SetPullRequests([]github.PullRequest{
+		{Repository: "eccodes", Number: 10, Title: "Eccodes decoder refactor", Author: "alice", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now(), BaseBranch: "develop"},
+		{Repository: "atlas", Number: 20, Title: "Atlas grid improvement", Author: "bob", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now(), BaseBranch: "main",
+			ClosingIssues: []github.IssueRef{{Repository: "eccodes", Number: 1, URL: "#"}}},
+		{Repository: "eccodes", Number: 11, Title: "Eccodes fix", Author: "alice", URL: "#", CreatedAt: time.Now(), UpdatedAt: time.Now(), BaseBranch: "develop",
+			ClosingIssues: []github.IssueRef{{Repository: "atlas", Number: 2, URL: "#"}}},
+	})
+
+	store.MergePlanning(nil, []github.ProjectBoard{{Number: 1, Title: "Roadmap", Columns: []string{"Todo"}, Items: []github.ProjectItem{
+		{Repository: "eccodes", Kind: "issue", Title: "Eccodes roadmap item", Status: "Todo"},
+		{Repository: "atlas", Kind: "issue", Title: "Atlas roadmap item", Status: "Todo"},
+		{Kind: "draft", Title: "Draft roadmap item"},
+	}}}, nil, nil)
+
+	store.MergeDeployments([]github.Environment{
+		{Repository: "eccodes", Name: "docs", Deployments: []github.Deployment{{Ref: "eccodes-ref"}}},
+		{Repository: "atlas", Name: "docs", Deployments: []github.Deployment{{Ref: "atlas-ref"}}},
+	}, nil, nil)
+
+	seedReleases(store)
+
+	tests := []struct {
+		path    string
+		handler http.HandlerFunc
+		want    string
+		hidden  string
+	}{
+		{"/issues", h.Dashboard, "Eccodes grib bug", "Atlas mesh feature"},
+		{"/issues?repo=atlas", h.Dashboard, "Eccodes grib bug", "Atlas mesh feature"},
+		{"/pulls", h.PullRequests, "Eccodes decoder refactor", "Atlas grid improvement"},
+		{"/builds", h.BuildStatus, "eccodes", "atlas"},
+		{"/graph", h.DependencyGraph, "eccodes", "atlas"},
+		{"/branches", h.BranchDivergence, "All repositories", "atlas"},
+		{"/planning", h.Planning, "Eccodes roadmap item", "Atlas roadmap item"},
+		{"/deployments", h.Deployments, "eccodes-ref", "atlas-ref"},
+		{"/releases", h.Releases, "2.39.0", "0.40.0"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		tt.handler(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		assertResponse(t, rec, http.StatusOK, tt.want)
+		body := rec.Body.String()
+		if strings.Contains(body, tt.hidden) || strings.Contains(body, `<option value="atlas"`) {
+			t.Errorf("%s: hidden repository atlas is shown", tt.path)
+		}
+	}
+
+	seedEvents(store)
+	for path, handler := range map[string]http.HandlerFunc{"/issues.atom": h.IssuesFeed, "/pulls.atom": h.PullRequestsFeed, "/builds.atom": h.BuildsFeed} {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if strings.Contains(rec.Body.String(), "ecmwf/atlas") {
+			t.Errorf("%s: event of hidden repository atlas is shown", path)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ReleasesFeed(rec, httptest.NewRequest(http.MethodGet, "/releases.atom", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "2.39.0") || strings.Contains(body, "0.40.0") {
+		t.Error("/releases.atom: release of hidden repository atlas is shown")
+	}
+
+	rec = httptest.NewRecorder()
+	h.Dashboard(rec, httptest.NewRequest(http.MethodGet, "/issues", nil))
+	if strings.Contains(rec.Body.String(), "PR open") {
+		t.Error("/issues: pull request of hidden repository atlas is linked")
+	}
+	rec = httptest.NewRecorder()
+	h.PullRequests(rec, httptest.NewRequest(http.MethodGet, "/pulls", nil))
+	if strings.Contains(rec.Body.String(), "Closes") {
+		t.Error("/pulls: issue of hidden repository atlas is linked")
+	}
+
+	for path, handler := range map[string]http.HandlerFunc{"/repo/atlas": h.RepoDetail, "/repo/atlas/release": h.RepoRelease, "/repo/atlas/queue": h.RepoQueue} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.SetPathValue("name", "atlas")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s of hidden repository: status = %d, want 404", path, rec.Code)
+		}
+	}
+}
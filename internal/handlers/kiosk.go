@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ozaq/ecmwf-dash/internal/auth"
+)
+
+// KioskStatusSource lists kiosk tokens for the admin page. It is nil when
+// authentication is disabled.
+type KioskStatusSource interface {
+	KioskStatus() []auth.KioskStatus
+}
+
+// KioskTokens renders the kiosk token admin page. Access control (admins
+// only) is enforced by the auth middleware.
+func (h *Handler) KioskTokens(w http.ResponseWriter, r *http.Request) {
+	if h.kiosks == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	data := struct {
+		PageID        string
+		Version       string
+		Kiosks        []auth.KioskStatus
+		StaleRepoList []string
+	}{
+		PageID:  "kiosk-tokens",
+		Version: h.version,
+		Kiosks:  h.kiosks.KioskStatus(),
+	}
+
+	renderTemplate(w, r, h.kioskTemplate, "base", data)
+}
+
+// restrictToKiosk drops repositories the request's kiosk token is not
+// scoped to. Requests without a kiosk token see everything.
+func restrictToKiosk(r *http.Request, repos []*RepositoryStatus, staleList []string) ([]*RepositoryStatus, []string) {
+	k, ok := auth.KioskFromContext(r.Context())
+	if !ok || len(k.Repos) == 0 {
+		return repos, staleList
+	}
+	var visible []*RepositoryStatus
+	for _, repo := range repos {
+		if k.Allows(repo.Name) {
+			visible = append(visible, repo)
+		}
+	}
+	var stale []string
+	for _, name := range staleList {
+		if k.Allows(name) {
+			stale = append(stale, name)
+		}
+	}
+	return visible, stale
+}
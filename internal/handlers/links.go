@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+// PRLink is an open pull request that closes an issue.
+type PRLink struct {
+	Repository string
+	Number     int
+	URL        string
+	Draft      bool
+}
+
+// IssueRow is an issue on /issues with the open pull requests closing it.
+type IssueRow struct {
+	github.Issue
+	OpenPRs []PRLink
+}
+
+type issueKey struct {
+	repo   string // lower case; GitHub names are case-insensitive
+	number int
+}
+
+// openPRsByIssue indexes the open pull requests by the issues they close.
+func openPRsByIssue(prs []github.PullRequest) map[issueKey][]PRLink {
+	linked := make(map[issueKey][]PRLink)
+	for _, pr := range prs {
+		for _, ref := range pr.ClosingIssues {
+			key := issueKey{strings.ToLower(ref.Repository), ref.Number}
+			linked[key] = append(linked[key], PRLink{Repository: pr.Repository, Number: pr.Number, URL: pr.URL, Draft: pr.Draft})
+		}
+	}
+	return linked
+}
+
+func linkedPRs(linked map[issueKey][]PRLink, issue github.Issue) []PRLink {
+	return linked[issueKey{strings.ToLower(issue.Repository), issue.Number}]
+}
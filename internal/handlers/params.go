@@ -32,6 +32,15 @@ func getNextOrder(current string) string {
 	return "asc"
 }
 
+// sanitizePRFilter accepts "open" (issues with an open pull request closing
+// them) and "none" (issues without one).
+func sanitizePRFilter(filter string) string {
+	if filter == "open" || filter == "none" {
+		return filter
+	}
+	return ""
+}
+
 func sanitizeRepo(repo string, validRepos []string) string {
 	if repo == "" {
 		return ""
@@ -65,6 +65,14 @@ func TestGetNextOrder(t *testing.T) {
 	}
 }
 
+func TestSanitizePRFilter(t *testing.T) {
+	for input, want := range map[string]string{"open": "open", "none": "none", "": "", "OPEN": "", "all": ""} {
+		if got := sanitizePRFilter(input); got != want {
+			t.Errorf("sanitizePRFilter(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
 func TestSanitizeRepo(t *testing.T) {
 	validRepos := []string{"eccodes", "atlas", "fckit"}
 
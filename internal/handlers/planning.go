@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// noStatusColumn collects board items whose status field is unset or
+// names an option that no longer exists.
+const noStatusColumn = "No status"
+
+// RepoMilestones is the open milestones of one repository, soonest due
+// first.
+type RepoMilestones struct {
+	Repo       string
+	Milestones []github.Milestone
+	Stale      bool
+}
+
+// BoardView is a project board laid out in columns.
+type BoardView struct {
+	github.ProjectBoard
+	Columns []BoardColumn
+}
+
+// BoardColumn is one status of a board and the items in it.
+type BoardColumn struct {
+	Name  string
+	Items []github.ProjectItem
+}
+
+// groupMilestones groups milestones by repository in repoNames order,
+// leaving out repositories without open milestones.
+func groupMilestones(milestones []github.Milestone, repoNames []string) []RepoMilestones {
+	byRepo := make(map[string][]github.Milestone)
+	for _, ms := range milestones {
+		byRepo[ms.Repository] = append(byRepo[ms.Repository], ms)
+	}
+	var groups []RepoMilestones
+	for _, name := range repoNames {
+		if ms := byRepo[name]; len(ms) > 0 {
+			groups = append(groups, RepoMilestones{Repo: name, Milestones: ms})
+		}
+	}
+	return groups
+}
+
+// layoutBoard sorts the items of board into its status columns, in board
+// order, followed by noStatusColumn when any item has no known status.
+// Items are kept when keep returns true for them.
+func layoutBoard(board github.ProjectBoard, keep func(github.ProjectItem) bool) BoardView {
+	view := BoardView{ProjectBoard: board}
+	index := make(map[string]int, len(board.Columns))
+	for i, name := range board.Columns {
+		index[name] = i
+		view.Columns = append(view.Columns, BoardColumn{Name: name})
+	}
+	var unsorted []github.ProjectItem
+	for _, item := range board.Items {
+		if !keep(item) {
+			continue
+		}
+		if i, ok := index[item.Status]; ok {
+			view.Columns[i].Items = append(view.Columns[i].Items, item)
+		} else {
+			unsorted = append(unsorted, item)
+		}
+	}
+	if len(unsorted) > 0 {
+		view.Columns = append(view.Columns, BoardColumn{Name: noStatusColumn, Items: unsorted})
+	}
+	return view
+}
+
+// Planning shows the progress of every open milestone and the configured
+// project boards grouped by status.
+func (h *Handler) Planning(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
+	milestones, boards, lastUpdate := h.storage.GetPlanning()
+	milestones = keepVisible(milestones, func(ms github.Milestone) string { return ms.Repository }, allowed)
+
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	repoNames := settings.RepoNames
+	if repo != "" {
+		repoNames = []string{repo}
+	}
+	// Draft issues belong to no repository; they are shown only without
+	// per-viewer filtering and without a repo filter.
+	keepItem := func(item github.ProjectItem) bool {
+		if item.Repository == "" {
+			return h.visibility == nil && repo == ""
+		}
+		if repo != "" {
+			return item.Repository == repo
+		}
+		return allowed(item.Repository)
+	}
+
+	groups := groupMilestones(milestones, repoNames)
+	views := make([]BoardView, 0, len(boards))
+	for _, b := range boards {
+		views = append(views, layoutBoard(b, keepItem))
+	}
+	logging.FromContext(r.Context()).Debug("serving planning", "milestones", len(milestones), "boards", len(boards))
+
+	staleMap, staleList := h.computeStaleness(storage.CategoryPlanning, settings.FetchIntervals.Planning, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
+	for i := range groups {
+		groups[i].Stale = staleMap[groups[i].Repo]
+	}
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Milestones    []RepoMilestones
+		Boards        []BoardView
+		Now           time.Time
+		LastUpdate    time.Time
+		Repo          string
+		RepoNames     []string
+		StaleRepoList []string
+	}{
+		PageID:        "planning",
+		Organization:  settings.Organization,
+		Version:       h.version,
+		Milestones:    groups,
+		Boards:        views,
+		Now:           time.Now(),
+		LastUpdate:    lastUpdate,
+		Repo:          repo,
+		RepoNames:     settings.RepoNames,
+		StaleRepoList: staleList,
+	}
+
+	renderTemplate(w, r, h.planningTemplate, "base", data)
+}
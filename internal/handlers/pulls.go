@@ -1,19 +1,21 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 	"sort"
 	"strconv"
 	"time"
 
 	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
 	"github.com/ozaq/ecmwf-dash/internal/storage"
 )
 
 func (h *Handler) PullRequests(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
 	prs, lastUpdate := h.storage.GetPullRequests()
-	log.Printf("Serving /pulls - PRs: %d", len(prs))
+	prs = keepVisible(prs, func(pr github.PullRequest) string { return pr.Repository }, allowed)
+	logging.FromContext(r.Context()).Debug("serving pull requests", "count", len(prs))
 
 	// Get query params
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
@@ -23,7 +25,7 @@ func (h *Handler) PullRequests(w http.ResponseWriter, r *http.Request) {
 
 	sortBy := sanitizeSort(r.URL.Query().Get("sort"))
 	order := sanitizeOrder(r.URL.Query().Get("order"))
-	repo := sanitizeRepo(r.URL.Query().Get("repo"), h.repoNames)
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
 
 	// Filter by repo
 	if repo != "" {
@@ -45,8 +47,14 @@ func (h *Handler) PullRequests(w http.ResponseWriter, r *http.Request) {
 	if start < len(prs) {
 		pagePRs = prs[start:end]
 	}
+	flaky := flakyIndex(h.flakyChecks(settings.RepoNames))
+	for i := range pagePRs {
+		markFlaky(pagePRs[i].Repository, pagePRs[i].Checks, flaky)
+		pagePRs[i].ClosingIssues = keepVisible(pagePRs[i].ClosingIssues, func(ref github.IssueRef) string { return ref.Repository }, allowed)
+	}
 
-	staleMap, staleList := h.computeStaleness(storage.CategoryPRs, h.fetchIntervals.PullRequests, lastUpdate)
+	staleMap, staleList := h.computeStaleness(storage.CategoryPRs, settings.FetchIntervals.PullRequests, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
 
 	data := struct {
 		PageID        string
@@ -66,7 +74,7 @@ func (h *Handler) PullRequests(w http.ResponseWriter, r *http.Request) {
 		StaleRepoList []string
 	}{
 		PageID:        "pulls",
-		Organization:  h.organization,
+		Organization:  settings.Organization,
 		Version:       h.version,
 		PullRequests:  pagePRs,
 		LastUpdate:    lastUpdate,
@@ -77,12 +85,12 @@ func (h *Handler) PullRequests(w http.ResponseWriter, r *http.Request) {
 		Order:         order,
 		NextOrder:     getNextOrder(order),
 		Repo:          repo,
-		RepoNames:     h.repoNames,
+		RepoNames:     settings.RepoNames,
 		StaleRepos:    staleMap,
 		StaleRepoList: staleList,
 	}
 
-	renderTemplate(w, h.prTemplate, "base", data)
+	renderTemplate(w, r, h.prTemplate, "base", data)
 }
 
 func sortPullRequests(prs []github.PullRequest, sortBy, order string) {
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// MergeQueue is the merge queue of one base branch.
+type MergeQueue struct {
+	Branch       string
+	PullRequests []github.PullRequest // merged next first
+}
+
+// mergeQueues groups the queued pull requests by base branch, sorted by
+// branch name, each in queue order.
+func mergeQueues(prs []github.PullRequest) []MergeQueue {
+	byBranch := make(map[string][]github.PullRequest)
+	for _, pr := range prs {
+		if pr.MergeQueue != nil {
+			byBranch[pr.BaseBranch] = append(byBranch[pr.BaseBranch], pr)
+		}
+	}
+	queues := make([]MergeQueue, 0, len(byBranch))
+	for branch, queued := range byBranch {
+		sort.SliceStable(queued, func(i, j int) bool { return queued[i].MergeQueue.Position < queued[j].MergeQueue.Position })
+		queues = append(queues, MergeQueue{Branch: branch, PullRequests: queued})
+	}
+	sort.Slice(queues, func(i, j int) bool { return queues[i].Branch < queues[j].Branch })
+	return queues
+}
+
+// RepoQueue lists the pull requests in a repository's merge queues, in the
+// order they will be merged, and those waiting to be merged automatically.
+func (h *Handler) RepoQueue(w http.ResponseWriter, r *http.Request) {
+	settings, _ := h.visibleSettings(r, h.settings())
+	rc, ok := findRepoConfig(settings.RepoConfig, r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	repo := rc.Name
+
+	prs, lastUpdate := h.storage.GetPullRequests()
+	prs = keepVisible(prs, func(pr github.PullRequest) string { return pr.Repository }, func(name string) bool { return name == repo })
+	queues := mergeQueues(prs)
+
+	var autoMerge []github.PullRequest
+	for _, pr := range prs {
+		if pr.AutoMerge != nil && pr.MergeQueue == nil {
+			autoMerge = append(autoMerge, pr)
+		}
+	}
+	sort.SliceStable(autoMerge, func(i, j int) bool { return autoMerge[i].Number < autoMerge[j].Number })
+	logging.FromContext(r.Context()).Debug("serving merge queue", "repo", repo, "queues", len(queues), "auto_merge", len(autoMerge))
+
+	staleMap, _ := h.computeStaleness(storage.CategoryPRs, settings.FetchIntervals.PullRequests, lastUpdate)
+	var staleList []string
+	if staleMap[repo] {
+		staleList = []string{repo}
+	}
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Repo          string
+		Queues        []MergeQueue
+		AutoMerge     []github.PullRequest // auto-merge enabled, not queued
+		LastUpdate    time.Time
+		StaleRepoList []string
+	}{
+		PageID:        "pulls",
+		Organization:  settings.Organization,
+		Version:       h.version,
+		Repo:          repo,
+		Queues:        queues,
+		AutoMerge:     autoMerge,
+		LastUpdate:    lastUpdate,
+		StaleRepoList: staleList,
+	}
+
+	renderTemplate(w, r, h.queueTemplate, "base", data)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// findRepoConfig returns the configuration of the repository called name.
+func findRepoConfig(repoConfig []RepoBranches, name string) (RepoBranches, bool) {
+	for _, rc := range repoConfig {
+		if rc.Name == name {
+			return rc, true
+		}
+	}
+	return RepoBranches{}, false
+}
+
+// RepoRelease shows whether a repository is ready for its next release:
+// its latest release and tag, commits since, open pull requests against
+// the release branch, open issues in the current milestone and the build
+// status of its tracked branches.
+func (h *Handler) RepoRelease(w http.ResponseWriter, r *http.Request) {
+	settings, _ := h.visibleSettings(r, h.settings())
+	rc, ok := findRepoConfig(settings.RepoConfig, r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	repo := rc.Name
+	onlyRepo := func(name string) bool { return name == repo }
+
+	infos, lastUpdate := h.storage.GetReleaseInfo()
+	info := github.ReleaseInfo{Repository: repo, Branch: rc.ReleaseBranch}
+	hasInfo := false
+	for _, i := range infos {
+		if i.Repository == repo {
+			info, hasInfo = i, true
+			break
+		}
+	}
+
+	prs, _ := h.storage.GetPullRequests()
+	var releasePRs []github.PullRequest
+	for _, pr := range keepVisible(prs, func(pr github.PullRequest) string { return pr.Repository }, onlyRepo) {
+		if pr.BaseBranch == info.Branch {
+			releasePRs = append(releasePRs, pr)
+		}
+	}
+	sort.SliceStable(releasePRs, func(i, j int) bool { return releasePRs[i].Number < releasePRs[j].Number })
+
+	var milestoneIssues []github.Issue
+	if info.Milestone != nil {
+		issues, _ := h.storage.GetIssues()
+		for _, issue := range keepVisible(issues, func(i github.Issue) string { return i.Repository }, onlyRepo) {
+			if issue.MilestoneNumber == info.Milestone.Number {
+				milestoneIssues = append(milestoneIssues, issue)
+			}
+		}
+		sort.SliceStable(milestoneIssues, func(i, j int) bool { return milestoneIssues[i].Number < milestoneIssues[j].Number })
+	}
+
+	builds := h.repoBuilds(settings, repo)
+	logging.FromContext(r.Context()).Debug("serving release readiness", "repo", repo, "prs", len(releasePRs), "issues", len(milestoneIssues))
+
+	staleMap, _ := h.computeStaleness(storage.CategoryReleases, settings.FetchIntervals.Releases, lastUpdate)
+	var staleList []string
+	if staleMap[repo] {
+		staleList = []string{repo}
+	}
+
+	data := struct {
+		PageID          string
+		Organization    string
+		Version         string
+		Repo            string
+		Info            github.ReleaseInfo
+		HasInfo         bool // false until the first fetch covering repo
+		PullRequests    []github.PullRequest
+		MilestoneIssues []github.Issue
+		Builds          []BranchStatus
+		LastUpdate      time.Time
+		StaleRepoList   []string
+	}{
+		PageID:          "builds",
+		Organization:    settings.Organization,
+		Version:         h.version,
+		Repo:            repo,
+		Info:            info,
+		HasInfo:         hasInfo,
+		PullRequests:    releasePRs,
+		MilestoneIssues: milestoneIssues,
+		Builds:          builds,
+		LastUpdate:      lastUpdate,
+		StaleRepoList:   staleList,
+	}
+
+	renderTemplate(w, r, h.releaseTemplate, "base", data)
+}
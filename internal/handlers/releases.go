@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// releaseFeed collects the recent releases of the repositories allowed,
+// newest first.
+func releaseFeed(infos []github.ReleaseInfo, allowed func(string) bool) []github.Release {
+	var releases []github.Release
+	for _, info := range keepVisible(infos, func(i github.ReleaseInfo) string { return i.Repository }, allowed) {
+		releases = append(releases, info.Releases...)
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		a, b := releases[i], releases[j]
+		if !a.PublishedAt.Equal(b.PublishedAt) {
+			return a.PublishedAt.After(b.PublishedAt)
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return a.Tag < b.Tag
+	})
+	return releases
+}
+
+// filteredReleases returns the releases of the allowed repositories,
+// filtered by r's repo parameter, and that repository.
+func (h *Handler) filteredReleases(r *http.Request, settings RepoSettings, allowed func(string) bool) ([]github.Release, string, time.Time) {
+	infos, lastUpdate := h.storage.GetReleaseInfo()
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	if repo != "" {
+		allowed = func(name string) bool { return name == repo }
+	}
+	return releaseFeed(infos, allowed), repo, lastUpdate
+}
+
+// Releases shows the recent releases of all repositories, newest first,
+// with their download counts.
+func (h *Handler) Releases(w http.ResponseWriter, r *http.Request) {
+	settings, allowed := h.visibleSettings(r, h.settings())
+	releases, repo, lastUpdate := h.filteredReleases(r, settings, allowed)
+	logging.FromContext(r.Context()).Debug("serving releases", "releases", len(releases))
+
+	staleMap, staleList := h.computeStaleness(storage.CategoryReleases, settings.FetchIntervals.Releases, lastUpdate)
+	staleList = keepVisible(staleList, func(name string) string { return name }, allowed)
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Releases      []github.Release
+		LastUpdate    time.Time
+		Repo          string
+		RepoNames     []string
+		StaleRepos    map[string]bool
+		StaleRepoList []string
+	}{
+		PageID:        "releases",
+		Organization:  settings.Organization,
+		Version:       h.version,
+		Releases:      releases,
+		LastUpdate:    lastUpdate,
+		Repo:          repo,
+		RepoNames:     settings.RepoNames,
+		StaleRepos:    staleMap,
+		StaleRepoList: staleList,
+	}
+
+	renderTemplate(w, r, h.releasesTemplate, "base", data)
+}
+
+// ReleasesFeed serves the releases shown on /releases as an Atom feed.
+func (h *Handler) ReleasesFeed(w http.ResponseWriter, r *http.Request) {
+	if !checkFeedParams(w, r) {
+		return
+	}
+	settings, allowed := h.feedSettings(r)
+	releases, repo, lastUpdate := h.filteredReleases(r, settings, allowed)
+
+	title := settings.Organization + " releases"
+	if repo != "" {
+		title = repo + " releases"
+	}
+	feed := newAtomFeed(r, title, "releases")
+	feed.Author = atomPerson{Name: settings.Organization}
+	for _, rel := range releases {
+		feed.Entries = append(feed.Entries, releaseEntry(rel))
+	}
+	writeAtom(w, r, feed, lastUpdate)
+}
+
+// releaseEntry describes a release. Its ID is the release's page on GitHub.
+func releaseEntry(rel github.Release) atomEntry {
+	title := rel.Repository + " " + rel.Tag
+	if rel.Prerelease {
+		title += " (pre-release)"
+	}
+	var summary []string
+	if rel.Name != "" && rel.Name != rel.Tag {
+		summary = append(summary, rel.Name)
+	}
+	if len(rel.Assets) > 0 {
+		names := make([]string, len(rel.Assets))
+		for i, a := range rel.Assets {
+			names[i] = a.Name
+		}
+		summary = append(summary, fmt.Sprintf("Assets: %s", strings.Join(names, ", ")))
+	}
+
+	entry := atomEntry{
+		ID:         rel.URL,
+		Title:      title,
+		Updated:    atomTime(rel.PublishedAt),
+		Published:  atomTime(rel.PublishedAt),
+		Links:      []atomLink{{Rel: "alternate", Type: "text/html", Href: rel.URL}},
+		Categories: []atomCategory{{Term: rel.Repository}},
+		Summary:    strings.Join(summary, ". "),
+	}
+	if rel.Author != "" {
+		entry.Author = &atomPerson{Name: rel.Author}
+	}
+	return entry
+}
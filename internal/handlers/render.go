@@ -3,21 +3,22 @@ package handlers
 import (
 	"bytes"
 	"html/template"
-	"log"
 	"net/http"
+
+	"github.com/ozaq/ecmwf-dash/internal/logging"
 )
 
 // renderTemplate executes a template into a buffer before writing to the
 // ResponseWriter. This prevents partial HTML output on template errors.
-func renderTemplate(w http.ResponseWriter, tmpl *template.Template, name string, data any) {
+func renderTemplate(w http.ResponseWriter, r *http.Request, tmpl *template.Template, name string, data any) {
 	var buf bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
-		log.Printf("Error executing template %q: %v", name, err)
+		logging.FromContext(r.Context()).Error("executing template failed", "template", name, "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if _, err := buf.WriteTo(w); err != nil {
-		log.Printf("Error writing response for template %q: %v", name, err)
+		logging.FromContext(r.Context()).Error("writing response failed", "template", name, "error", err)
 	}
 }
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// maxExternalActivity caps the external-contributor list on /repo/{name}.
+const maxExternalActivity = 10
+
+// ReviewSummary counts a repository's open pull requests by review state.
+// Drafts are counted separately and not by review state.
+type ReviewSummary struct {
+	Total            int
+	Draft            int
+	Approved         int
+	ChangesRequested int
+	Pending          int
+}
+
+// LabelCount is how many open issues carry a label.
+type LabelCount struct {
+	Label github.Label
+	Count int
+}
+
+// Activity is an open issue or pull request by an external contributor.
+type Activity struct {
+	Kind      string // "issue" or "pull"
+	Number    int
+	Title     string
+	URL       string
+	Author    string
+	UpdatedAt time.Time
+}
+
+// Freshness is when one kind of data about a repository was last fetched.
+type Freshness struct {
+	Name      string
+	FetchedAt time.Time // zero until the first successful fetch
+	Stale     bool      // fetched, but longer ago than three intervals
+}
+
+// repoBuilds returns the build status of repo's tracked branches, with
+// flaky and upstream failures marked, or nil before the first fetch.
+func (h *Handler) repoBuilds(settings RepoSettings, repo string) []BranchStatus {
+	branchChecks, _ := h.storage.GetBranchChecks()
+	flaky := flakyIndex(h.flakyChecks([]string{repo}))
+	for i := range branchChecks {
+		if branchChecks[i].Repository == repo {
+			markFlaky(repo, branchChecks[i].Checks, flaky)
+		}
+	}
+	repositories := groupByRepository(branchChecks, settings.RepoConfig)
+	markUpstreamFailures(repositories, settings.RepoConfig)
+	for _, rs := range repositories {
+		if rs.Name == repo {
+			return rs.Branches
+		}
+	}
+	return nil
+}
+
+// summarizeReviews counts prs by review state.
+func summarizeReviews(prs []github.PullRequest) ReviewSummary {
+	s := ReviewSummary{Total: len(prs)}
+	for _, pr := range prs {
+		switch {
+		case pr.Draft:
+			s.Draft++
+		case pr.ReviewStatus == "approved":
+			s.Approved++
+		case pr.ReviewStatus == "changes_requested":
+			s.ChangesRequested++
+		default:
+			s.Pending++
+		}
+	}
+	return s
+}
+
+// countLabels counts issues per label, most used first, and returns how
+// many issues have no label.
+func countLabels(issues []github.Issue) ([]LabelCount, int) {
+	index := make(map[string]int)
+	var counts []LabelCount
+	unlabeled := 0
+	for _, issue := range issues {
+		if len(issue.Labels) == 0 {
+			unlabeled++
+		}
+		for _, label := range issue.Labels {
+			i, ok := index[label.Name]
+			if !ok {
+				i = len(counts)
+				index[label.Name] = i
+				counts = append(counts, LabelCount{Label: label})
+			}
+			counts[i].Count++
+		}
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Label.Name < counts[j].Label.Name
+	})
+	return counts, unlabeled
+}
+
+// externalActivity returns the open issues and pull requests by external
+// contributors, most recently updated first, and how many authors they
+// come from.
+func externalActivity(issues []github.Issue, prs []github.PullRequest) ([]Activity, int) {
+	var activity []Activity
+	authors := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.IsExternal {
+			activity = append(activity, Activity{Kind: "issue", Number: issue.Number, Title: issue.Title, URL: issue.URL, Author: issue.Author, UpdatedAt: issue.UpdatedAt})
+			authors[issue.Author] = true
+		}
+	}
+	for _, pr := range prs {
+		if pr.IsExternal {
+			activity = append(activity, Activity{Kind: "pull", Number: pr.Number, Title: pr.Title, URL: pr.URL, Author: pr.Author, UpdatedAt: pr.UpdatedAt})
+			authors[pr.Author] = true
+		}
+	}
+	sort.SliceStable(activity, func(i, j int) bool { return activity[i].UpdatedAt.After(activity[j].UpdatedAt) })
+	return activity, len(authors)
+}
+
+// freshness reports when each kind of data about repo was last fetched.
+func (h *Handler) freshness(repo string, intervals FetchIntervals) []Freshness {
+	categories := []struct {
+		name, category string
+		interval       time.Duration
+	}{
+		{"Issues", storage.CategoryIssues, intervals.Issues},
+		{"Pull requests", storage.CategoryPRs, intervals.PullRequests},
+		{"Checks", storage.CategoryChecks, intervals.Actions},
+		{"Branch comparisons", storage.CategoryBranches, intervals.Branches},
+		{"Releases", storage.CategoryReleases, intervals.Releases},
+		{"Deployments", storage.CategoryDeployments, intervals.Deployments},
+	}
+	now := time.Now()
+	out := make([]Freshness, 0, len(categories))
+	for _, c := range categories {
+		f := Freshness{Name: c.name, FetchedAt: h.storage.RepoFetchTimes(c.category)[repo]}
+		f.Stale = !f.FetchedAt.IsZero() && now.Sub(f.FetchedAt) > 3*c.interval
+		out = append(out, f)
+	}
+	return out
+}
+
+// RepoDetail gathers everything the dashboard knows about one repository:
+// branch builds, open pull requests by review state, issues by label,
+// external contributions and how fresh each kind of data is.
+func (h *Handler) RepoDetail(w http.ResponseWriter, r *http.Request) {
+	settings, _ := h.visibleSettings(r, h.settings())
+	rc, ok := findRepoConfig(settings.RepoConfig, r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	repo := rc.Name
+	onlyRepo := func(name string) bool { return name == repo }
+
+	issues, _ := h.storage.GetIssues()
+	issues = keepVisible(issues, func(i github.Issue) string { return i.Repository }, onlyRepo)
+	prs, _ := h.storage.GetPullRequests()
+	prs = keepVisible(prs, func(pr github.PullRequest) string { return pr.Repository }, onlyRepo)
+
+	labels, unlabeled := countLabels(issues)
+	activity, contributors := externalActivity(issues, prs)
+	externalCount := len(activity)
+	if len(activity) > maxExternalActivity {
+		activity = activity[:maxExternalActivity]
+	}
+	freshness := h.freshness(repo, settings.FetchIntervals)
+	var staleList []string
+	for _, f := range freshness {
+		if f.Stale {
+			staleList = []string{repo}
+		}
+	}
+	_, _, lastUpdate := h.storage.LastFetchTimes()
+	logging.FromContext(r.Context()).Debug("serving repository", "repo", repo, "issues", len(issues), "prs", len(prs))
+
+	data := struct {
+		PageID          string
+		Organization    string
+		Version         string
+		Repo            string
+		DependsOn       []string
+		Builds          []BranchStatus
+		Reviews         ReviewSummary
+		IssueCount      int
+		Labels          []LabelCount
+		Unlabeled       int
+		External        []Activity
+		ExternalCount   int // open issues and PRs by external contributors
+		ExternalAuthors int
+		Freshness       []Freshness
+		LastUpdate      time.Time
+		StaleRepoList   []string
+	}{
+		PageID:          "builds",
+		Organization:    settings.Organization,
+		Version:         h.version,
+		Repo:            repo,
+		DependsOn:       rc.DependsOn,
+		Builds:          h.repoBuilds(settings, repo),
+		Reviews:         summarizeReviews(prs),
+		IssueCount:      len(issues),
+		Labels:          labels,
+		Unlabeled:       unlabeled,
+		External:        activity,
+		ExternalCount:   externalCount,
+		ExternalAuthors: contributors,
+		Freshness:       freshness,
+		LastUpdate:      lastUpdate,
+		StaleRepoList:   staleList,
+	}
+
+	renderTemplate(w, r, h.repoTemplate, "base", data)
+}
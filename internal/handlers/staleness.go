@@ -10,6 +10,10 @@ type FetchIntervals struct {
 	Issues       time.Duration
 	PullRequests time.Duration
 	Actions      time.Duration
+	Branches     time.Duration
+	Releases     time.Duration
+	Planning     time.Duration
+	Deployments  time.Duration
 }
 
 // staleRepos returns repo names whose last-success timestamp is older than
@@ -43,7 +47,7 @@ func (h *Handler) computeStaleness(category string, interval time.Duration, last
 	}
 	repoTimes := h.storage.RepoFetchTimes(category)
 	threshold := interval * 3
-	staleMap := staleRepos(repoTimes, threshold, h.repoNames)
+	staleMap := staleRepos(repoTimes, threshold, h.settings().RepoNames)
 	if staleMap == nil {
 		return make(map[string]bool), nil
 	}
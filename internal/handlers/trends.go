@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/logging"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+// TrendSettings controls the check duration statistics on /builds/trends.
+type TrendSettings struct {
+	Runs                int     // latest runs per check considered
+	RegressionThreshold float64 // flag slowdowns above this fraction, e.g. 0.25
+}
+
+// recentRuns is how many of the latest runs are compared against the rest
+// of the window when looking for regressions.
+const recentRuns = 5
+
+// CheckTrend summarises the recent durations of one check.
+type CheckTrend struct {
+	Workflow   string
+	Name       string
+	Runs       int
+	Last       time.Duration
+	P50        time.Duration
+	P90        time.Duration
+	Sparkline  string  // SVG polyline points in a 100x20 box
+	Change     float64 // median of the recent runs relative to the earlier ones; 0 if too few runs
+	Regression bool
+}
+
+// computeTrends derives per-check statistics over the latest s.Runs runs.
+// Regressions sort first.
+func computeTrends(history []storage.CheckHistory, s TrendSettings) []CheckTrend {
+	trends := make([]CheckTrend, 0, len(history))
+	for _, h := range history {
+		samples := h.Samples
+		if len(samples) > s.Runs {
+			samples = samples[len(samples)-s.Runs:]
+		}
+		if len(samples) == 0 {
+			continue
+		}
+		durations := make([]time.Duration, len(samples))
+		for i, sample := range samples {
+			durations[i] = sample.Duration
+		}
+
+		t := CheckTrend{
+			Workflow:  h.Workflow,
+			Name:      h.Name,
+			Runs:      len(durations),
+			Last:      durations[len(durations)-1],
+			P50:       percentile(durations, 50),
+			P90:       percentile(durations, 90),
+			Sparkline: sparkline(durations),
+		}
+		if len(durations) >= 2*recentRuns {
+			baseline := percentile(durations[:len(durations)-recentRuns], 50)
+			recent := percentile(durations[len(durations)-recentRuns:], 50)
+			if baseline > 0 {
+				t.Change = float64(recent-baseline) / float64(baseline)
+				t.Regression = t.Change > s.RegressionThreshold
+			}
+		}
+		trends = append(trends, t)
+	}
+	sort.SliceStable(trends, func(i, j int) bool { return trends[i].Regression && !trends[j].Regression })
+	return trends
+}
+
+// percentile returns the nearest-rank p-th percentile of durations.
+func percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// sparkline returns polyline points plotting durations, oldest left, scaled
+// so the slowest run touches the top of a 100x20 box.
+func sparkline(durations []time.Duration) string {
+	longest := slices.Max(durations)
+	if longest <= 0 {
+		return ""
+	}
+	step := 100.0
+	if len(durations) > 1 {
+		step = 100.0 / float64(len(durations)-1)
+	}
+	points := make([]string, len(durations))
+	for i, d := range durations {
+		y := 20 - 19*float64(d)/float64(longest)
+		points[i] = fmt.Sprintf("%.1f,%.1f", float64(i)*step, y)
+	}
+	return strings.Join(points, " ")
+}
+
+func (h *Handler) BuildTrends(w http.ResponseWriter, r *http.Request) {
+	settings, _ := h.visibleSettings(r, h.settings())
+	repo := sanitizeRepo(r.URL.Query().Get("repo"), settings.RepoNames)
+	if repo == "" && len(settings.RepoNames) > 0 {
+		repo = settings.RepoNames[0]
+	}
+
+	var trends []CheckTrend
+	if repo != "" {
+		trends = computeTrends(h.storage.CheckHistory(repo), settings.Trends)
+	}
+	_, _, lastUpdate := h.storage.LastFetchTimes()
+	logging.FromContext(r.Context()).Debug("serving build trends", "repo", repo, "checks", len(trends))
+
+	data := struct {
+		PageID        string
+		Organization  string
+		Version       string
+		Repo          string
+		RepoNames     []string
+		Trends        []CheckTrend
+		Runs          int
+		Threshold     float64
+		LastUpdate    time.Time
+		StaleRepoList []string
+	}{
+		PageID:       "builds",
+		Organization: settings.Organization,
+		Version:      h.version,
+		Repo:         repo,
+		RepoNames:    settings.RepoNames,
+		Trends:       trends,
+		Runs:         settings.Trends.Runs,
+		Threshold:    settings.Trends.RegressionThreshold,
+		LastUpdate:   lastUpdate,
+	}
+
+	renderTemplate(w, r, h.trendsTemplate, "base", data)
+}
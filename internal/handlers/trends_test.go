@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+	"github.com/ozaq/ecmwf-dash/internal/storage"
+)
+
+func minutes(ms ...int) []storage.DurationSample {
+	samples := make([]storage.DurationSample, len(ms))
+	for i, m := range ms {
+		samples[i] = storage.DurationSample{CommitSHA: string(rune('a' + i)), Duration: time.Duration(m) * time.Minute}
+	}
+	return samples
+}
+
+func TestPercentile(t *testing.T) {
+	ds := []time.Duration{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+	if got := percentile(ds, 50); got != 5 {
+		t.Errorf("p50 = %d, want 5", got)
+	}
+	if got := percentile(ds, 90); got != 9 {
+		t.Errorf("p90 = %d, want 9", got)
+	}
+	if got := percentile(ds[:1], 90); got != 5 {
+		t.Errorf("p90 of one = %d, want 5", got)
+	}
+	if ds[0] != 5 {
+		t.Error("percentile sorted its input")
+	}
+}
+
+func TestComputeTrends(t *testing.T) {
+	history := []storage.CheckHistory{
+		{Workflow: "CI", Name: "build", Samples: minutes(10, 10, 11, 10, 9, 10, 14, 15, 14, 15, 16)},
+		{Workflow: "CI", Name: "lint", Samples: minutes(2, 2, 2, 2, 2, 2, 2, 2, 2, 2)},
+		{Workflow: "Docs", Name: "docs", Samples: minutes(3, 4)},
+	}
+
+	trends := computeTrends(history, TrendSettings{Runs: 10, RegressionThreshold: 0.25})
+	if len(trends) != 3 {
+		t.Fatalf("got %d trends, want 3", len(trends))
+	}
+
+	build := trends[0]
+	if build.Name != "build" || !build.Regression {
+		t.Fatalf("trends[0] = %+v, want build flagged as regression first", build)
+	}
+	// Window is the last 10 runs: 10 11 10 9 10 | 14 15 14 15 16
+	if build.Runs != 10 || build.Last != 16*time.Minute || build.P50 != 11*time.Minute || build.P90 != 15*time.Minute {
+		t.Errorf("build stats = runs %d last %v p50 %v p90 %v", build.Runs, build.Last, build.P50, build.P90)
+	}
+	if build.Change < 0.49 || build.Change > 0.51 {
+		t.Errorf("build change = %v, want 0.5 (15m vs 10m)", build.Change)
+	}
+
+	if trends[1].Name != "lint" || trends[1].Regression || trends[1].Change != 0 {
+		t.Errorf("lint = %+v, want steady", trends[1])
+	}
+	if trends[2].Name != "docs" || trends[2].Change != 0 || trends[2].Sparkline == "" {
+		t.Errorf("docs = %+v, want no change with too few runs but a sparkline", trends[2])
+	}
+}
+
+func TestBuildTrendsHandler(t *testing.T) {
+	h, store := newTestHandler(t)
+	start := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i, d := range []time.Duration{4 * time.Minute, 5 * time.Minute} {
+		started := start.Add(time.Duration(i) * time.Hour)
+		store.MergeBranchChecks([]github.BranchCheck{{
+			Repository: "atlas",
+			Branch:     "main",
+			CommitSHA:  string(rune('a' + i)),
+			Checks: []github.Check{
+				{Name: "build (gcc)", Status: "completed", Conclusion: "success", Workflow: "CI", StartedAt: started, CompletedAt: started.Add(d)},
+			},
+		}}, nil, []string{"atlas"})
+	}
+
+	rec := httptest.NewRecorder()
+	h.BuildTrends(rec, httptest.NewRequest(http.MethodGet, "/builds/trends?repo=atlas", nil))
+	assertResponse(t, rec, http.StatusOK, "Build Trends", "build (gcc)", "5m00s", `<polyline points="0.0,4.8 100.0,1.0">`, `href="../static/base.css"`)
+
+	rec = httptest.NewRecorder()
+	h.BuildTrends(rec, httptest.NewRequest(http.MethodGet, "/builds/trends", nil))
+	if !strings.Contains(rec.Body.String(), "No check durations recorded for eccodes") {
+		t.Error("without repo the first configured repository should be shown")
+	}
+}
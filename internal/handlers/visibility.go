@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+)
+
+// RepoVisibility decides which repositories the viewer of a request may
+// see. It is nil when per-viewer filtering is disabled.
+type RepoVisibility interface {
+	Visible(ctx context.Context, org string, repos []string) map[string]bool
+}
+
+// PublicRepos reports which repositories are public. Feeds use it for kiosk
+// tokens, which have no signed-in user whose access could be checked.
+type PublicRepos interface {
+	Public(ctx context.Context, org string, repos []string) map[string]bool
+}
+
+// visibleSettings narrows settings to the repositories the request's viewer
+// may see, so repo filters, dropdowns and stale banners never mention
+// hidden repositories. The returned func reports whether data for a repo
+// may be shown; repos no longer in the config are hidden when filtering is
+// active.
+func (h *Handler) visibleSettings(r *http.Request, s RepoSettings) (RepoSettings, func(repo string) bool) {
+	if h.visibility == nil {
+		return s, func(string) bool { return true }
+	}
+	visible := h.visibility.Visible(r.Context(), s.Organization, s.RepoNames)
+	allowed := func(repo string) bool { return visible[repo] }
+
+	s.RepoNames = keepVisible(s.RepoNames, func(name string) string { return name }, allowed)
+	s.RepoConfig = keepVisible(s.RepoConfig, func(rc RepoBranches) string { return rc.Name }, allowed)
+	return s, allowed
+}
+
+// keepVisible returns the items whose repository is allowed, in a new slice.
+func keepVisible[T any](items []T, repoOf func(T) string, allowed func(string) bool) []T {
+	var kept []T
+	for _, item := range items {
+		if allowed(repoOf(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "json", "info")
+	logger.Info("fetch completed", "repo", "eccodes", "count", 3)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output is not JSON: %v (%q)", err, buf.String())
+	}
+	if entry["msg"] != "fetch completed" || entry["repo"] != "eccodes" || entry["count"] != float64(3) {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+}
+
+func TestNewTextRespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "text", "warn")
+	logger.Info("hidden")
+	logger.Warn("shown", "repo", "atlas")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Error("info message should be filtered at warn level")
+	}
+	if !strings.Contains(out, "msg=shown") || !strings.Contains(out, "repo=atlas") {
+		t.Errorf("unexpected text output: %q", out)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"info", slog.LevelInfo},
+		{"", slog.LevelInfo},
+		{"WARN", slog.LevelWarn},
+		{"error", slog.LevelError},
+		{"bogus", slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		if got := ParseLevel(tt.in); got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRequestIDContext(t *testing.T) {
+	ctx := context.Background()
+	if got := RequestID(ctx); got != "" {
+		t.Errorf("RequestID(empty ctx) = %q, want empty", got)
+	}
+	ctx = WithRequestID(ctx, "abc123")
+	if got := RequestID(ctx); got != "abc123" {
+		t.Errorf("RequestID() = %q, want %q", got, "abc123")
+	}
+}
+
+func TestNewRequestIDUnique(t *testing.T) {
+	a, b := NewRequestID(), NewRequestID()
+	if len(a) != 16 || a == b {
+		t.Errorf("NewRequestID() returned %q and %q, want two distinct 16-char IDs", a, b)
+	}
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// captureDefault redirects the default slog logger to a JSON buffer for the
+// duration of the test.
+func captureDefault(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(New(&buf, "json", "debug"))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestMiddlewareLogsRequest(t *testing.T) {
+	buf := captureDefault(t)
+
+	var seenID string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = RequestID(r.Context())
+		FromContext(r.Context()).Info("inside handler")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("short and stout"))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/builds", nil))
+
+	if seenID == "" {
+		t.Fatal("handler context has no request ID")
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != seenID {
+		t.Errorf("%s header = %q, want %q", RequestIDHeader, got, seenID)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	var inner, access map[string]any
+	if err := json.Unmarshal(lines[0], &inner); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(lines[1], &access); err != nil {
+		t.Fatal(err)
+	}
+	if inner["request_id"] != seenID {
+		t.Errorf("handler log request_id = %v, want %q", inner["request_id"], seenID)
+	}
+	if access["request_id"] != seenID || access["path"] != "/builds" || access["method"] != "GET" {
+		t.Errorf("unexpected access log: %v", access)
+	}
+	if access["status"] != float64(http.StatusTeapot) || access["bytes"] != float64(len("short and stout")) {
+		t.Errorf("access log status/bytes = %v/%v", access["status"], access["bytes"])
+	}
+	if _, ok := access["duration_ms"]; !ok {
+		t.Error("access log missing duration_ms")
+	}
+}
+
+func TestMiddlewareKeepsValidIncomingID(t *testing.T) {
+	captureDefault(t)
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "proxy-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get(RequestIDHeader); got != "proxy-42" {
+		t.Errorf("valid incoming ID should be kept, got %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get(RequestIDHeader); got == "bad id\nwith newline" || got == "" {
+		t.Errorf("invalid incoming ID should be replaced, got %q", got)
+	}
+}
+
+func TestMiddlewareDefaultStatus(t *testing.T) {
+	buf := captureDefault(t)
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var access map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &access); err != nil {
+		t.Fatal(err)
+	}
+	if access["status"] != float64(http.StatusOK) {
+		t.Errorf("status = %v, want 200 when handler writes nothing", access["status"])
+	}
+}
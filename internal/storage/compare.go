@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+// MergeBranchComparisons replaces the comparisons of successfully fetched
+// repos, keeping those of repos in failedRepos and the previous result of
+// any pair whose comparison failed this time.
+func (m *Memory) MergeBranchComparisons(comparisons []github.BranchComparison, failedRepos, succeededRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	comparisons = retainedItems(m, comparisons, func(c github.BranchComparison) string { return c.Repository })
+	succeededRepos = m.retainedRepos(succeededRepos)
+
+	type key struct{ repo, base, head string }
+	previous := make(map[key]github.BranchComparison, len(m.comparisons))
+	for _, cmp := range m.comparisons {
+		previous[key{cmp.Repository, cmp.Base, cmp.Head}] = cmp
+	}
+
+	failed := toSet(failedRepos)
+	var merged []github.BranchComparison
+	for _, cmp := range m.comparisons {
+		if failed[cmp.Repository] {
+			merged = append(merged, cmp)
+		}
+	}
+	for _, cmp := range comparisons {
+		if failed[cmp.Repository] {
+			continue
+		}
+		if old, ok := previous[key{cmp.Repository, cmp.Base, cmp.Head}]; ok && cmp.FetchFailed {
+			cmp = old
+		}
+		merged = append(merged, cmp)
+	}
+	m.comparisons = merged
+
+	if len(succeededRepos) > 0 {
+		now := time.Now()
+		m.comparisonsTime = now
+		for _, name := range succeededRepos {
+			m.branchRepoTimes[name] = now
+		}
+	}
+}
+
+// GetBranchComparisons returns a copy of the branch comparisons and when
+// they were last fetched.
+func (m *Memory) GetBranchComparisons() ([]github.BranchComparison, time.Time) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]github.BranchComparison(nil), m.comparisons...), m.comparisonsTime
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+func TestMergeBranchComparisons(t *testing.T) {
+	s := New()
+	s.MergeBranchComparisons([]github.BranchComparison{
+		{Repository: "eckit", Base: "master", Head: "develop", AheadBy: 10},
+		{Repository: "fdb", Base: "master", Head: "develop", AheadBy: 5},
+	}, nil, []string{"eckit", "fdb"})
+
+	// eckit's comparison fails this time and fdb fails entirely.
+	s.MergeBranchComparisons([]github.BranchComparison{
+		{Repository: "eckit", Base: "master", Head: "develop", FetchFailed: true},
+		{Repository: "eckit", Base: "master", Head: "release", AheadBy: 1},
+	}, []string{"fdb"}, []string{"eckit"})
+
+	comparisons, updated := s.GetBranchComparisons()
+	if updated.IsZero() {
+		t.Error("expected a last update time")
+	}
+	got := make(map[string]github.BranchComparison)
+	for _, cmp := range comparisons {
+		got[cmp.Repository+"/"+cmp.Head] = cmp
+	}
+	if len(got) != 3 {
+		t.Fatalf("comparisons = %+v, want eckit develop and release, fdb develop", got)
+	}
+	if cmp := got["eckit/develop"]; cmp.FetchFailed || cmp.AheadBy != 10 {
+		t.Errorf("eckit/develop = %+v, want the earlier result kept", cmp)
+	}
+	if cmp := got["fdb/develop"]; cmp.AheadBy != 5 {
+		t.Errorf("fdb/develop = %+v, want kept for the failed repo", cmp)
+	}
+	s.RetainRepos([]string{"fdb"})
+	if comparisons, _ := s.GetBranchComparisons(); len(comparisons) != 1 {
+		t.Errorf("after RetainRepos got %d comparisons, want fdb's", len(comparisons))
+	}
+	if _, ok := s.RepoFetchTimes(CategoryBranches)["eckit"]; ok {
+		t.Error("RetainRepos should drop eckit's fetch time")
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+// MergeDeployments replaces the environments of successfully fetched
+// repos. Repos in failedRepos keep their earlier environments.
+func (m *Memory) MergeDeployments(envs []github.Environment, failedRepos, succeededRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	envs = retainedItems(m, envs, func(e github.Environment) string { return e.Repository })
+	succeededRepos = m.retainedRepos(succeededRepos)
+
+	failed := toSet(failedRepos)
+	var merged []github.Environment
+	for _, env := range m.environments {
+		if failed[env.Repository] {
+			merged = append(merged, env)
+		}
+	}
+	for _, env := range envs {
+		if !failed[env.Repository] {
+			merged = append(merged, env)
+		}
+	}
+	m.environments = merged
+
+	if len(succeededRepos) > 0 {
+		now := time.Now()
+		m.deploymentsTime = now
+		for _, name := range succeededRepos {
+			m.deploymentRepoTimes[name] = now
+		}
+	}
+}
+
+// GetDeployments returns a copy of the environments and when they were
+// last fetched.
+func (m *Memory) GetDeployments() ([]github.Environment, time.Time) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	envs := make([]github.Environment, len(m.environments))
+	for i, env := range m.environments {
+		envs[i] = env
+		envs[i].Deployments = append([]github.Deployment(nil), env.Deployments...)
+	}
+	return envs, m.deploymentsTime
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+func TestMergeDeployments(t *testing.T) {
+	s := New()
+	s.MergeDeployments([]github.Environment{
+		{Repository: "eckit", Name: "docs", Deployments: []github.Deployment{{ID: 1, State: "success"}}},
+		{Repository: "fdb", Name: "docs", Deployments: []github.Deployment{{ID: 2, State: "success"}}},
+		{Repository: "fdb", Name: "pypi", Deployments: []github.Deployment{{ID: 3, State: "failure"}}},
+	}, nil, []string{"eckit", "fdb"})
+
+	// fdb fails this time; eckit no longer has any deployments.
+	s.MergeDeployments(nil, []string{"fdb"}, []string{"eckit"})
+
+	envs, updated := s.GetDeployments()
+	if updated.IsZero() {
+		t.Error("expected a last update time")
+	}
+	if len(envs) != 2 || envs[0].Repository != "fdb" || envs[1].Name != "pypi" {
+		t.Fatalf("envs = %+v, want fdb's two environments kept", envs)
+	}
+
+	envs[0].Deployments[0].State = "mutated"
+	if again, _ := s.GetDeployments(); again[0].Deployments[0].State != "success" {
+		t.Error("GetDeployments should return copies of the deployments")
+	}
+
+	s.RetainRepos([]string{"eckit"})
+	if envs, _ := s.GetDeployments(); len(envs) != 0 {
+		t.Errorf("after RetainRepos got %d environments, want none", len(envs))
+	}
+	if _, ok := s.RepoFetchTimes(CategoryDeployments)["fdb"]; ok {
+		t.Error("RetainRepos should drop fdb's fetch time")
+	}
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+// EventLimit is the number of events kept, newest first.
+const EventLimit = 500
+
+// Event kinds.
+const (
+	EventIssueOpened  = "issue_opened"
+	EventPROpened     = "pr_opened"
+	EventPRMerged     = "pr_merged"
+	EventBuildChanged = "build_changed"
+)
+
+// Event is a change noticed by comparing a fetch with the one before it.
+// Only repositories fetched before count, so the first fetch after a start
+// or after adding a repository records nothing.
+type Event struct {
+	Kind       string
+	Repository string
+	Number     int    // issue or pull request; 0 for builds
+	Title      string // issues and pull requests
+	URL        string // the issue, pull request or commit
+	Author     string
+	Labels     []string
+	Branch     string // builds, and the base branch of pull requests
+
+	// Status is a build's settled status, "success" or "failure", and
+	// PreviousStatus the one before; both are empty for other kinds.
+	Status         string
+	PreviousStatus string
+	CommitSHA      string
+
+	// At is when the event happened on GitHub: opened, reopened (the
+	// last update of an item created before the previous fetch) or
+	// merged. Builds have no such time and use when they were noticed.
+	At time.Time
+}
+
+type eventKey struct {
+	kind, repo string
+	number     int
+}
+
+type branchKey struct {
+	repo, branch string
+}
+
+// recordOpenedIssues adds an event for each issue of a repo fetched before
+// that is not among the stored issues. Called under lock, before the
+// stored issues are replaced.
+func (m *Memory) recordOpenedIssues(issues []github.Issue, now time.Time) {
+	known := make(map[eventKey]bool, len(m.issues))
+	for _, issue := range m.issues {
+		known[eventKey{EventIssueOpened, issue.Repository, issue.Number}] = true
+	}
+	var events []Event
+	for _, issue := range issues {
+		key := eventKey{EventIssueOpened, issue.Repository, issue.Number}
+		fetchedAt, fetched := m.issueRepoTimes[issue.Repository]
+		if !fetched || known[key] || m.hasEvent(key) {
+			continue
+		}
+		events = append(events, Event{
+			Kind:       EventIssueOpened,
+			Repository: issue.Repository,
+			Number:     issue.Number,
+			Title:      issue.Title,
+			URL:        issue.URL,
+			Author:     issue.Author,
+			Labels:     labelNames(issue.Labels),
+			At:         openedAt(issue.CreatedAt, issue.UpdatedAt, fetchedAt, now),
+		})
+	}
+	m.addEvents(events)
+}
+
+// recordOpenedPRs is recordOpenedIssues for pull requests.
+func (m *Memory) recordOpenedPRs(prs []github.PullRequest, now time.Time) {
+	known := make(map[eventKey]bool, len(m.pullRequests))
+	for _, pr := range m.pullRequests {
+		known[eventKey{EventPROpened, pr.Repository, pr.Number}] = true
+	}
+	var events []Event
+	for _, pr := range prs {
+		key := eventKey{EventPROpened, pr.Repository, pr.Number}
+		fetchedAt, fetched := m.prRepoTimes[pr.Repository]
+		if !fetched || known[key] || m.hasEvent(key) {
+			continue
+		}
+		events = append(events, prEvent(EventPROpened, pr, openedAt(pr.CreatedAt, pr.UpdatedAt, fetchedAt, now)))
+	}
+	m.addEvents(events)
+}
+
+// openedAt is when an issue or pull request not open at the fetch at
+// fetchedAt was opened. One created before that fetch has been reopened
+// since, which updated it, so its last update stands in for the reopening.
+func openedAt(created, updated, fetchedAt, now time.Time) time.Time {
+	switch {
+	case !created.Before(fetchedAt):
+		return created
+	case updated.After(fetchedAt):
+		return updated
+	default:
+		return now
+	}
+}
+
+// RecordMergedPullRequests adds an event for each pull request merged
+// since the last fetch. The fetcher finds them among the pull requests
+// that are no longer open.
+func (m *Memory) RecordMergedPullRequests(prs []github.PullRequest) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prs = retainedItems(m, prs, func(pr github.PullRequest) string { return pr.Repository })
+
+	var events []Event
+	for _, pr := range prs {
+		if pr.MergedAt.IsZero() || m.hasEvent(eventKey{EventPRMerged, pr.Repository, pr.Number}) {
+			continue
+		}
+		events = append(events, prEvent(EventPRMerged, pr, pr.MergedAt))
+	}
+	m.addEvents(events)
+}
+
+func prEvent(kind string, pr github.PullRequest, at time.Time) Event {
+	return Event{
+		Kind:       kind,
+		Repository: pr.Repository,
+		Number:     pr.Number,
+		Title:      pr.Title,
+		URL:        pr.URL,
+		Author:     pr.Author,
+		Labels:     labelNames(pr.Labels),
+		Branch:     pr.BaseBranch,
+		At:         at,
+	}
+}
+
+// recordBuildTransitions adds an event for each branch whose settled
+// status differs from the last one seen. Branches with running required
+// checks or no checks are not settled and are skipped until they are.
+// Called under lock.
+func (m *Memory) recordBuildTransitions(branchChecks []github.BranchCheck, now time.Time) {
+	var events []Event
+	for _, bc := range branchChecks {
+		status := settledStatus(bc)
+		if status == "" {
+			continue
+		}
+		key := branchKey{bc.Repository, bc.Branch}
+		prev, seen := m.buildStatus[key]
+		m.buildStatus[key] = status
+		if !seen || prev == status {
+			continue
+		}
+		at := bc.UpdatedAt
+		if at.IsZero() {
+			at = now
+		}
+		events = append(events, Event{
+			Kind:           EventBuildChanged,
+			Repository:     bc.Repository,
+			URL:            bc.CommitURL,
+			Branch:         bc.Branch,
+			Status:         status,
+			PreviousStatus: prev,
+			CommitSHA:      bc.CommitSHA,
+			At:             at,
+		})
+	}
+	m.addEvents(events)
+}
+
+// settledStatus returns "failure" if a required check failed or never
+// reported, "success" if all required checks passed, and "" while one is
+// running or when there are no checks. With no required checks
+// configured, every check counts, as on /builds.
+func settledStatus(bc github.BranchCheck) string {
+	if len(bc.MissingRequired) > 0 {
+		return "failure"
+	}
+	status := ""
+	for _, check := range bc.Checks {
+		if len(bc.RequiredChecks) > 0 && !check.Required {
+			continue
+		}
+		switch github.ClassifyCheck(check.Status, check.Conclusion) {
+		case "running":
+			return ""
+		case "failure":
+			status = "failure"
+		case "success":
+			if status == "" {
+				status = "success"
+			}
+		}
+	}
+	return status
+}
+
+// hasEvent reports whether an issue or pull request event is already
+// kept. Called under lock.
+func (m *Memory) hasEvent(key eventKey) bool {
+	for _, e := range m.events {
+		if e.Kind == key.kind && e.Repository == key.repo && e.Number == key.number {
+			return true
+		}
+	}
+	return false
+}
+
+// addEvents adds events to the log, keeping it newest first and capped
+// at EventLimit. Called under lock.
+func (m *Memory) addEvents(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	m.events = append(events, m.events...)
+	sort.SliceStable(m.events, func(i, j int) bool { return m.events[i].At.After(m.events[j].At) })
+	if len(m.events) > EventLimit {
+		m.events = m.events[:EventLimit]
+	}
+}
+
+// GetEvents returns a copy of the event log, newest first.
+func (m *Memory) GetEvents() []Event {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]Event, len(m.events))
+	for i, e := range m.events {
+		e.Labels = append([]string(nil), e.Labels...)
+		out[i] = e
+	}
+	return out
+}
+
+func labelNames(labels []github.Label) []string {
+	var names []string
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+func TestOpenedEvents(t *testing.T) {
+	s := New()
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	s.MergeIssues([]github.Issue{{Repository: "eccodes", Number: 1}}, nil, []string{"eccodes"})
+	s.MergePullRequests([]github.PullRequest{{Repository: "eccodes", Number: 2}}, nil, []string{"eccodes"})
+	if events := s.GetEvents(); len(events) != 0 {
+		t.Fatalf("first fetch recorded %v, want nothing", events)
+	}
+	// The first fetch happened before the items below were created.
+	s.issueRepoTimes["eccodes"] = created.Add(-time.Hour)
+	s.prRepoTimes["eccodes"] = created.Add(-time.Hour)
+
+	s.MergeIssues([]github.Issue{
+		{Repository: "eccodes", Number: 1},
+		{Repository: "eccodes", Number: 3, Title: "GRIB2 decoding crash", Author: "alice", CreatedAt: created, Labels: []github.Label{{Name: "bug"}}},
+		{Repository: "atlas", Number: 4}, // not fetched before
+	}, nil, []string{"eccodes", "atlas"})
+	s.MergePullRequests([]github.PullRequest{
+		{Repository: "eccodes", Number: 2},
+		{Repository: "eccodes", Number: 5, Title: "Fix crash", BaseBranch: "develop", CreatedAt: created.Add(time.Hour)},
+	}, nil, []string{"eccodes"})
+	// The next fetch still sees them, which adds nothing.
+	s.MergeIssues([]github.Issue{{Repository: "eccodes", Number: 3}}, nil, []string{"eccodes"})
+
+	events := s.GetEvents()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(events), events)
+	}
+	if e := events[0]; e.Kind != EventPROpened || e.Number != 5 || e.Branch != "develop" || !e.At.Equal(created.Add(time.Hour)) {
+		t.Errorf("newest event = %+v, want PR #5 opened", e)
+	}
+	if e := events[1]; e.Kind != EventIssueOpened || e.Number != 3 || e.Author != "alice" || len(e.Labels) != 1 || e.Labels[0] != "bug" {
+		t.Errorf("event = %+v, want issue #3 opened", e)
+	}
+
+	merged := created.Add(2 * time.Hour)
+	s.RecordMergedPullRequests([]github.PullRequest{
+		{Repository: "eccodes", Number: 2, Title: "Old PR", MergedAt: merged},
+		{Repository: "eccodes", Number: 6}, // closed without merging
+	})
+	s.RecordMergedPullRequests([]github.PullRequest{{Repository: "eccodes", Number: 2, MergedAt: merged}})
+	events = s.GetEvents()
+	if len(events) != 3 || events[0].Kind != EventPRMerged || events[0].Number != 2 || !events[0].At.Equal(merged) {
+		t.Errorf("events = %+v, want one merge of #2 on top", events)
+	}
+
+	// An issue created long ago and reopened since is dated by its update.
+	reopened := created.Add(3 * time.Hour)
+	s.issueRepoTimes["eccodes"] = created.Add(2 * time.Hour)
+	s.MergeIssues([]github.Issue{
+		{Repository: "eccodes", Number: 3},
+		{Repository: "eccodes", Number: 7, CreatedAt: created.AddDate(-1, 0, 0), UpdatedAt: reopened},
+	}, nil, []string{"eccodes"})
+	if e := s.GetEvents()[0]; e.Number != 7 || !e.At.Equal(reopened) {
+		t.Errorf("newest event = %+v, want issue #7 reopened at %v", e, reopened)
+	}
+
+	s.RetainRepos([]string{"atlas"})
+	if events := s.GetEvents(); len(events) != 0 {
+		t.Errorf("RetainRepos kept %d events of a removed repo", len(events))
+	}
+}
+
+func TestBuildTransitions(t *testing.T) {
+	s := New()
+	branch := func(sha string, checks ...github.Check) []github.BranchCheck {
+		return []github.BranchCheck{{Repository: "eccodes", Branch: "develop", CommitSHA: sha, CommitURL: "https://github.com/ecmwf/eccodes/commit/" + sha, Checks: checks}}
+	}
+	passed := github.Check{Name: "ci", Status: "completed", Conclusion: "success"}
+	failed := github.Check{Name: "ci", Status: "completed", Conclusion: "failure"}
+	running := github.Check{Name: "ci", Status: "in_progress"}
+
+	s.MergeBranchChecks(branch("a1", passed), nil, []string{"eccodes"})
+	s.MergeBranchChecks(branch("b2", running), nil, []string{"eccodes"})
+	s.MergeBranchChecks(branch("b2", failed), nil, []string{"eccodes"})
+	s.MergeBranchChecks(branch("c3", failed), nil, []string{"eccodes"})
+	s.MergeBranchChecks(branch("d4", passed), nil, []string{"eccodes"})
+
+	events := s.GetEvents()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want failing and fixed: %+v", len(events), events)
+	}
+	var fixed, broke Event
+	for _, e := range events {
+		if e.Status == "success" {
+			fixed = e
+		} else {
+			broke = e
+		}
+	}
+	if broke.Kind != EventBuildChanged || broke.CommitSHA != "b2" || broke.PreviousStatus != "success" || broke.At.IsZero() {
+		t.Errorf("failing event = %+v, want b2 after success", broke)
+	}
+	if fixed.CommitSHA != "d4" || fixed.PreviousStatus != "failure" || fixed.Branch != "develop" {
+		t.Errorf("fixed event = %+v, want d4 after failure", fixed)
+	}
+}
+
+func TestSettledStatus(t *testing.T) {
+	optionalFailed := github.Check{Name: "docs", Status: "completed", Conclusion: "failure"}
+	required := github.Check{Name: "ci", Status: "completed", Conclusion: "success", Required: true}
+	tests := []struct {
+		name string
+		bc   github.BranchCheck
+		want string
+	}{
+		{"no checks", github.BranchCheck{}, ""},
+		{"optional failure", github.BranchCheck{RequiredChecks: []string{"ci"}, Checks: []github.Check{required, optionalFailed}}, "success"},
+		{"every check counts", github.BranchCheck{Checks: []github.Check{required, optionalFailed}}, "failure"},
+		{"missing required", github.BranchCheck{RequiredChecks: []string{"ci", "lint"}, MissingRequired: []string{"lint"}, Checks: []github.Check{required}}, "failure"},
+	}
+	for _, tt := range tests {
+		if got := settledStatus(tt.bc); got != tt.want {
+			t.Errorf("%s: settledStatus = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
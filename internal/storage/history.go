@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+// HistoryLimit is the number of runs kept per check.
+const HistoryLimit = 100
+
+// DurationSample is one finished run of a check.
+type DurationSample struct {
+	Branch      string
+	CommitSHA   string
+	CompletedAt time.Time
+	Duration    time.Duration
+}
+
+// CheckHistory is the duration history of one check in a repository.
+type CheckHistory struct {
+	Workflow string
+	Name     string
+	Samples  []DurationSample // oldest first
+}
+
+type historyKey struct {
+	repo, workflow, name string
+}
+
+// recordDurations appends the finished checks of branchChecks to the
+// duration history. The same run is seen on every fetch until the branch
+// moves on, so samples are deduplicated by commit and completion time.
+// Called under lock.
+func (m *Memory) recordDurations(branchChecks []github.BranchCheck) {
+	for _, bc := range branchChecks {
+		for _, check := range bc.Checks {
+			d := check.Duration()
+			if d <= 0 {
+				continue
+			}
+			key := historyKey{bc.Repository, check.Workflow, check.Name}
+			samples := m.durations[key]
+			if hasSample(samples, bc.CommitSHA, check.CompletedAt) {
+				continue
+			}
+			samples = append(samples, DurationSample{
+				Branch:      bc.Branch,
+				CommitSHA:   bc.CommitSHA,
+				CompletedAt: check.CompletedAt,
+				Duration:    d,
+			})
+			sort.SliceStable(samples, func(i, j int) bool { return samples[i].CompletedAt.Before(samples[j].CompletedAt) })
+			if len(samples) > HistoryLimit {
+				samples = samples[len(samples)-HistoryLimit:]
+			}
+			m.durations[key] = samples
+		}
+	}
+}
+
+func hasSample(samples []DurationSample, sha string, completedAt time.Time) bool {
+	for i := len(samples) - 1; i >= 0; i-- {
+		if samples[i].CommitSHA == sha && samples[i].CompletedAt.Equal(completedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckHistory returns copies of the duration histories of repo's checks,
+// ordered by workflow and check name.
+func (m *Memory) CheckHistory(repo string) []CheckHistory {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var out []CheckHistory
+	for key, samples := range m.durations {
+		if key.repo != repo {
+			continue
+		}
+		out = append(out, CheckHistory{
+			Workflow: key.workflow,
+			Name:     key.name,
+			Samples:  append([]DurationSample(nil), samples...),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Workflow != out[j].Workflow {
+			return out[i].Workflow < out[j].Workflow
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// OutcomeSample is one finished run of a check, passed or not.
+type OutcomeSample struct {
+	Branch     string
+	CommitSHA  string
+	RunAttempt int
+	Conclusion string
+	// CompletedAt is zero for commit statuses; SeenAt is when the outcome
+	// was first fetched and orders the samples.
+	CompletedAt time.Time
+	SeenAt      time.Time
+}
+
+// CheckOutcomes is the outcome history of one check in a repository.
+type CheckOutcomes struct {
+	Workflow string
+	Name     string
+	Samples  []OutcomeSample // oldest first
+}
+
+// recordOutcomes appends the finished checks of one commit to the outcome
+// history. A rerun of the same commit shows up as a new sample with a later
+// attempt or completion time. Called under lock.
+func (m *Memory) recordOutcomes(repo, branch, sha string, checks []github.Check, now time.Time) {
+	if sha == "" {
+		return
+	}
+	for _, check := range checks {
+		if github.ClassifyCheck(check.Status, check.Conclusion) == "running" {
+			continue
+		}
+		key := historyKey{repo, check.Workflow, check.Name}
+		samples := m.outcomes[key]
+		if hasOutcome(samples, sha, check) {
+			continue
+		}
+		samples = append(samples, OutcomeSample{
+			Branch:      branch,
+			CommitSHA:   sha,
+			RunAttempt:  check.RunAttempt,
+			Conclusion:  check.Conclusion,
+			CompletedAt: check.CompletedAt,
+			SeenAt:      now,
+		})
+		if len(samples) > HistoryLimit {
+			samples = samples[len(samples)-HistoryLimit:]
+		}
+		m.outcomes[key] = samples
+	}
+}
+
+func hasOutcome(samples []OutcomeSample, sha string, check github.Check) bool {
+	for i := len(samples) - 1; i >= 0; i-- {
+		s := samples[i]
+		if s.CommitSHA == sha && s.RunAttempt == check.RunAttempt && s.Conclusion == check.Conclusion && s.CompletedAt.Equal(check.CompletedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Memory) recordBranchOutcomes(branchChecks []github.BranchCheck, now time.Time) {
+	for _, bc := range branchChecks {
+		m.recordOutcomes(bc.Repository, bc.Branch, bc.CommitSHA, bc.Checks, now)
+	}
+}
+
+func (m *Memory) recordPROutcomes(prs []github.PullRequest, now time.Time) {
+	for _, pr := range prs {
+		m.recordOutcomes(pr.Repository, pr.HeadBranch, pr.HeadSHA, pr.Checks, now)
+	}
+}
+
+// CheckOutcomes returns copies of the outcome histories of repo's checks,
+// ordered by workflow and check name.
+func (m *Memory) CheckOutcomes(repo string) []CheckOutcomes {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var out []CheckOutcomes
+	for key, samples := range m.outcomes {
+		if key.repo != repo {
+			continue
+		}
+		out = append(out, CheckOutcomes{
+			Workflow: key.workflow,
+			Name:     key.name,
+			Samples:  append([]OutcomeSample(nil), samples...),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Workflow != out[j].Workflow {
+			return out[i].Workflow < out[j].Workflow
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
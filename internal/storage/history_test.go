@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+func finishedCheck(name string, started time.Time, d time.Duration) github.Check {
+	return github.Check{Name: name, Workflow: "CI", Status: "completed", Conclusion: "success", StartedAt: started, CompletedAt: started.Add(d)}
+}
+
+func TestCheckHistory(t *testing.T) {
+	s := New()
+	t0 := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	run := func(sha string, started time.Time, d time.Duration) []github.BranchCheck {
+		return []github.BranchCheck{{
+			Repository: "eccodes",
+			Branch:     "develop",
+			CommitSHA:  sha,
+			Checks: []github.Check{
+				finishedCheck("build", started, d),
+				{Name: "lint", Workflow: "CI", Status: "in_progress", StartedAt: started},
+			},
+		}}
+	}
+
+	s.MergeBranchChecks(run("a", t0, 10*time.Minute), nil, []string{"eccodes"})
+	s.MergeBranchChecks(run("a", t0, 10*time.Minute), nil, []string{"eccodes"}) // same run fetched again
+	s.MergeBranchChecks(run("b", t0.Add(time.Hour), 12*time.Minute), nil, []string{"eccodes"})
+
+	history := s.CheckHistory("eccodes")
+	if len(history) != 1 || history[0].Name != "build" || history[0].Workflow != "CI" {
+		t.Fatalf("history = %+v, want only the finished build check", history)
+	}
+	samples := history[0].Samples
+	if len(samples) != 2 || samples[0].Duration != 10*time.Minute || samples[1].Duration != 12*time.Minute || samples[1].CommitSHA != "b" {
+		t.Errorf("samples = %+v, want 10m then 12m without duplicates", samples)
+	}
+
+	// Returned samples are copies.
+	samples[0].Duration = 0
+	if s.CheckHistory("eccodes")[0].Samples[0].Duration != 10*time.Minute {
+		t.Error("CheckHistory returned shared samples")
+	}
+
+	s.RetainRepos([]string{"atlas"})
+	if got := s.CheckHistory("eccodes"); len(got) != 0 {
+		t.Errorf("history after RetainRepos = %+v, want none", got)
+	}
+}
+
+func TestCheckHistoryLimit(t *testing.T) {
+	s := New()
+	t0 := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i := range HistoryLimit + 10 {
+		started := t0.Add(time.Duration(i) * time.Hour)
+		s.SetBranchChecks([]github.BranchCheck{{
+			Repository: "eccodes",
+			CommitSHA:  started.Format(time.RFC3339),
+			Checks:     []github.Check{finishedCheck("build", started, time.Duration(i+1)*time.Second)},
+		}})
+	}
+	samples := s.CheckHistory("eccodes")[0].Samples
+	if len(samples) != HistoryLimit || samples[0].Duration != 11*time.Second {
+		t.Errorf("kept %d samples starting at %v, want the latest %d", len(samples), samples[0].Duration, HistoryLimit)
+	}
+}
+
+func TestCheckOutcomes(t *testing.T) {
+	s := New()
+	check := func(conclusion string, attempt int) github.Check {
+		return github.Check{Name: "build", Workflow: "CI", Status: "completed", Conclusion: conclusion, RunAttempt: attempt}
+	}
+	branch := func(sha string, checks ...github.Check) []github.BranchCheck {
+		return []github.BranchCheck{{Repository: "eccodes", Branch: "develop", CommitSHA: sha, Checks: checks}}
+	}
+
+	s.MergeBranchChecks(branch("a", check("failure", 1), github.Check{Name: "lint", Status: "queued"}), nil, []string{"eccodes"})
+	s.MergeBranchChecks(branch("a", check("failure", 1)), nil, []string{"eccodes"}) // fetched again
+	s.MergeBranchChecks(branch("a", check("success", 2)), nil, []string{"eccodes"}) // rerun
+	s.SetPullRequests([]github.PullRequest{{Repository: "eccodes", HeadBranch: "feature", HeadSHA: "x", Checks: []github.Check{check("success", 1)}}})
+
+	got := s.CheckOutcomes("eccodes")
+	if len(got) != 1 || got[0].Name != "build" {
+		t.Fatalf("outcomes = %+v, want only the finished build check", got)
+	}
+	samples := got[0].Samples
+	if len(samples) != 3 {
+		t.Fatalf("samples = %+v, want failure, rerun success and PR success", samples)
+	}
+	if samples[0].Conclusion != "failure" || samples[1].Conclusion != "success" || samples[1].RunAttempt != 2 || samples[2].Branch != "feature" || samples[2].CommitSHA != "x" {
+		t.Errorf("samples = %+v", samples)
+	}
+
+	s.RetainRepos(nil)
+	if got := s.CheckOutcomes("eccodes"); len(got) != 0 {
+		t.Errorf("outcomes after RetainRepos = %+v, want none", got)
+	}
+}
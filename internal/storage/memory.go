@@ -19,17 +19,61 @@ type Memory struct {
 	branchChecks     []github.BranchCheck
 	branchChecksTime time.Time
 
+	// Scheduled workflows, see scheduled.go; fetched with the branch checks.
+	scheduled []github.ScheduledWorkflow
+
+	// Branch pair comparisons, see compare.go
+	comparisons     []github.BranchComparison
+	comparisonsTime time.Time
+
+	// Latest releases, tags and milestones, see releases.go
+	releases     []github.ReleaseInfo
+	releasesTime time.Time
+
+	// Milestones and project boards, see planning.go
+	milestones   []github.Milestone
+	boards       []github.ProjectBoard
+	planningTime time.Time
+
+	// Deployment environments, see deployments.go
+	environments    []github.Environment
+	deploymentsTime time.Time
+
 	// Per-repo last-success timestamps
-	issueRepoTimes map[string]time.Time
-	prRepoTimes    map[string]time.Time
-	checkRepoTimes map[string]time.Time
+	issueRepoTimes      map[string]time.Time
+	prRepoTimes         map[string]time.Time
+	checkRepoTimes      map[string]time.Time
+	branchRepoTimes     map[string]time.Time
+	releaseRepoTimes    map[string]time.Time
+	planningRepoTimes   map[string]time.Time
+	deploymentRepoTimes map[string]time.Time
+
+	// Check run durations and outcomes, see history.go
+	durations map[historyKey][]DurationSample
+	outcomes  map[historyKey][]OutcomeSample
+
+	// Changes between fetches and the last settled status of each branch,
+	// see events.go
+	events      []Event
+	buildStatus map[branchKey]string
+
+	// retained is the set of repos of the last RetainRepos; nil keeps
+	// every repo.
+	retained map[string]bool
 }
 
 func New() *Memory {
 	return &Memory{
-		issueRepoTimes: make(map[string]time.Time),
-		prRepoTimes:    make(map[string]time.Time),
-		checkRepoTimes: make(map[string]time.Time),
+		issueRepoTimes:      make(map[string]time.Time),
+		prRepoTimes:         make(map[string]time.Time),
+		checkRepoTimes:      make(map[string]time.Time),
+		branchRepoTimes:     make(map[string]time.Time),
+		releaseRepoTimes:    make(map[string]time.Time),
+		planningRepoTimes:   make(map[string]time.Time),
+		deploymentRepoTimes: make(map[string]time.Time),
+		durations:           make(map[historyKey][]DurationSample),
+		outcomes:            make(map[historyKey][]OutcomeSample),
+		buildStatus:         make(map[branchKey]string),
 	}
 }
 
@@ -53,6 +97,7 @@ func (m *Memory) SetPullRequests(prs []github.PullRequest) {
 	defer m.mu.Unlock()
 	m.pullRequests = deepCopyPullRequests(prs)
 	now := time.Now()
+	m.recordPROutcomes(prs, now)
 	m.prsTime = now
 	m.updateRepoTimes(m.prRepoTimes, repoNamesFromPRs(prs), now)
 }
@@ -68,6 +113,8 @@ func (m *Memory) SetBranchChecks(checks []github.BranchCheck) {
 	defer m.mu.Unlock()
 	m.branchChecks = deepCopyBranchChecks(checks)
 	now := time.Now()
+	m.recordDurations(checks)
+	m.recordBranchOutcomes(checks, now)
 	m.branchChecksTime = now
 	m.updateRepoTimes(m.checkRepoTimes, repoNamesFromChecks(checks), now)
 }
@@ -91,6 +138,10 @@ func (m *Memory) MergeIssues(issues []github.Issue, failedRepos, succeededRepos
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	issues = retainedItems(m, issues, func(i github.Issue) string { return i.Repository })
+	succeededRepos = m.retainedRepos(succeededRepos)
+
+	m.recordOpenedIssues(issues, time.Now())
 	failed := toSet(failedRepos)
 	merged := keepByRepo(m.issues, failed)
 	merged = append(merged, deepCopyIssues(issues)...)
@@ -111,10 +162,15 @@ func (m *Memory) MergePullRequests(prs []github.PullRequest, failedRepos, succee
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	prs = retainedItems(m, prs, func(pr github.PullRequest) string { return pr.Repository })
+	succeededRepos = m.retainedRepos(succeededRepos)
+
+	m.recordOpenedPRs(prs, time.Now())
 	failed := toSet(failedRepos)
 	merged := keepPRsByRepo(m.pullRequests, failed)
 	merged = append(merged, deepCopyPullRequests(prs)...)
 	m.pullRequests = merged
+	m.recordPROutcomes(prs, time.Now())
 
 	if len(succeededRepos) > 0 {
 		now := time.Now()
@@ -131,10 +187,16 @@ func (m *Memory) MergeBranchChecks(checks []github.BranchCheck, failedRepos, suc
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	checks = retainedItems(m, checks, func(bc github.BranchCheck) string { return bc.Repository })
+	succeededRepos = m.retainedRepos(succeededRepos)
+
 	failed := toSet(failedRepos)
 	merged := keepChecksByRepo(m.branchChecks, failed)
 	merged = append(merged, deepCopyBranchChecks(checks)...)
 	m.branchChecks = merged
+	m.recordDurations(checks)
+	m.recordBranchOutcomes(checks, time.Now())
+	m.recordBuildTransitions(checks, time.Now())
 
 	if len(succeededRepos) > 0 {
 		now := time.Now()
@@ -158,6 +220,14 @@ func (m *Memory) RepoFetchTimes(category string) map[string]time.Time {
 		src = m.prRepoTimes
 	case CategoryChecks:
 		src = m.checkRepoTimes
+	case CategoryBranches:
+		src = m.branchRepoTimes
+	case CategoryReleases:
+		src = m.releaseRepoTimes
+	case CategoryPlanning:
+		src = m.planningRepoTimes
+	case CategoryDeployments:
+		src = m.deploymentRepoTimes
 	default:
 		return make(map[string]time.Time)
 	}
@@ -169,6 +239,109 @@ func (m *Memory) RepoFetchTimes(category string) map[string]time.Time {
 	return dst
 }
 
+// RetainRepos drops issues, pull requests, branch checks, scheduled
+// workflows, branch comparisons, release info, milestones, deployment
+// environments, check duration and outcome history, events, build statuses
+// and per-repo timestamps for every repo not listed in repos.
+//
+// Later merges drop data and timestamps of repos not listed too, so a
+// fetch cycle that started before a config reload removed a repo cannot
+// bring it back.
+func (m *Memory) RetainRepos(repos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keep := toSet(repos)
+	m.retained = keep
+	m.issues = keepByRepo(m.issues, keep)
+	m.pullRequests = keepPRsByRepo(m.pullRequests, keep)
+	m.branchChecks = keepChecksByRepo(m.branchChecks, keep)
+	var scheduled []github.ScheduledWorkflow
+	for _, wf := range m.scheduled {
+		if keep[wf.Repository] {
+			scheduled = append(scheduled, wf)
+		}
+	}
+	m.scheduled = scheduled
+	var comparisons []github.BranchComparison
+	for _, cmp := range m.comparisons {
+		if keep[cmp.Repository] {
+			comparisons = append(comparisons, cmp)
+		}
+	}
+	m.comparisons = comparisons
+	var releases []github.ReleaseInfo
+	for _, info := range m.releases {
+		if keep[info.Repository] {
+			releases = append(releases, info)
+		}
+	}
+	m.releases = releases
+	var milestones []github.Milestone
+	for _, ms := range m.milestones {
+		if keep[ms.Repository] {
+			milestones = append(milestones, ms)
+		}
+	}
+	m.milestones = milestones
+	var environments []github.Environment
+	for _, env := range m.environments {
+		if keep[env.Repository] {
+			environments = append(environments, env)
+		}
+	}
+	m.environments = environments
+	for _, times := range []map[string]time.Time{m.issueRepoTimes, m.prRepoTimes, m.checkRepoTimes, m.branchRepoTimes, m.releaseRepoTimes, m.planningRepoTimes, m.deploymentRepoTimes} {
+		for name := range times {
+			if !keep[name] {
+				delete(times, name)
+			}
+		}
+	}
+	for key := range m.durations {
+		if !keep[key.repo] {
+			delete(m.durations, key)
+		}
+	}
+	for key := range m.outcomes {
+		if !keep[key.repo] {
+			delete(m.outcomes, key)
+		}
+	}
+	var events []Event
+	for _, e := range m.events {
+		if keep[e.Repository] {
+			events = append(events, e)
+		}
+	}
+	m.events = events
+	for key := range m.buildStatus {
+		if !keep[key.repo] {
+			delete(m.buildStatus, key)
+		}
+	}
+}
+
+// retainedItems returns the items whose repo is retained, see RetainRepos.
+// Called under lock.
+func retainedItems[T any](m *Memory, items []T, repoOf func(T) string) []T {
+	if m.retained == nil {
+		return items
+	}
+	var kept []T
+	for _, item := range items {
+		if m.retained[repoOf(item)] {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// retainedRepos is retainedItems for repo names. Called under lock.
+func (m *Memory) retainedRepos(repos []string) []string {
+	return retainedItems(m, repos, func(name string) string { return name })
+}
+
 // updateRepoTimes sets the timestamp for all repos present in the data.
 // Called under lock by Set* methods.
 func (m *Memory) updateRepoTimes(times map[string]time.Time, repos []string, now time.Time) {
@@ -244,6 +417,7 @@ func keepPRsByRepo(prs []github.PullRequest, keepSet map[string]bool) []github.P
 			cp.Labels = append([]github.Label(nil), pr.Labels...)
 			cp.Checks = append([]github.Check(nil), pr.Checks...)
 			cp.Reviewers = append([]github.Reviewer(nil), pr.Reviewers...)
+			cp.ClosingIssues = append([]github.IssueRef(nil), pr.ClosingIssues...)
 			kept = append(kept, cp)
 		}
 	}
@@ -280,6 +454,7 @@ func deepCopyPullRequests(src []github.PullRequest) []github.PullRequest {
 		dst[i].Labels = append([]github.Label(nil), pr.Labels...)
 		dst[i].Checks = append([]github.Check(nil), pr.Checks...)
 		dst[i].Reviewers = append([]github.Reviewer(nil), pr.Reviewers...)
+		dst[i].ClosingIssues = append([]github.IssueRef(nil), pr.ClosingIssues...)
 	}
 	return dst
 }
@@ -141,6 +141,30 @@ func TestDeepCopyPRReviewers(t *testing.T) {
 	}
 }
 
+func TestDeepCopyPRClosingIssues(t *testing.T) {
+	s := New()
+	s.SetPullRequests([]github.PullRequest{
+		{Repository: "r", ClosingIssues: []github.IssueRef{{Repository: "r", Number: 1}}},
+	})
+
+	got, _ := s.GetPullRequests()
+	got[0].ClosingIssues[0].Number = 99
+
+	internal, _ := s.GetPullRequests()
+	if internal[0].ClosingIssues[0].Number != 1 {
+		t.Errorf("inner closing issues slice was mutated: got %d, want 1", internal[0].ClosingIssues[0].Number)
+	}
+
+	// Data kept for a failed repo is copied too.
+	s.MergePullRequests(nil, []string{"r"}, nil)
+	got, _ = s.GetPullRequests()
+	got[0].ClosingIssues[0].Number = 99
+	internal, _ = s.GetPullRequests()
+	if internal[0].ClosingIssues[0].Number != 1 {
+		t.Errorf("closing issues kept for a failed repo were mutated: got %d, want 1", internal[0].ClosingIssues[0].Number)
+	}
+}
+
 func TestDeepCopyBranchCheckChecks(t *testing.T) {
 	s := New()
 	s.SetBranchChecks([]github.BranchCheck{
@@ -516,3 +540,62 @@ func TestConcurrentMergeAndGet(t *testing.T) {
 
 // Verify Memory satisfies Store interface at compile time.
 var _ Store = (*Memory)(nil)
+
+func TestRetainRepos(t *testing.T) {
+	s := New()
+	s.MergeIssues([]github.Issue{{Repository: "keep", Number: 1}, {Repository: "drop", Number: 2}}, nil, []string{"keep", "drop"})
+	s.MergePullRequests([]github.PullRequest{{Repository: "keep", Number: 3}, {Repository: "drop", Number: 4}}, nil, []string{"keep", "drop"})
+	s.MergeBranchChecks([]github.BranchCheck{{Repository: "keep", Branch: "main"}, {Repository: "drop", Branch: "main"}}, nil, []string{"keep", "drop"})
+
+	s.RetainRepos([]string{"keep"})
+
+	issues, _ := s.GetIssues()
+	if len(issues) != 1 || issues[0].Repository != "keep" {
+		t.Errorf("issues after RetainRepos = %v, want only keep", issues)
+	}
+	prs, _ := s.GetPullRequests()
+	if len(prs) != 1 || prs[0].Repository != "keep" {
+		t.Errorf("PRs after RetainRepos = %v, want only keep", prs)
+	}
+	checks, _ := s.GetBranchChecks()
+	if len(checks) != 1 || checks[0].Repository != "keep" {
+		t.Errorf("checks after RetainRepos = %v, want only keep", checks)
+	}
+	for _, cat := range []string{CategoryIssues, CategoryPRs, CategoryChecks} {
+		times := s.RepoFetchTimes(cat)
+		if _, ok := times["drop"]; ok {
+			t.Errorf("%s: timestamp for dropped repo should be removed", cat)
+		}
+		if _, ok := times["keep"]; !ok {
+			t.Errorf("%s: timestamp for kept repo should remain", cat)
+		}
+	}
+}
+
+func TestRetainReposDropsLaterMerges(t *testing.T) {
+	s := New()
+	s.RetainRepos([]string{"keep"})
+
+	// A fetch cycle that started before the reload still reports "drop".
+	s.MergeIssues([]github.Issue{{Repository: "keep", Number: 1}, {Repository: "drop", Number: 2}}, nil, []string{"keep", "drop"})
+	s.MergeReleaseInfo([]github.ReleaseInfo{{Repository: "drop"}}, nil, []string{"drop"})
+
+	if issues, _ := s.GetIssues(); len(issues) != 1 || issues[0].Repository != "keep" {
+		t.Errorf("issues = %v, want only keep", issues)
+	}
+	if infos, _ := s.GetReleaseInfo(); len(infos) != 0 {
+		t.Errorf("release info = %v, want none", infos)
+	}
+	for _, cat := range []string{CategoryIssues, CategoryReleases} {
+		if _, ok := s.RepoFetchTimes(cat)["drop"]; ok {
+			t.Errorf("%s: merge brought back the timestamp of a removed repo", cat)
+		}
+	}
+
+	// Adding the repo back on a later reload lets it in again.
+	s.RetainRepos([]string{"keep", "drop"})
+	s.MergeIssues([]github.Issue{{Repository: "drop", Number: 2}}, nil, []string{"drop"})
+	if _, ok := s.RepoFetchTimes(CategoryIssues)["drop"]; !ok {
+		t.Error("repo added back is not fetched")
+	}
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+// MergePlanning replaces the milestones of successfully fetched repos,
+// keeping those of repos in failedRepos, and replaces the project boards,
+// keeping the earlier version of any board that failed to fetch.
+func (m *Memory) MergePlanning(milestones []github.Milestone, boards []github.ProjectBoard, failedRepos, succeededRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	milestones = retainedItems(m, milestones, func(ms github.Milestone) string { return ms.Repository })
+	succeededRepos = m.retainedRepos(succeededRepos)
+
+	failed := toSet(failedRepos)
+	var merged []github.Milestone
+	for _, ms := range m.milestones {
+		if failed[ms.Repository] {
+			merged = append(merged, ms)
+		}
+	}
+	for _, ms := range milestones {
+		if !failed[ms.Repository] {
+			merged = append(merged, ms)
+		}
+	}
+	m.milestones = merged
+
+	previous := make(map[int]github.ProjectBoard, len(m.boards))
+	for _, b := range m.boards {
+		previous[b.Number] = b
+	}
+	mergedBoards := make([]github.ProjectBoard, 0, len(boards))
+	for _, b := range boards {
+		if old, ok := previous[b.Number]; ok && b.FetchFailed {
+			b = old
+		}
+		mergedBoards = append(mergedBoards, b)
+	}
+	m.boards = mergedBoards
+
+	now := time.Now()
+	m.planningTime = now
+	for _, name := range succeededRepos {
+		m.planningRepoTimes[name] = now
+	}
+}
+
+// GetPlanning returns copies of the milestones and project boards and when
+// they were last fetched. Board items are shared and never modified.
+func (m *Memory) GetPlanning() ([]github.Milestone, []github.ProjectBoard, time.Time) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]github.Milestone(nil), m.milestones...), append([]github.ProjectBoard(nil), m.boards...), m.planningTime
+}
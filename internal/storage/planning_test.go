@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+func TestMergePlanning(t *testing.T) {
+	s := New()
+	s.MergePlanning(
+		[]github.Milestone{{Repository: "eckit", Number: 1, Title: "1.29"}, {Repository: "fdb", Number: 2, Title: "5.14"}},
+		[]github.ProjectBoard{{Number: 7, Title: "Roadmap", Items: []github.ProjectItem{{Title: "Plan"}}}},
+		nil, []string{"eckit", "fdb"})
+
+	// fdb fails this time and so does the board.
+	s.MergePlanning(
+		[]github.Milestone{{Repository: "eckit", Number: 3, Title: "1.30"}},
+		[]github.ProjectBoard{{Number: 7, FetchFailed: true}, {Number: 8, Title: "Ops"}},
+		[]string{"fdb"}, []string{"eckit"})
+
+	milestones, boards, updated := s.GetPlanning()
+	if updated.IsZero() {
+		t.Error("expected a last update time")
+	}
+	titles := make(map[string]bool)
+	for _, ms := range milestones {
+		titles[ms.Title] = true
+	}
+	if len(milestones) != 2 || !titles["1.30"] || !titles["5.14"] {
+		t.Errorf("milestones = %+v, want eckit's replaced and fdb's kept", milestones)
+	}
+	if len(boards) != 2 || boards[0].Title != "Roadmap" || boards[0].FetchFailed || boards[1].Title != "Ops" {
+		t.Errorf("boards = %+v, want the earlier Roadmap kept and Ops added", boards)
+	}
+
+	s.RetainRepos([]string{"fdb"})
+	if milestones, _, _ := s.GetPlanning(); len(milestones) != 1 {
+		t.Errorf("after RetainRepos got %d milestones, want fdb's", len(milestones))
+	}
+	if _, ok := s.RepoFetchTimes(CategoryPlanning)["eckit"]; ok {
+		t.Error("RetainRepos should drop eckit's fetch time")
+	}
+}
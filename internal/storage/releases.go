@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+// MergeReleaseInfo replaces the release info of successfully fetched
+// repos. Repos in failedRepos keep their earlier info; a failed repo seen
+// for the first time gets its FetchFailed placeholder.
+func (m *Memory) MergeReleaseInfo(infos []github.ReleaseInfo, failedRepos, succeededRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos = retainedItems(m, infos, func(i github.ReleaseInfo) string { return i.Repository })
+	succeededRepos = m.retainedRepos(succeededRepos)
+
+	failed := toSet(failedRepos)
+	known := make(map[string]bool, len(m.releases))
+	var merged []github.ReleaseInfo
+	for _, info := range m.releases {
+		if failed[info.Repository] {
+			merged = append(merged, info)
+			known[info.Repository] = true
+		}
+	}
+	for _, info := range infos {
+		if known[info.Repository] {
+			continue
+		}
+		merged = append(merged, info)
+	}
+	m.releases = merged
+
+	if len(succeededRepos) > 0 {
+		now := time.Now()
+		m.releasesTime = now
+		for _, name := range succeededRepos {
+			m.releaseRepoTimes[name] = now
+		}
+	}
+}
+
+// GetReleaseInfo returns a copy of the release info and when it was last
+// fetched. The Release and Milestone pointers and the Releases slices are
+// shared; they are never modified after the fetch.
+func (m *Memory) GetReleaseInfo() ([]github.ReleaseInfo, time.Time) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]github.ReleaseInfo(nil), m.releases...), m.releasesTime
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+func TestMergeReleaseInfo(t *testing.T) {
+	s := New()
+	s.MergeReleaseInfo([]github.ReleaseInfo{
+		{Repository: "eckit", LatestTag: "1.28.0"},
+		{Repository: "fdb", LatestTag: "5.13.0"},
+	}, nil, []string{"eckit", "fdb"})
+
+	// fdb fails this time and atlas fails on its first fetch.
+	s.MergeReleaseInfo([]github.ReleaseInfo{
+		{Repository: "eckit", LatestTag: "1.29.0"},
+		{Repository: "fdb", FetchFailed: true},
+		{Repository: "atlas", FetchFailed: true},
+	}, []string{"fdb", "atlas"}, []string{"eckit"})
+
+	infos, updated := s.GetReleaseInfo()
+	if updated.IsZero() {
+		t.Error("expected a last update time")
+	}
+	got := make(map[string]github.ReleaseInfo)
+	for _, info := range infos {
+		got[info.Repository] = info
+	}
+	if len(infos) != 3 {
+		t.Fatalf("infos = %+v, want eckit, fdb and atlas once each", infos)
+	}
+	if got["eckit"].LatestTag != "1.29.0" {
+		t.Errorf("eckit = %+v, want replaced", got["eckit"])
+	}
+	if info := got["fdb"]; info.FetchFailed || info.LatestTag != "5.13.0" {
+		t.Errorf("fdb = %+v, want the earlier info kept", info)
+	}
+	if !got["atlas"].FetchFailed {
+		t.Errorf("atlas = %+v, want the failed placeholder", got["atlas"])
+	}
+
+	s.RetainRepos([]string{"fdb"})
+	if infos, _ := s.GetReleaseInfo(); len(infos) != 1 {
+		t.Errorf("after RetainRepos got %d infos, want fdb's", len(infos))
+	}
+	if _, ok := s.RepoFetchTimes(CategoryReleases)["eckit"]; ok {
+		t.Error("RetainRepos should drop eckit's fetch time")
+	}
+}
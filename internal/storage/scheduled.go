@@ -0,0 +1,53 @@
+package storage
+
+import "github.com/ozaq/ecmwf-dash/internal/github"
+
+// MergeScheduledWorkflows replaces the scheduled workflows with workflows,
+// keeping the previous entries of repos in failedRepos and of workflows
+// whose fetch failed this time.
+func (m *Memory) MergeScheduledWorkflows(workflows []github.ScheduledWorkflow, failedRepos []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workflows = retainedItems(m, workflows, func(wf github.ScheduledWorkflow) string { return wf.Repository })
+
+	type key struct{ repo, file string }
+	previous := make(map[key]github.ScheduledWorkflow, len(m.scheduled))
+	for _, wf := range m.scheduled {
+		previous[key{wf.Repository, wf.File}] = wf
+	}
+
+	failed := toSet(failedRepos)
+	var merged []github.ScheduledWorkflow
+	for _, wf := range m.scheduled {
+		if failed[wf.Repository] {
+			merged = append(merged, wf)
+		}
+	}
+	for _, wf := range workflows {
+		if failed[wf.Repository] {
+			continue
+		}
+		if old, ok := previous[key{wf.Repository, wf.File}]; ok && wf.FetchFailed {
+			wf = old
+		}
+		merged = append(merged, wf)
+	}
+	m.scheduled = deepCopyScheduled(merged)
+}
+
+// GetScheduledWorkflows returns copies of the scheduled workflows.
+func (m *Memory) GetScheduledWorkflows() []github.ScheduledWorkflow {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return deepCopyScheduled(m.scheduled)
+}
+
+func deepCopyScheduled(src []github.ScheduledWorkflow) []github.ScheduledWorkflow {
+	dst := make([]github.ScheduledWorkflow, len(src))
+	for i, wf := range src {
+		dst[i] = wf
+		dst[i].Runs = append([]github.WorkflowRun(nil), wf.Runs...)
+	}
+	return dst
+}
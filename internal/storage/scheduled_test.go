@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/github"
+)
+
+func TestMergeScheduledWorkflows(t *testing.T) {
+	s := New()
+	run := github.WorkflowRun{ID: 1, Status: "completed", Conclusion: "success"}
+	s.MergeScheduledWorkflows([]github.ScheduledWorkflow{
+		{Repository: "eccodes", File: "nightly.yml", Runs: []github.WorkflowRun{run}},
+		{Repository: "fdb", File: "stress.yml", Runs: []github.WorkflowRun{run}},
+		{Repository: "atlas", File: "nightly.yml", Runs: []github.WorkflowRun{run}},
+	}, nil)
+
+	// eccodes' workflow fails to fetch, fdb fails entirely, atlas drops its workflow.
+	s.MergeScheduledWorkflows([]github.ScheduledWorkflow{
+		{Repository: "eccodes", File: "nightly.yml", FetchFailed: true},
+		{Repository: "eccodes", File: "weekly.yml", FetchFailed: true},
+	}, []string{"fdb"})
+
+	got := make(map[string]github.ScheduledWorkflow)
+	for _, wf := range s.GetScheduledWorkflows() {
+		got[wf.Repository+"/"+wf.File] = wf
+	}
+	if len(got) != 3 {
+		t.Fatalf("workflows = %+v, want eccodes/nightly, eccodes/weekly and fdb/stress", got)
+	}
+	if wf := got["eccodes/nightly.yml"]; wf.FetchFailed || len(wf.Runs) != 1 {
+		t.Errorf("eccodes/nightly.yml = %+v, want the earlier runs kept", wf)
+	}
+	if wf := got["eccodes/weekly.yml"]; !wf.FetchFailed {
+		t.Errorf("eccodes/weekly.yml = %+v, want the failed entry without history", wf)
+	}
+	if wf := got["fdb/stress.yml"]; len(wf.Runs) != 1 {
+		t.Errorf("fdb/stress.yml = %+v, want kept for the failed repo", wf)
+	}
+
+	// Returned runs are copies.
+	s.GetScheduledWorkflows()[0].Runs[0].Conclusion = "failure"
+	for _, wf := range s.GetScheduledWorkflows() {
+		if len(wf.Runs) > 0 && wf.Runs[0].Conclusion != "success" {
+			t.Error("GetScheduledWorkflows returned shared runs")
+		}
+	}
+
+	s.RetainRepos([]string{"eccodes"})
+	if got := s.GetScheduledWorkflows(); len(got) != 2 {
+		t.Errorf("after RetainRepos got %d workflows, want eccodes' 2", len(got))
+	}
+}
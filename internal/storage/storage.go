@@ -11,6 +11,11 @@ const (
 	CategoryIssues = "issues"
 	CategoryPRs    = "prs"
 	CategoryChecks = "checks"
+
+	CategoryBranches    = "branches"
+	CategoryReleases    = "releases"
+	CategoryPlanning    = "planning"
+	CategoryDeployments = "deployments"
 )
 
 // Store defines the interface for data access. All consumers should depend on
@@ -30,6 +35,50 @@ type Store interface {
 	MergePullRequests(prs []github.PullRequest, failedRepos, succeededRepos []string)
 	MergeBranchChecks(checks []github.BranchCheck, failedRepos, succeededRepos []string)
 
-	// RepoFetchTimes returns per-repo last-success timestamps for a category ("issues"|"prs"|"checks").
+	// RepoFetchTimes returns per-repo last-success timestamps for a category ("issues"|"prs"|"checks"|"branches"|"releases"|"planning"|"deployments").
 	RepoFetchTimes(category string) map[string]time.Time
+
+	// MergeScheduledWorkflows replaces the scheduled workflow runs, keeping
+	// earlier data for failed repos and for workflows that failed to fetch.
+	MergeScheduledWorkflows(workflows []github.ScheduledWorkflow, failedRepos []string)
+	GetScheduledWorkflows() []github.ScheduledWorkflow
+
+	// MergeBranchComparisons replaces the branch comparisons like the other
+	// Merge methods, and also keeps earlier results for pairs that failed
+	// to fetch.
+	MergeBranchComparisons(comparisons []github.BranchComparison, failedRepos, succeededRepos []string)
+	GetBranchComparisons() ([]github.BranchComparison, time.Time)
+
+	// MergeReleaseInfo replaces the release info like the other Merge
+	// methods.
+	MergeReleaseInfo(infos []github.ReleaseInfo, failedRepos, succeededRepos []string)
+	GetReleaseInfo() ([]github.ReleaseInfo, time.Time)
+
+	// MergePlanning replaces the milestones like the other Merge methods
+	// and the project boards, keeping earlier boards that failed to fetch.
+	MergePlanning(milestones []github.Milestone, boards []github.ProjectBoard, failedRepos, succeededRepos []string)
+	GetPlanning() ([]github.Milestone, []github.ProjectBoard, time.Time)
+
+	// MergeDeployments replaces the deployment environments like the other
+	// Merge methods.
+	MergeDeployments(envs []github.Environment, failedRepos, succeededRepos []string)
+	GetDeployments() ([]github.Environment, time.Time)
+
+	// RecordMergedPullRequests adds events for pull requests merged since
+	// the last fetch; GetEvents returns the events noticed between fetches,
+	// newest first.
+	RecordMergedPullRequests(prs []github.PullRequest)
+	GetEvents() []Event
+
+	// CheckHistory returns the duration history of each check in repo.
+	CheckHistory(repo string) []CheckHistory
+
+	// CheckOutcomes returns the pass/fail history of each check in repo,
+	// from both branch and pull request checks.
+	CheckOutcomes(repo string) []CheckOutcomes
+
+	// RetainRepos drops all data and timestamps for repos not in repos,
+	// and ignores them in later merges until retained again. Used after a
+	// config reload removes repositories.
+	RetainRepos(repos []string)
 }
@@ -0,0 +1,166 @@
+// Package tracing wires OpenTelemetry into the dashboard: a global tracer
+// provider exporting over OTLP/HTTP, server spans for HTTP handlers and
+// client spans for outgoing GitHub API calls. With tracing disabled the
+// global no-op provider stays in place and instrumentation costs nothing.
+package tracing
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+)
+
+const instrumentationName = "github.com/ozaq/ecmwf-dash"
+
+// Tracer returns the dashboard's tracer from the current global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs a global tracer provider that batches spans to the OTLP/HTTP
+// endpoint in cfg. When cfg.Enabled is false it does nothing. The returned
+// shutdown function flushes pending spans and must be called on exit.
+func Setup(ctx context.Context, cfg config.TracingConfig, version string) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if strings.Contains(cfg.Endpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP exporter: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "ecmwf-dash"
+	}
+	ratio := cfg.SampleRatio
+	if ratio == 0 {
+		ratio = 1
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+// Handler wraps h so every request except static assets and /health gets a
+// server span. The span is named after the method until Routes renames it
+// after the matched route, so span names stay a bounded set.
+func Handler(h http.Handler) http.Handler {
+	return otelhttp.NewHandler(h, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return routeSpanName(r.Method, r.Pattern)
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/health" && !strings.HasPrefix(r.URL.Path, "/static/")
+		}),
+	)
+}
+
+// Routes wraps mux so the server span started by Handler is named after the
+// route pattern the request matches, such as "GET /repo/{name}", and
+// records it as http.route. The concrete path stays in the span's URL
+// attributes.
+func Routes(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(routeSpanName(r.Method, pattern))
+			span.SetAttributes(semconv.HTTPRoute(pattern))
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// routeSpanName names a server span after the method and the route
+// pattern, which may already start with a method; without a pattern it is
+// just the method.
+func routeSpanName(method, pattern string) string {
+	if pattern == "" || strings.Contains(pattern, " ") {
+		return cmp.Or(pattern, method)
+	}
+	return method + " " + pattern
+}
+
+// Transport wraps base so every outgoing request gets a client span named
+// "GitHub <method>". The org and repo of repository endpoints are recorded
+// as attributes, next to the URL otelhttp records.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(repoAttributes{base},
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "GitHub " + r.Method
+		}),
+	)
+}
+
+// repoAttributes adds org and repo attributes to the client span of
+// requests to /repos/{org}/{repo}/... endpoints.
+type repoAttributes struct {
+	base http.RoundTripper
+}
+
+func (t repoAttributes) RoundTrip(r *http.Request) (*http.Response, error) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 4)
+	if len(parts) >= 3 && parts[0] == "repos" {
+		trace.SpanFromContext(r.Context()).SetAttributes(
+			attribute.String("org", parts[1]),
+			attribute.String("repo", parts[2]),
+		)
+	}
+	return t.base.RoundTrip(r)
+}
+
+// StartRepoSpan starts a span covering all API calls for one repository in
+// a fetch of the given category.
+func StartRepoSpan(ctx context.Context, category, org, repo string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, "fetch "+category+" "+repo, trace.WithAttributes(
+		attribute.String("category", category),
+		attribute.String("org", org),
+		attribute.String("repo", repo),
+	))
+}
+
+// EndSpan records err (if any) on span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
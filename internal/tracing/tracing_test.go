@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ozaq/ecmwf-dash/internal/config"
+	"github.com/ozaq/ecmwf-dash/internal/tracing/tracingtest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestSetupDisabled(t *testing.T) {
+	before := otel.GetTracerProvider()
+	shutdown, err := Setup(context.Background(), config.TracingConfig{}, "test")
+	if err != nil {
+		t.Fatalf("Setup() error: %v", err)
+	}
+	if otel.GetTracerProvider() != before {
+		t.Error("disabled tracing should not replace the global provider")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error: %v", err)
+	}
+}
+
+func TestSetupEnabled(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	for _, endpoint := range []string{"localhost:4318", "http://localhost:4318/v1/traces"} {
+		shutdown, err := Setup(context.Background(), config.TracingConfig{Enabled: true, Endpoint: endpoint, Insecure: true}, "test")
+		if err != nil {
+			t.Fatalf("Setup(%q) error: %v", endpoint, err)
+		}
+		// No spans were recorded, so shutdown does not contact the collector.
+		if err := shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown() error: %v", err)
+		}
+	}
+}
+
+func TestHandlerSpans(t *testing.T) {
+	exp := tracingtest.Record(t)
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, path := range []string{"/builds", "/health", "/static/base.css"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span (health and static filtered), got %d", len(spans))
+	}
+	if spans[0].Name != "GET" {
+		t.Errorf("span name = %q, want %q before routing", spans[0].Name, "GET")
+	}
+}
+
+func TestRoutesNamesSpans(t *testing.T) {
+	exp := tracingtest.Record(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repo/{name}", func(w http.ResponseWriter, r *http.Request) {})
+	// Middleware between the two passes a copy of the request, as the
+	// logging middleware does, so the mux's pattern is not seen by Handler.
+	routes := Routes(mux)
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		routes.ServeHTTP(w, r.WithContext(r.Context()))
+	}))
+
+	for _, path := range []string{"/repo/eccodes", "/repo/atlas", "/no/such/page"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	for _, span := range spans[:2] {
+		if span.Name != "GET /repo/{name}" {
+			t.Errorf("span name = %q, want %q", span.Name, "GET /repo/{name}")
+		}
+	}
+	if spans[2].Name != "GET" {
+		t.Errorf("unmatched span name = %q, want %q", spans[2].Name, "GET")
+	}
+}
+
+func TestRepoSpanRecordsError(t *testing.T) {
+	exp := tracingtest.Record(t)
+
+	_, span := StartRepoSpan(context.Background(), "issues", "ecmwf", "eccodes")
+	EndSpan(span, context.DeadlineExceeded)
+
+	got := tracingtest.Find(exp, "fetch issues eccodes")
+	if got == nil {
+		t.Fatal("repo span not recorded")
+	}
+	if got.Status.Code != codes.Error {
+		t.Errorf("status = %v, want Error", got.Status.Code)
+	}
+	attrs := make(map[string]string)
+	for _, kv := range got.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["repo"] != "eccodes" || attrs["org"] != "ecmwf" || attrs["category"] != "issues" {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+}
@@ -0,0 +1,33 @@
+// Package tracingtest installs an in-memory span exporter for tests.
+package tracingtest
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Record installs a global tracer provider that synchronously exports to
+// the returned in-memory exporter, and restores a no-op provider when the
+// test ends. Tests using it must not run in parallel.
+func Record(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exp := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	return exp
+}
+
+// Find returns the first recorded span with the given name, or nil.
+func Find(exp *tracetest.InMemoryExporter, name string) *tracetest.SpanStub {
+	spans := exp.GetSpans()
+	for i := range spans {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package visibility decides which configured repositories a viewer may
+// see: public repositories are visible to everyone, private ones only to
+// viewers whose own GitHub token can read them.
+package visibility
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/auth"
+)
+
+// DefaultTTL is how long access decisions are cached.
+const DefaultTTL = 10 * time.Minute
+
+// RepoAccessor reports a token's view of a repository; *github.Client
+// implements it.
+type RepoAccessor interface {
+	RepoAccess(ctx context.Context, org, repo string) (private, readable bool, err error)
+}
+
+// Checker answers visibility questions, caching both which repositories are
+// private (looked up with the dashboard's own token) and each viewer's read
+// access to them (looked up with the viewer's token). Lookup failures hide
+// the repository and are not cached.
+type Checker struct {
+	server    RepoAccessor
+	newViewer func(token string) (RepoAccessor, error)
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	private   map[string]entry    // "org/repo" → private
+	access    map[accessKey]entry // → viewer can read
+	lastSweep time.Time
+}
+
+type entry struct {
+	value   bool
+	expires time.Time
+}
+
+type accessKey struct {
+	subject string
+	repo    string // "org/repo"
+}
+
+// New returns a Checker. server uses the dashboard's token; newViewer
+// builds a client for a viewer's token. A zero ttl means DefaultTTL.
+func New(server RepoAccessor, newViewer func(token string) (RepoAccessor, error), ttl time.Duration) *Checker {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	return &Checker{
+		server:    server,
+		newViewer: newViewer,
+		ttl:       ttl,
+		now:       time.Now,
+		private:   make(map[string]entry),
+		access:    make(map[accessKey]entry),
+	}
+}
+
+// Visible returns the subset of repos the request's viewer may see.
+// Requests without a signed-in user (kiosk screens) see every repository;
+// kiosk scoping is applied separately.
+func (c *Checker) Visible(ctx context.Context, org string, repos []string) map[string]bool {
+	visible := make(map[string]bool, len(repos))
+	user, signedIn := auth.UserFromContext(ctx)
+
+	var viewer RepoAccessor
+	for _, repo := range repos {
+		if !signedIn {
+			visible[repo] = true
+			continue
+		}
+		full := org + "/" + repo
+
+		private, ok := c.isPrivate(ctx, org, repo, full)
+		if !ok || !private {
+			visible[repo] = ok
+			continue
+		}
+
+		key := accessKey{subject: user.Subject, repo: full}
+		if e, hit := cachedValue(c, c.access, key); hit {
+			visible[repo] = e
+			continue
+		}
+		if user.GitHubToken == "" {
+			continue // not linked or declined: private repos stay hidden
+		}
+		if viewer == nil {
+			var err error
+			if viewer, err = c.newViewer(user.GitHubToken); err != nil {
+				slog.Warn("creating viewer GitHub client failed", "sub", user.Subject, "error", err)
+				return visible
+			}
+		}
+		_, readable, err := viewer.RepoAccess(ctx, org, repo)
+		if err != nil {
+			slog.Warn("checking viewer repository access failed", "sub", user.Subject, "repo", repo, "error", err)
+			continue
+		}
+		storeValue(c, c.access, key, readable)
+		visible[repo] = readable
+	}
+	return visible
+}
+
+// Public returns the subset of repos that are public, for viewers who must
+// not see private repositories whatever their credentials, such as feed
+// readers using a kiosk token.
+func (c *Checker) Public(ctx context.Context, org string, repos []string) map[string]bool {
+	public := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		private, ok := c.isPrivate(ctx, org, repo, org+"/"+repo)
+		public[repo] = ok && !private
+	}
+	return public
+}
+
+// isPrivate reports whether org/repo is private; ok is false if that could
+// not be determined, in which case the repository must be hidden.
+func (c *Checker) isPrivate(ctx context.Context, org, repo, full string) (private, ok bool) {
+	if e, hit := cachedValue(c, c.private, full); hit {
+		return e, true
+	}
+	private, _, err := c.server.RepoAccess(ctx, org, repo)
+	if err != nil {
+		slog.Warn("checking repository visibility failed", "repo", repo, "error", err)
+		return false, false
+	}
+	storeValue(c, c.private, full, private)
+	return private, true
+}
+
+func cachedValue[K comparable](c *Checker, m map[K]entry, key K) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := m[key]
+	if !ok || c.now().After(e.expires) {
+		return false, false
+	}
+	return e.value, true
+}
+
+func storeValue[K comparable](c *Checker, m map[K]entry, key K, value bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	m[key] = entry{value: value, expires: now.Add(c.ttl)}
+
+	// Drop expired viewer entries now and then, so users who stopped
+	// visiting do not accumulate.
+	if now.Sub(c.lastSweep) > c.ttl {
+		for k, e := range c.access {
+			if now.After(e.expires) {
+				delete(c.access, k)
+			}
+		}
+		c.lastSweep = now
+	}
+}
@@ -0,0 +1,144 @@
+package visibility
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ozaq/ecmwf-dash/internal/auth"
+)
+
+// fakeAccessor answers from a map of readable repos and records calls.
+type fakeAccessor struct {
+	mu       sync.Mutex
+	private  map[string]bool
+	readable map[string]bool
+	err      error
+	calls    int
+}
+
+func (f *fakeAccessor) RepoAccess(_ context.Context, org, repo string) (bool, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return false, false, f.err
+	}
+	return f.private[repo], f.readable[repo], nil
+}
+
+func newTestChecker(server *fakeAccessor, viewers map[string]*fakeAccessor) *Checker {
+	return New(server, func(token string) (RepoAccessor, error) {
+		v, ok := viewers[token]
+		if !ok {
+			return nil, errors.New("unknown token")
+		}
+		return v, nil
+	}, time.Minute)
+}
+
+var repos = []string{"eccodes", "mars-server", "atlas"}
+
+func newServer() *fakeAccessor {
+	return &fakeAccessor{
+		private:  map[string]bool{"mars-server": true},
+		readable: map[string]bool{"eccodes": true, "mars-server": true, "atlas": true},
+	}
+}
+
+func viewerCtx(subject, token string) context.Context {
+	return auth.WithUser(context.Background(), auth.User{Subject: subject, GitHubToken: token})
+}
+
+func TestVisible(t *testing.T) {
+	alice := &fakeAccessor{readable: map[string]bool{"eccodes": true, "mars-server": true, "atlas": true}}
+	bob := &fakeAccessor{readable: map[string]bool{"eccodes": true, "atlas": true}}
+	c := newTestChecker(newServer(), map[string]*fakeAccessor{"alice-token": alice, "bob-token": bob})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want map[string]bool
+	}{
+		{"member", viewerCtx("alice", "alice-token"), map[string]bool{"eccodes": true, "mars-server": true, "atlas": true}},
+		{"non-member", viewerCtx("bob", "bob-token"), map[string]bool{"eccodes": true, "atlas": true}},
+		{"not linked", viewerCtx("carol", ""), map[string]bool{"eccodes": true, "atlas": true}},
+		{"kiosk", context.Background(), map[string]bool{"eccodes": true, "mars-server": true, "atlas": true}},
+	}
+	for _, tt := range tests {
+		got := c.Visible(tt.ctx, "ecmwf", repos)
+		for _, repo := range repos {
+			if got[repo] != tt.want[repo] {
+				t.Errorf("%s: visible[%s] = %v, want %v", tt.name, repo, got[repo], tt.want[repo])
+			}
+		}
+	}
+
+	// Only private repos are checked with the viewer's token.
+	if alice.calls != 1 || bob.calls != 1 {
+		t.Errorf("viewer calls = %d, %d; want 1 each", alice.calls, bob.calls)
+	}
+}
+
+func TestVisibleCaches(t *testing.T) {
+	srv := newServer()
+	alice := &fakeAccessor{readable: map[string]bool{"mars-server": true}}
+	c := newTestChecker(srv, map[string]*fakeAccessor{"alice-token": alice})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	ctx := viewerCtx("alice", "alice-token")
+	c.Visible(ctx, "ecmwf", repos)
+	c.Visible(ctx, "ecmwf", repos)
+	if srv.calls != len(repos) || alice.calls != 1 {
+		t.Fatalf("calls = server %d, viewer %d; want %d and 1", srv.calls, alice.calls, len(repos))
+	}
+
+	// Access revoked on GitHub shows up once the cache expires.
+	alice.readable = nil
+	now = now.Add(2 * time.Minute)
+	if got := c.Visible(ctx, "ecmwf", repos); got["mars-server"] {
+		t.Error("mars-server still visible after TTL expired and access was revoked")
+	}
+}
+
+func TestVisibleFailsClosed(t *testing.T) {
+	srv := newServer()
+	srv.err = errors.New("rate limited")
+	c := newTestChecker(srv, nil)
+
+	got := c.Visible(viewerCtx("alice", "alice-token"), "ecmwf", repos)
+	if got["eccodes"] || got["mars-server"] || got["atlas"] {
+		t.Errorf("visible = %v, want nothing when visibility cannot be determined", got)
+	}
+
+	// Errors are not cached.
+	srv.err = nil
+	if got := c.Visible(viewerCtx("alice", "alice-token"), "ecmwf", repos); !got["eccodes"] {
+		t.Error("public repo hidden after the error cleared")
+	}
+}
+
+func TestPublic(t *testing.T) {
+	srv := newServer()
+	c := newTestChecker(srv, nil)
+
+	// Signed in or not, private repositories are left out.
+	for _, ctx := range []context.Context{context.Background(), viewerCtx("alice", "alice-token")} {
+		got := c.Public(ctx, "ecmwf", repos)
+		if !got["eccodes"] || got["mars-server"] || !got["atlas"] {
+			t.Errorf("public = %v, want eccodes and atlas", got)
+		}
+	}
+	if srv.calls != len(repos) {
+		t.Errorf("server calls = %d, want %d", srv.calls, len(repos))
+	}
+
+	srv.err = errors.New("rate limited")
+	c = newTestChecker(srv, nil)
+	if got := c.Public(context.Background(), "ecmwf", repos); got["eccodes"] {
+		t.Error("public repo shown when visibility cannot be determined")
+	}
+}
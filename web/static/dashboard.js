@@ -111,9 +111,9 @@
         row.setAttribute('aria-expanded', expanded);
     });
 
-    // Repo filter — auto-submit on change (CSP-compliant, no inline handler)
+    // Repo and PR filters — auto-submit on change (CSP-compliant, no inline handler)
     document.addEventListener('change', function(e) {
-        if (e.target.id !== 'repo-filter') return;
+        if (e.target.id !== 'repo-filter' && e.target.id !== 'pr-filter') return;
         var form = e.target.closest('form');
         if (form) form.submit();
     });